@@ -17,12 +17,16 @@ type Config struct {
 	Downloads   DownloadsConfig   `toml:"downloads"`
 	Plex        PlexConfig        `toml:"plex"`
 	Sort        SortConfig        `toml:"sort"`
+	Seeding     []SeedingRule     `toml:"seeding"`
 	Sources     []SourceConfig    `toml:"sources"`
+	Feeds       []FeedConfig      `toml:"feeds"`
+	Quality     []QualityProfile  `toml:"quality"`
+	Searches    []SavedSearch     `toml:"searches"`
 }
 
 // SortConfig holds user's preferred sort settings for each tab
 type SortConfig struct {
-	// Search results: 0=name, 1=size, 2=seeds, 3=leech, 4=health
+	// Search results: 0=name, 1=size, 2=seeds, 3=leech, 4=health, 5=age, 6=score
 	SearchCol int  `toml:"search_col"`
 	SearchAsc bool `toml:"search_asc"`
 
@@ -35,12 +39,206 @@ type SortConfig struct {
 	CompletedAsc bool `toml:"completed_asc"`
 }
 
+// Source types
+const (
+	SourceTypeGeneric = "generic" // HTML scraping with heuristics (default)
+	SourceTypeTorznab = "torznab" // Torznab/Newznab indexer API
+)
+
 // SourceConfig holds a custom torrent source
 type SourceConfig struct {
-	Name    string `toml:"name"`
-	URL     string `toml:"url"`
-	Enabled bool   `toml:"enabled"`
-	Warning string `toml:"warning,omitempty"` // Non-empty if source has issues
+	Name       string      `toml:"name"`
+	URL        string      `toml:"url"`
+	Type       string      `toml:"type,omitempty"` // generic (default) or torznab
+	Enabled    bool        `toml:"enabled"`
+	Warning    string      `toml:"warning,omitempty"`    // Non-empty if source has issues
+	APIKey     string      `toml:"api_key,omitempty"`    // Torznab API key
+	Categories []int       `toml:"categories,omitempty"` // Torznab category IDs to search (empty = all)
+	Defaults   AddDefaults `toml:"defaults,omitempty"`
+
+	// Profile replaces the generic scraper's heuristics for this source
+	Profile *ScrapeProfile `toml:"profile,omitempty"`
+}
+
+// ScrapeProfile describes exactly how to search a site and read its result
+// rows. Field selectors are evaluated within each row.
+//
+// Example:
+//
+//	[sources.profile]
+//	search_url = "https://example.local/search?q={query}&page={page}"
+//	row = "table.results tbody tr"
+//	name = { selector = "td.name a" }
+//	size = { selector = "td.size" }
+//	seeders = { selector = "td.seeds" }
+//	leechers = { selector = "td.leeches" }
+//	magnet = { selector = "a[href^='magnet:']", attr = "href" }
+//	link = { selector = "td.name a", attr = "href" }
+//	pages = 2
+type ScrapeProfile struct {
+	// Search URL with a {query} placeholder; {page} (from 1) or {page0}
+	// (from 0) select the result page
+	SearchURL string        `toml:"search_url"`
+	Row       string        `toml:"row"` // Selector of one result row
+	Name      FieldSelector `toml:"name,omitempty"`
+	Size      FieldSelector `toml:"size,omitempty"`
+	Seeders   FieldSelector `toml:"seeders,omitempty"`
+	Leechers  FieldSelector `toml:"leechers,omitempty"`
+	Magnet    FieldSelector `toml:"magnet,omitempty"`
+	Link      FieldSelector `toml:"link,omitempty"`      // Detail page
+	Published FieldSelector `toml:"published,omitempty"` // Upload date, absolute or "3 days ago"
+	Category  FieldSelector `toml:"category,omitempty"`
+	Uploader  FieldSelector `toml:"uploader,omitempty"`
+	NextPage  string        `toml:"next_page,omitempty"` // Selector of the next page link, instead of {page}
+	Pages     int           `toml:"pages,omitzero"`      // Result pages to fetch (default 1)
+}
+
+// FieldSelector locates one value within a result row; an empty one leaves
+// the field to the scraper's defaults. Without a selector the row itself is
+// read, and with a regex its first group (or whole match) is taken from the
+// text or attribute.
+type FieldSelector struct {
+	Selector string `toml:"selector,omitempty"`
+	Attr     string `toml:"attr,omitempty"` // Attribute to read instead of the text
+	Regex    string `toml:"regex,omitempty"`
+}
+
+// AddDefaults holds the values prefilled in the "add with options" modal
+// for torrents from a source. Zero values mean "use qBittorrent's default".
+type AddDefaults struct {
+	SavePath           string   `toml:"save_path,omitempty"` // Empty = downloads.path
+	Category           string   `toml:"category,omitempty"`
+	Tags               []string `toml:"tags,omitempty"`
+	Paused             bool     `toml:"paused,omitempty"`
+	SkipChecking       bool     `toml:"skip_checking,omitempty"`
+	Sequential         bool     `toml:"sequential,omitempty"`
+	FirstLastPiecePrio bool     `toml:"first_last_piece_prio,omitempty"`
+	ContentLayout      string   `toml:"content_layout,omitempty"` // Original, Subfolder or NoSubfolder
+	DownloadLimitKiB   int64    `toml:"download_limit_kib,omitzero"`
+	UploadLimitKiB     int64    `toml:"upload_limit_kib,omitzero"`
+	RatioLimit         float64  `toml:"ratio_limit,omitzero"`
+	SeedingTimeLimit   int      `toml:"seeding_time_limit,omitzero"` // Minutes
+}
+
+// Actions a seeding rule takes once its goal is reached
+const (
+	SeedingActionStop        = "stop"         // Pause the torrent (default)
+	SeedingActionRemove      = "remove"       // Remove the torrent, keep its files
+	SeedingActionRemoveFiles = "remove_files" // Remove the torrent and its files
+)
+
+// SeedingRule is a share ratio and/or seeding time goal for torrents.
+// A rule with a Hash applies to that torrent, one with a Category to every
+// torrent in it, and one with neither to all torrents. The most specific
+// matching rule wins.
+//
+// Example:
+//
+//	[[seeding]]
+//	category = "tv"
+//	ratio = 2.0
+//	seeding_days = 14
+//
+//	[[seeding]]
+//	category = "movies"
+//	ratio = 1.0
+//	action = "remove"
+//	not_in_plex = true
+type SeedingRule struct {
+	Hash        string  `toml:"hash,omitempty"`
+	Category    string  `toml:"category,omitempty"`
+	Ratio       float64 `toml:"ratio,omitzero"`        // Share ratio goal (0 = none)
+	SeedingDays float64 `toml:"seeding_days,omitzero"` // Seeding time goal (0 = none)
+	RequireAll  bool    `toml:"require_all,omitempty"` // Both goals must be met (default: either)
+	Action      string  `toml:"action,omitempty"`      // stop, remove or remove_files
+	NotInPlex   bool    `toml:"not_in_plex,omitempty"` // Only act on content outside the Plex libraries
+}
+
+// FeedConfig is an RSS or Atom feed (e.g. a Torznab RSS URL) polled for
+// new torrents. Items matching one of its rules are added automatically.
+//
+// Example:
+//
+//	[[feeds]]
+//	name = "ShowRSS"
+//	url = "https://showrss.info/user/1234.rss"
+//	enabled = true
+//	interval_minutes = 30
+//
+//	[[feeds.rules]]
+//	name = "The Expanse"
+//	enabled = true
+//	include = "^The\\.Expanse"
+//	exclude = "2160p|HDR"
+//	episodes = "S06E01+"
+//	category = "tv"
+type FeedConfig struct {
+	Name            string     `toml:"name"`
+	URL             string     `toml:"url"`
+	Enabled         bool       `toml:"enabled"`
+	IntervalMinutes int        `toml:"interval_minutes,omitzero"` // 0 = every 15 minutes
+	Rules           []FeedRule `toml:"rules,omitempty"`
+}
+
+// FeedRule selects the feed items to download. Every condition that is set
+// must hold; the first enabled rule matching an item adds it.
+type FeedRule struct {
+	Name      string `toml:"name"`
+	Enabled   bool   `toml:"enabled"`
+	Include   string `toml:"include,omitempty"`    // Regex the title must match (case-insensitive)
+	Exclude   string `toml:"exclude,omitempty"`    // Regex the title must not match
+	MinSizeMB int64  `toml:"min_size_mb,omitzero"` // 0 = no minimum
+	MaxSizeMB int64  `toml:"max_size_mb,omitzero"` // 0 = no maximum
+	Episodes  string `toml:"episodes,omitempty"`   // Episode filter, e.g. "S01E01+" or "S02, S03E01-S03E05"
+	Category  string `toml:"category,omitempty"`   // qBittorrent category for added torrents
+	SavePath  string `toml:"save_path,omitempty"`  // Empty = downloads.path
+	Paused    bool   `toml:"paused,omitempty"`     // Add torrents paused
+}
+
+// QualityProfile ranks search results by what their release names say.
+// Resolutions, sources and codecs are listed best first; results matching
+// a Reject term are never picked. The Default profile is active at start.
+//
+// Example:
+//
+//	[[quality]]
+//	name = "TV 1080p"
+//	default = true
+//	resolutions = ["1080p", "720p"]
+//	sources = ["WEB-DL", "WEBRip", "HDTV"]
+//	codecs = ["x265", "x264"]
+//	prefer = ["PROPER", "DDP5.1"]
+//	reject = ["CAM", "TS", "SCR", "HDR"]
+//	max_size_gb = 8
+//	min_seeders = 5
+type QualityProfile struct {
+	Name           string   `toml:"name"`
+	Default        bool     `toml:"default,omitempty"`
+	Resolutions    []string `toml:"resolutions,omitempty"`    // Best first, e.g. 2160p, 1080p
+	Sources        []string `toml:"sources,omitempty"`        // Best first, e.g. BluRay, WEB-DL, Remux
+	Codecs         []string `toml:"codecs,omitempty"`         // Best first, e.g. x265, x264
+	Prefer         []string `toml:"prefer,omitempty"`         // Tags or words worth a bonus, e.g. HDR10, Atmos
+	Reject         []string `toml:"reject,omitempty"`         // Tags or words ruling a result out, e.g. CAM, TS
+	MaxSizeGB      float64  `toml:"max_size_gb,omitzero"`     // Per episode for multi-episode files and packs; 0 = no maximum
+	SeasonEpisodes int      `toml:"season_episodes,omitzero"` // Episodes assumed per season of a pack; 0 = 10
+	MinSeeders     int      `toml:"min_seeders,omitzero"`     // 0 = any
+}
+
+// SavedSearch is a named search re-run in the background, pointing out
+// results its previous run didn't have. The query may carry filters.
+//
+// Example:
+//
+//	[[searches]]
+//	name = "Expanse 1080p"
+//	query = "the expanse res:1080p seeds:>=5"
+//	enabled = true
+//	interval_minutes = 120
+type SavedSearch struct {
+	Name            string `toml:"name"`
+	Query           string `toml:"query"`
+	Enabled         bool   `toml:"enabled"`                   // Re-run on its schedule
+	IntervalMinutes int    `toml:"interval_minutes,omitzero"` // 0 = every 60 minutes
 }
 
 // QBittorrentConfig holds qBittorrent Web API settings
@@ -86,6 +284,35 @@ type PlexConfig struct {
 	// UseSudo prefixes rsync commands with sudo for NAS mount permissions.
 	// Requires passwordless sudo for rsync: username ALL=(ALL) NOPASSWD: /usr/bin/rsync
 	UseSudo bool `toml:"use_sudo"`
+
+	// TransferMode is how files get into the libraries: hardlink, reflink,
+	// move, rsync or copy. Empty (or "auto") hardlinks when the library is
+	// on the same filesystem as the downloads, and copies otherwise.
+	TransferMode string `toml:"transfer_mode"`
+
+	// Verify checks each native copy against its source before it replaces
+	// anything in the library: xxhash, crc32c (both fast) or sha256. Empty
+	// skips it.
+	Verify string `toml:"verify"`
+
+	// MovieTemplate names movies in the movie library, e.g.
+	// "{title} ({year})/{title} ({year}) {edition} [{resolution}]{ext}".
+	// Empty keeps "{title} ({year}){ext}".
+	MovieTemplate string `toml:"movie_template"`
+
+	// TVTemplate names episodes in the TV library, e.g.
+	// "{show}/Season {season:02}/{show} - S{season:02}E{episode:02}{ext}".
+	// Empty keeps the original filename in the season folder.
+	TVTemplate string `toml:"tv_template"`
+
+	// AutoMove copies torrents into the libraries as soon as they complete,
+	// while they keep seeding. Torrents whose detection is less certain than
+	// AutoMoveConfidence wait for review in the Completed tab instead.
+	AutoMove bool `toml:"auto_move"`
+
+	// AutoMoveConfidence is the detection confidence (0.0-1.0) needed to
+	// move a torrent without asking.
+	AutoMoveConfidence float64 `toml:"auto_move_confidence"`
 }
 
 // Default returns the default configuration
@@ -113,6 +340,8 @@ func Default() Config {
 			TVLibrary:    "", // Must be configured by user
 			AutoDetect:   true,
 			UseSudo:      true, // Use sudo for NAS mounts by default
+
+			AutoMoveConfidence: 0.8,
 		},
 		Sort: SortConfig{
 			SearchCol:    2,     // Default: seeds (most seeders first)
@@ -170,6 +399,20 @@ func Save(cfg Config) error {
 	return toml.NewEncoder(f).Encode(cfg)
 }
 
+// WriteFileAtomic writes a state file, creating its directory. The data is
+// written to a temporary file that is then renamed over path, so a crash
+// can't leave a truncated file.
+func WriteFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
 // EnsureDownloadDir creates the download directory if it doesn't exist
 func EnsureDownloadDir(cfg Config) error {
 	return os.MkdirAll(cfg.Downloads.Path, 0755)
@@ -0,0 +1,146 @@
+package feeds
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/litescript/ls-torrent-tui/internal/release"
+)
+
+// Episode identifies a TV episode, or a whole season for season packs
+type Episode struct {
+	Show   string // Normalized show name, e.g. "the expanse"
+	Season int
+	Number int // 0 = season pack
+}
+
+var (
+	nonAlnumRe   = regexp.MustCompile(`[^a-z0-9]+`)
+	trailingYear = regexp.MustCompile(` (19|20)\d\d$`)
+)
+
+// ParseEpisode finds the season and episode in a release title. Both
+// S01E02 and 1x02 styles are recognised, as are season packs (S01,
+// "Season 1"). The title before them is taken as the show name.
+func ParseEpisode(title string) (Episode, bool) {
+	info := release.Parse(title)
+	if len(info.Seasons) == 0 {
+		return Episode{}, false
+	}
+	return Episode{
+		Show:   normalizeShow(info.Title),
+		Season: info.Season(),
+		Number: info.Episode(),
+	}, true
+}
+
+// normalizeShow lowercases a show name and drops punctuation and a
+// trailing year, so "Show.Name.2019." and "Show Name -" compare equal
+func normalizeShow(s string) string {
+	s = strings.TrimSpace(nonAlnumRe.ReplaceAllString(strings.ToLower(s), " "))
+	return trailingYear.ReplaceAllString(s, "")
+}
+
+// Key identifies the episode for de-duplication across releases, so the
+// same episode in another quality or from another group isn't added twice
+func (e Episode) Key() string {
+	if e.Number == 0 {
+		return fmt.Sprintf("%s s%02d", e.Show, e.Season)
+	}
+	return fmt.Sprintf("%s s%02de%02d", e.Show, e.Season, e.Number)
+}
+
+// String formats the episode as S01E02 (or S01 for a season pack)
+func (e Episode) String() string {
+	if e.Number == 0 {
+		return fmt.Sprintf("S%02d", e.Season)
+	}
+	return fmt.Sprintf("S%02dE%02d", e.Season, e.Number)
+}
+
+// position orders episodes across seasons. A season pack sorts as the
+// first episode of its season.
+func (e Episode) position() int {
+	return e.Season*1000 + max(e.Number, 1)
+}
+
+// EpisodeFilter restricts a rule to some episodes. It is a comma separated
+// list of terms:
+//
+//	S02E05     that episode only
+//	S02        every episode of season 2
+//	S01E05+    S01E05 and everything after it, in any later season too
+//	S02+       season 2 onwards
+//	S01E03-S01E08, S01-S03   inclusive ranges
+//
+// An empty filter matches every item, including ones without an episode.
+type EpisodeFilter []episodeRange
+
+type episodeRange struct {
+	from, to int // Episode positions, inclusive
+}
+
+var episodeTermRe = regexp.MustCompile(`^S(\d{1,2})(?:E(\d{1,3}))?$`)
+
+// ParseEpisodeFilter parses the episode filter syntax described on EpisodeFilter
+func ParseEpisodeFilter(s string) (EpisodeFilter, error) {
+	var f EpisodeFilter
+	for _, term := range strings.Split(s, ",") {
+		term = strings.ToUpper(strings.ReplaceAll(term, " ", ""))
+		if term == "" {
+			continue
+		}
+
+		var r episodeRange
+		var err error
+		switch {
+		case strings.HasSuffix(term, "+"):
+			r.from, _, err = parseEpisodeTerm(strings.TrimSuffix(term, "+"))
+			r.to = math.MaxInt
+		case strings.Contains(term, "-"):
+			start, end, _ := strings.Cut(term, "-")
+			if r.from, _, err = parseEpisodeTerm(start); err == nil {
+				_, r.to, err = parseEpisodeTerm(end)
+			}
+		default:
+			r.from, r.to, err = parseEpisodeTerm(term)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.to < r.from {
+			return nil, fmt.Errorf("episode range %q ends before it starts", term)
+		}
+		f = append(f, r)
+	}
+	return f, nil
+}
+
+// parseEpisodeTerm parses S01E02 or S01 into the first and last position
+// it covers
+func parseEpisodeTerm(term string) (from, to int, err error) {
+	m := episodeTermRe.FindStringSubmatch(term)
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid episode %q (want e.g. S01E02, S01 or S01E02+)", term)
+	}
+	season, _ := strconv.Atoi(m[1])
+	if m[2] == "" {
+		return season * 1000, season*1000 + 999, nil
+	}
+	ep, _ := strconv.Atoi(m[2])
+	return season*1000 + ep, season*1000 + ep, nil
+}
+
+// Match reports whether the filter allows the episode
+func (f EpisodeFilter) Match(e Episode) bool {
+	pos := e.position()
+	for _, r := range f {
+		if pos >= r.from && pos <= r.to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+// Package feeds polls RSS and Atom feeds and adds the items matching
+// user-defined rules to qBittorrent. A persistent History keeps track of
+// what was added, so items and episodes are only downloaded once.
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// DefaultInterval is how often a feed is polled when it doesn't say
+const DefaultInterval = 15 * time.Minute
+
+// MinInterval keeps feeds from being hammered
+const MinInterval = 5 * time.Minute
+
+// Interval returns how often a feed should be polled
+func Interval(feed config.FeedConfig) time.Duration {
+	if feed.IntervalMinutes <= 0 {
+		return DefaultInterval
+	}
+	return max(time.Duration(feed.IntervalMinutes)*time.Minute, MinInterval)
+}
+
+// Rule is a compiled config.FeedRule
+type Rule struct {
+	config.FeedRule
+	include  *regexp.Regexp
+	exclude  *regexp.Regexp
+	episodes EpisodeFilter
+}
+
+// NewRule compiles a rule's patterns and episode filter
+func NewRule(r config.FeedRule) (*Rule, error) {
+	rule := &Rule{FeedRule: r}
+	var err error
+	if r.Include != "" {
+		if rule.include, err = regexp.Compile("(?i)" + r.Include); err != nil {
+			return nil, fmt.Errorf("include: %w", err)
+		}
+	}
+	if r.Exclude != "" {
+		if rule.exclude, err = regexp.Compile("(?i)" + r.Exclude); err != nil {
+			return nil, fmt.Errorf("exclude: %w", err)
+		}
+	}
+	if rule.episodes, err = ParseEpisodeFilter(r.Episodes); err != nil {
+		return nil, err
+	}
+	if r.MaxSizeMB > 0 && r.MinSizeMB > r.MaxSizeMB {
+		return nil, fmt.Errorf("minimum size is above the maximum")
+	}
+	return rule, nil
+}
+
+// Match reports whether an item satisfies every condition of the rule.
+// Items of unknown size don't match a rule with a size range.
+func (r *Rule) Match(item Item) bool {
+	if r.include != nil && !r.include.MatchString(item.Title) {
+		return false
+	}
+	if r.exclude != nil && r.exclude.MatchString(item.Title) {
+		return false
+	}
+	if r.MinSizeMB > 0 || r.MaxSizeMB > 0 {
+		mb := item.Size >> 20
+		if item.Size <= 0 || mb < r.MinSizeMB || (r.MaxSizeMB > 0 && mb > r.MaxSizeMB) {
+			return false
+		}
+	}
+	if len(r.episodes) > 0 {
+		ep, ok := ParseEpisode(item.Title)
+		if !ok || !r.episodes.Match(ep) {
+			return false
+		}
+	}
+	return true
+}
+
+// Status describes what a rule does with a feed item
+type Status int
+
+const (
+	StatusNoMatch   Status = iota // The rule doesn't match
+	StatusMatch                   // Would be added on the next poll
+	StatusAdded                   // Already added
+	StatusDuplicate               // Episode already added from another release
+)
+
+// Match is a feed item to add, with the rule that selected it
+type Match struct {
+	Item       Item
+	Rule       config.FeedRule
+	EpisodeKey string // Episode.Key(), "" if the title has no episode
+}
+
+// Plan returns the items of a feed to add: the first enabled rule matching
+// an item selects it. Items already added are skipped, and so are episodes
+// that were downloaded before or appear more than once in the feed, so
+// only the first release of an episode is taken.
+func Plan(feed config.FeedConfig, items []Item, h *History) ([]Match, error) {
+	var rules []*Rule
+	for _, r := range feed.Rules {
+		if !r.Enabled {
+			continue
+		}
+		rule, err := NewRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	var matches []Match
+	claimed := make(map[string]bool)
+	for _, item := range items {
+		if h.IsSeen(feed.URL, item.GUID) {
+			continue
+		}
+		for _, rule := range rules {
+			if !rule.Match(item) {
+				continue
+			}
+			key := episodeKey(item)
+			if key != "" && (claimed[key] || h.HasEpisode(key)) {
+				break
+			}
+			if key != "" {
+				claimed[key] = true
+			}
+			matches = append(matches, Match{Item: item, Rule: rule.FeedRule, EpisodeKey: key})
+			break
+		}
+	}
+	return matches, nil
+}
+
+// Preview reports what a single rule does with each item of a feed,
+// ignoring the feed's other rules and whether the rule is enabled
+func Preview(feedURL string, rule config.FeedRule, items []Item, h *History) ([]Status, error) {
+	r, err := NewRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(items))
+	claimed := make(map[string]bool)
+	for i, item := range items {
+		if !r.Match(item) {
+			continue
+		}
+		key := episodeKey(item)
+		switch {
+		case h.IsSeen(feedURL, item.GUID):
+			statuses[i] = StatusAdded
+		case key != "" && (claimed[key] || h.HasEpisode(key)):
+			statuses[i] = StatusDuplicate
+		default:
+			statuses[i] = StatusMatch
+		}
+		if key != "" {
+			claimed[key] = true
+		}
+	}
+	return statuses, nil
+}
+
+// episodeKey returns the de-duplication key of an item, "" for non-episodes
+func episodeKey(item Item) string {
+	if ep, ok := ParseEpisode(item.Title); ok && ep.Show != "" {
+		return ep.Key()
+	}
+	return ""
+}
+
+// Adder adds torrents to the client; *qbit.Client implements it
+type Adder interface {
+	AddURLs(ctx context.Context, urls []string, opts qbit.AddTorrentOptions) error
+}
+
+// Poller fetches feeds and adds their matching items
+type Poller struct {
+	Client   *http.Client
+	Adder    Adder
+	History  *History
+	SavePath string // Save path for rules without one
+}
+
+// Result is the outcome of polling one feed
+type Result struct {
+	Feed  *Feed    // Fetched feed, nil if fetching failed
+	Added []string // Titles of the added items
+	Err   error    // Fetch, rule or add error (adds continue past errors)
+}
+
+// Fetch downloads a feed without adding anything
+func (p *Poller) Fetch(ctx context.Context, feedURL string) (*Feed, error) {
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return Fetch(ctx, client, feedURL)
+}
+
+// Poll fetches a feed and adds the items its rules select. An item is
+// recorded in the history only once it was added, so failed adds are
+// retried on the next poll.
+func (p *Poller) Poll(ctx context.Context, feed config.FeedConfig) Result {
+	f, err := p.Fetch(ctx, feed.URL)
+	if err != nil {
+		return Result{Err: err}
+	}
+	res := Result{Feed: f}
+
+	matches, err := Plan(feed, f.Items, p.History)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	for _, m := range matches {
+		opts := qbit.AddTorrentOptions{
+			SavePath: m.Rule.SavePath,
+			Category: m.Rule.Category,
+			Paused:   m.Rule.Paused,
+		}
+		if opts.SavePath == "" {
+			opts.SavePath = p.SavePath
+		}
+		if err := p.Adder.AddURLs(ctx, []string{m.Item.Link}, opts); err != nil {
+			res.Err = fmt.Errorf("add %s: %w", m.Item.Title, err)
+			continue
+		}
+		p.History.Add(feed.URL, m.Item.GUID, m.EpisodeKey, time.Now())
+		res.Added = append(res.Added, m.Item.Title)
+	}
+
+	if len(res.Added) > 0 {
+		p.History.Prune(time.Now())
+		if err := p.History.Save(); err != nil && res.Err == nil {
+			res.Err = fmt.Errorf("save history: %w", err)
+		}
+	}
+	return res
+}
+
+// Describe summarises a rule's conditions for display
+func Describe(r config.FeedRule) string {
+	var parts []string
+	if r.Include != "" {
+		parts = append(parts, "/"+r.Include+"/")
+	}
+	if r.Exclude != "" {
+		parts = append(parts, "not /"+r.Exclude+"/")
+	}
+	if r.Episodes != "" {
+		parts = append(parts, r.Episodes)
+	}
+	switch {
+	case r.MinSizeMB > 0 && r.MaxSizeMB > 0:
+		parts = append(parts, fmt.Sprintf("%d-%d MB", r.MinSizeMB, r.MaxSizeMB))
+	case r.MinSizeMB > 0:
+		parts = append(parts, fmt.Sprintf(">= %d MB", r.MinSizeMB))
+	case r.MaxSizeMB > 0:
+		parts = append(parts, fmt.Sprintf("<= %d MB", r.MaxSizeMB))
+	}
+	if r.Category != "" {
+		parts = append(parts, "→ "+r.Category)
+	}
+	if len(parts) == 0 {
+		return "everything"
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,309 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+<channel>
+  <title>Indexer</title>
+  <item>
+    <title>Show.Name.S01E02.1080p.WEB.h264-GRP</title>
+    <guid>https://indexer/details/2</guid>
+    <link>https://indexer/dl/2.torrent</link>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate>
+    <enclosure url="https://indexer/dl/2.torrent" length="1500000000" type="application/x-bittorrent"/>
+    <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:abc"/>
+    <torznab:attr name="infohash" value="ABC"/>
+  </item>
+  <item>
+    <title>Other thing</title>
+    <link>https://example.com/page</link>
+    <size>2048</size>
+  </item>
+  <item>
+    <title>No link at all</title>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <entry>
+    <id>urn:1</id>
+    <title>Show Name 1x03 720p</title>
+    <updated>2024-05-01T10:00:00Z</updated>
+    <link rel="alternate" href="https://example.com/1"/>
+    <link rel="enclosure" href="https://example.com/1.torrent" length="700000000"/>
+  </entry>
+</feed>`
+
+func TestParseRSS(t *testing.T) {
+	feed, err := Parse(strings.NewReader(rssFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "Indexer" || len(feed.Items) != 2 {
+		t.Fatalf("feed = %+v", feed)
+	}
+
+	item := feed.Items[0]
+	if item.Link != "magnet:?xt=urn:btih:abc" || item.InfoHash != "abc" || item.Size != 1500000000 ||
+		item.GUID != "https://indexer/details/2" || item.Published.Year() != 2006 {
+		t.Errorf("item = %+v", item)
+	}
+	if item := feed.Items[1]; item.Link != "https://example.com/page" || item.GUID != item.Link || item.Size != 2048 {
+		t.Errorf("item without guid = %+v", item)
+	}
+}
+
+func TestParseAtom(t *testing.T) {
+	feed, err := Parse(strings.NewReader(atomFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "Atom Feed" || len(feed.Items) != 1 {
+		t.Fatalf("feed = %+v", feed)
+	}
+	item := feed.Items[0]
+	if item.Link != "https://example.com/1.torrent" || item.Size != 700000000 || item.GUID != "urn:1" ||
+		item.Published.IsZero() {
+		t.Errorf("item = %+v", item)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`<error code="100" description="Invalid API Key"/>`)); err == nil ||
+		!strings.Contains(err.Error(), "Invalid API Key") {
+		t.Errorf("error response: err = %v", err)
+	}
+	if _, err := Parse(strings.NewReader(`<html><body>hi</body></html>`)); err == nil {
+		t.Error("HTML page parsed as a feed")
+	}
+}
+
+func TestParseEpisode(t *testing.T) {
+	tests := []struct {
+		title string
+		want  Episode
+		ok    bool
+	}{
+		{"Show.Name.S01E02.1080p", Episode{"show name", 1, 2}, true},
+		{"Show Name - S1E2 - Title", Episode{"show name", 1, 2}, true},
+		{"Show.Name.2019.S03E10.720p", Episode{"show name", 3, 10}, true},
+		{"Show Name 2x05 HDTV", Episode{"show name", 2, 5}, true},
+		{"Show.Name.S02.COMPLETE.1080p", Episode{"show name", 2, 0}, true},
+		{"Show Name Season 4 Complete", Episode{"show name", 4, 0}, true},
+		{"Some.Movie.2020.1080p.x264", Episode{}, false},
+		{"Movie 1920x1080", Episode{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseEpisode(tt.title)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseEpisode(%q) = %+v, %v, want %+v, %v", tt.title, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEpisodeFilter(t *testing.T) {
+	ep := func(s, e int) Episode { return Episode{Show: "x", Season: s, Number: e} }
+	tests := []struct {
+		filter string
+		ep     Episode
+		want   bool
+	}{
+		{"S01E05+", ep(1, 4), false},
+		{"S01E05+", ep(1, 5), true},
+		{"S01E05+", ep(3, 1), true},
+		{"S01E01+", ep(1, 0), true}, // Season pack of the first season
+		{"S01E05+", ep(1, 0), false},
+		{"S01E05+", ep(2, 0), true},
+		{"S02", ep(2, 13), true},
+		{"S02", ep(3, 1), false},
+		{"S02+", ep(5, 1), true},
+		{"s01e03-s01e05", ep(1, 5), true},
+		{"S01E03-S01E05", ep(1, 6), false},
+		{"S01-S02", ep(2, 9), true},
+		{"S01E01, S03E02", ep(3, 2), true},
+		{"S01E01, S03E02", ep(2, 1), false},
+	}
+	for _, tt := range tests {
+		f, err := ParseEpisodeFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseEpisodeFilter(%q): %v", tt.filter, err)
+		}
+		if got := f.Match(tt.ep); got != tt.want {
+			t.Errorf("%q.Match(%v) = %v, want %v", tt.filter, tt.ep, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"E05", "S01E05-S01E02", "S1E5++", "latest"} {
+		if _, err := ParseEpisodeFilter(bad); err == nil {
+			t.Errorf("ParseEpisodeFilter(%q) accepted", bad)
+		}
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	rule, err := NewRule(config.FeedRule{
+		Include:   `show\.name`,
+		Exclude:   "2160p",
+		MinSizeMB: 100,
+		MaxSizeMB: 2000,
+		Episodes:  "S01E02+",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		item Item
+		want bool
+	}{
+		{Item{Title: "Show.Name.S01E02.1080p", Size: 1 << 30}, true},
+		{Item{Title: "SHOW.NAME.S01E03.720p", Size: 500 << 20}, true},
+		{Item{Title: "Show.Name.S01E01.1080p", Size: 1 << 30}, false},  // Before S01E02
+		{Item{Title: "Show.Name.S01E02.2160p", Size: 1 << 30}, false},  // Excluded
+		{Item{Title: "Show.Name.S01E02.1080p", Size: 3 << 30}, false},  // Too big
+		{Item{Title: "Show.Name.S01E02.1080p", Size: 50 << 20}, false}, // Too small
+		{Item{Title: "Show.Name.S01E02.1080p"}, false},                 // Unknown size
+		{Item{Title: "Other.S01E02.1080p", Size: 1 << 30}, false},
+	}
+	for _, tt := range tests {
+		if got := rule.Match(tt.item); got != tt.want {
+			t.Errorf("Match(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+
+	if _, err := NewRule(config.FeedRule{Include: "("}); err == nil {
+		t.Error("invalid include regex accepted")
+	}
+}
+
+func TestPlanDedupesEpisodes(t *testing.T) {
+	h := &History{Seen: map[string]map[string]time.Time{}, Episodes: map[string]time.Time{}}
+	h.Add("feed", "seen", "", time.Now())
+	h.Add("feed", "old", "show name s01e01", time.Now())
+
+	feed := config.FeedConfig{URL: "feed", Rules: []config.FeedRule{
+		{Name: "off", Include: ".", Enabled: false},
+		{Name: "show", Include: "^Show", Enabled: true},
+	}}
+	items := []Item{
+		{GUID: "a", Title: "Show.Name.S01E02.1080p"},
+		{GUID: "b", Title: "Show.Name.S01E02.720p"},   // Same episode, other quality
+		{GUID: "c", Title: "Show Name S01E01 REPACK"}, // Downloaded before
+		{GUID: "seen", Title: "Show.Name.S01E03.1080p"},
+		{GUID: "d", Title: "Show.Name.S01E04.1080p"},
+		{GUID: "e", Title: "Unrelated"},
+	}
+
+	matches, err := Plan(feed, items, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Item.GUID)
+	}
+	if strings.Join(got, ",") != "a,d" {
+		t.Errorf("planned %v, want [a d]", got)
+	}
+	if matches[0].Rule.Name != "show" || matches[0].EpisodeKey != "show name s01e02" {
+		t.Errorf("match = %+v", matches[0])
+	}
+
+	statuses, err := Preview("feed", feed.Rules[1], items, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Status{StatusMatch, StatusDuplicate, StatusDuplicate, StatusAdded, StatusMatch, StatusNoMatch}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("preview[%d] = %v, want %v", i, statuses[i], want[i])
+		}
+	}
+}
+
+type fakeAdder struct {
+	urls []string
+	opts []qbit.AddTorrentOptions
+	fail string // URL that fails to add
+}
+
+func (a *fakeAdder) AddURLs(ctx context.Context, urls []string, opts qbit.AddTorrentOptions) error {
+	if urls[0] == a.fail {
+		return errors.New("add failed")
+	}
+	a.urls = append(a.urls, urls...)
+	a.opts = append(a.opts, opts)
+	return nil
+}
+
+func TestPollAddsAndRemembers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<rss><channel>
+			<item><title>Show.S01E01</title><guid>1</guid><link>magnet:?xt=urn:btih:1</link></item>
+			<item><title>Show.S01E02</title><guid>2</guid><link>magnet:?xt=urn:btih:2</link></item>
+		</channel></rss>`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adder := &fakeAdder{fail: "magnet:?xt=urn:btih:2"}
+	p := &Poller{Client: srv.Client(), Adder: adder, History: h, SavePath: "/dl"}
+	feed := config.FeedConfig{URL: srv.URL, Enabled: true, Rules: []config.FeedRule{
+		{Name: "show", Include: "^Show", Category: "tv", Enabled: true},
+	}}
+
+	res := p.Poll(context.Background(), feed)
+	if res.Err == nil || len(res.Added) != 1 || res.Added[0] != "Show.S01E01" {
+		t.Fatalf("first poll = %+v", res)
+	}
+	if adder.opts[0].SavePath != "/dl" || adder.opts[0].Category != "tv" {
+		t.Errorf("add options = %+v", adder.opts[0])
+	}
+
+	// The failed item is retried, the added one isn't
+	adder.fail = ""
+	res = p.Poll(context.Background(), feed)
+	if res.Err != nil || len(res.Added) != 1 || res.Added[0] != "Show.S01E02" {
+		t.Fatalf("second poll = %+v", res)
+	}
+
+	// History survives a reload
+	h2, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h2.IsSeen(srv.URL, "1") || !h2.IsSeen(srv.URL, "2") || !h2.HasEpisode("show s01e02") {
+		t.Errorf("reloaded history = %+v", h2)
+	}
+}
+
+func TestHistoryPrune(t *testing.T) {
+	now := time.Now()
+	h := &History{Seen: map[string]map[string]time.Time{}, Episodes: map[string]time.Time{}}
+	h.Add("feed", "old", "show s01e01", now.Add(-SeenRetention-time.Hour))
+	h.Add("feed", "new", "", now)
+	h.Prune(now)
+	if h.IsSeen("feed", "old") || !h.IsSeen("feed", "new") || !h.HasEpisode("show s01e01") {
+		t.Errorf("after prune: %+v", h)
+	}
+}
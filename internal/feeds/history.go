@@ -0,0 +1,127 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+)
+
+// SeenRetention is how long seen GUIDs are remembered. Feeds only carry
+// recent items, so older entries can't come back.
+const SeenRetention = 90 * 24 * time.Hour
+
+// History remembers which feed items were added and which episodes were
+// downloaded, so nothing is added twice across polls and restarts. It is
+// safe for concurrent use.
+type History struct {
+	path string
+
+	mu       sync.Mutex
+	Seen     map[string]map[string]time.Time `json:"seen"`     // Feed URL -> GUID -> added at
+	Episodes map[string]time.Time            `json:"episodes"` // Episode.Key() -> added at
+}
+
+// HistoryPath returns the path of the history file, next to the config file
+func HistoryPath() string {
+	return filepath.Join(filepath.Dir(config.ConfigPath()), "feeds-history.json")
+}
+
+// LoadHistory reads the history file at path. A missing file gives an
+// empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{
+		path:     path,
+		Seen:     make(map[string]map[string]time.Time),
+		Episodes: make(map[string]time.Time),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return h, err
+	}
+	if h.Seen == nil {
+		h.Seen = make(map[string]map[string]time.Time)
+	}
+	if h.Episodes == nil {
+		h.Episodes = make(map[string]time.Time)
+	}
+	return h, nil
+}
+
+// IsSeen reports whether an item of a feed was already added
+func (h *History) IsSeen(feedURL, guid string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.Seen[feedURL][guid]
+	return ok
+}
+
+// HasEpisode reports whether an episode was already downloaded
+func (h *History) HasEpisode(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.Episodes[key]
+	return ok
+}
+
+// Add records an added item, and its episode if it has one
+func (h *History) Add(feedURL, guid, episodeKey string, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Seen[feedURL] == nil {
+		h.Seen[feedURL] = make(map[string]time.Time)
+	}
+	h.Seen[feedURL][guid] = at
+	if episodeKey != "" {
+		h.Episodes[episodeKey] = at
+	}
+}
+
+// Forget drops the seen items of a feed, e.g. when it is removed
+func (h *History) Forget(feedURL string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.Seen, feedURL)
+}
+
+// Prune drops seen GUIDs older than SeenRetention. Episodes are kept, so a
+// re-released episode is still recognised.
+func (h *History) Prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for feedURL, guids := range h.Seen {
+		for guid, at := range guids {
+			if now.Sub(at) > SeenRetention {
+				delete(guids, guid)
+			}
+		}
+		if len(guids) == 0 {
+			delete(h.Seen, feedURL)
+		}
+	}
+}
+
+// Save writes the history back to its file
+func (h *History) Save() error {
+	h.mu.Lock()
+	data, err := json.MarshalIndent(h, "", "  ")
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if h.path == "" {
+		return nil
+	}
+	return config.WriteFileAtomic(h.path, data)
+}
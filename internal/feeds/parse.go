@@ -0,0 +1,240 @@
+package feeds
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feed is a parsed RSS or Atom feed
+type Feed struct {
+	Title string
+	Items []Item
+}
+
+// Item is a feed entry that points at a torrent
+type Item struct {
+	GUID      string // Unique ID; falls back to the link
+	Title     string
+	Link      string // Magnet link or .torrent URL
+	Size      int64  // Bytes (0 = unknown)
+	Published time.Time
+	InfoHash  string // Lowercase hex, if the feed reports it
+}
+
+// Fetch downloads and parses a feed
+func Fetch(ctx context.Context, client *http.Client, feedURL string) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return Parse(io.LimitReader(resp.Body, 16<<20))
+}
+
+// Parse reads an RSS 2.0, RSS 1.0 or Atom feed. Torznab attributes and the
+// ezRSS torrent namespace are understood. Items without a magnet link or
+// download URL are dropped.
+func Parse(r io.Reader) (*Feed, error) {
+	var doc feedDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid feed: %w", err)
+	}
+
+	feed := &Feed{Title: strings.TrimSpace(doc.ChannelTitle)}
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		if doc.XMLName.Local == "RDF" {
+			doc.Items = append(doc.Items, doc.RDFItems...)
+		}
+		for _, it := range doc.Items {
+			if item, ok := it.item(); ok {
+				feed.Items = append(feed.Items, item)
+			}
+		}
+	case "feed":
+		feed.Title = strings.TrimSpace(doc.Title)
+		for _, e := range doc.Entries {
+			if item, ok := e.item(); ok {
+				feed.Items = append(feed.Items, item)
+			}
+		}
+	case "error":
+		return nil, fmt.Errorf("%s (code %s)", doc.Description, doc.Code)
+	default:
+		return nil, fmt.Errorf("not an RSS or Atom feed (<%s>)", doc.XMLName.Local)
+	}
+	return feed, nil
+}
+
+// feedDoc decodes any of the supported root elements
+type feedDoc struct {
+	XMLName      xml.Name
+	ChannelTitle string      `xml:"channel>title"`
+	Items        []rssItem   `xml:"channel>item"`
+	RDFItems     []rssItem   `xml:"item"` // RSS 1.0 puts items next to the channel
+	Title        string      `xml:"title"`
+	Entries      []atomEntry `xml:"entry"`
+	// Torznab/Newznab <error code="..." description="..."/>
+	Code        string `xml:"code,attr"`
+	Description string `xml:"description,attr"`
+}
+
+type rssItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	Link      string `xml:"link"`
+	PubDate   string `xml:"pubDate"`
+	Size      int64  `xml:"size"`
+	Enclosure struct {
+		URL    string `xml:"url,attr"`
+		Length int64  `xml:"length,attr"`
+	} `xml:"enclosure"`
+	// ezRSS torrent namespace
+	MagnetURI     string `xml:"magnetURI"`
+	InfoHash      string `xml:"infoHash"`
+	ContentLength int64  `xml:"contentLength"`
+	// torznab:attr or newznab:attr, matched in any namespace
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+func (it rssItem) attr(name string) string {
+	for _, a := range it.Attrs {
+		if strings.EqualFold(a.Name, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (it rssItem) item() (Item, bool) {
+	item := Item{
+		Title:    strings.TrimSpace(it.Title),
+		InfoHash: strings.ToLower(strings.TrimSpace(firstNonEmpty(it.attr("infohash"), it.InfoHash))),
+	}
+
+	link := strings.TrimSpace(it.Link)
+	switch {
+	case strings.HasPrefix(it.attr("magneturl"), "magnet:"):
+		item.Link = it.attr("magneturl")
+	case strings.HasPrefix(it.MagnetURI, "magnet:"):
+		item.Link = strings.TrimSpace(it.MagnetURI)
+	case strings.HasPrefix(link, "magnet:"):
+		item.Link = link
+	case it.Enclosure.URL != "":
+		item.Link = it.Enclosure.URL
+	case strings.HasPrefix(link, "http"):
+		item.Link = link
+	case item.InfoHash != "":
+		item.Link = magnetFromHash(item.InfoHash, item.Title)
+	default:
+		return item, false
+	}
+
+	item.Size = it.Size
+	if n, err := strconv.ParseInt(it.attr("size"), 10, 64); err == nil {
+		item.Size = n
+	}
+	if item.Size <= 0 {
+		item.Size = max(it.ContentLength, it.Enclosure.Length)
+	}
+	item.Published = parseDate(it.PubDate)
+	item.GUID = firstNonEmpty(strings.TrimSpace(it.GUID), item.Link)
+	return item, true
+}
+
+type atomEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Links     []struct {
+		Href   string `xml:"href,attr"`
+		Rel    string `xml:"rel,attr"`
+		Type   string `xml:"type,attr"`
+		Length int64  `xml:"length,attr"`
+	} `xml:"link"`
+}
+
+func (e atomEntry) item() (Item, bool) {
+	item := Item{Title: strings.TrimSpace(e.Title)}
+
+	// Prefer a magnet, then an enclosure, then the alternate link
+	var alternate string
+	for _, l := range e.Links {
+		switch {
+		case strings.HasPrefix(l.Href, "magnet:"):
+			item.Link = l.Href
+		case l.Rel == "enclosure" && item.Link == "":
+			item.Link = l.Href
+			item.Size = l.Length
+		case alternate == "":
+			alternate = l.Href
+		}
+	}
+	if item.Link == "" {
+		if !strings.HasPrefix(alternate, "http") {
+			return item, false
+		}
+		item.Link = alternate
+	}
+
+	item.Published = parseDate(firstNonEmpty(e.Published, e.Updated))
+	item.GUID = firstNonEmpty(strings.TrimSpace(e.ID), item.Link)
+	return item, true
+}
+
+// dateLayouts are the date formats seen in the wild, RFC 822 variants first
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+// parseDate parses a feed date, returning the zero time if it can't
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func magnetFromHash(hash, name string) string {
+	return "magnet:?xt=urn:btih:" + hash + "&dn=" + url.QueryEscape(name)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+// Package pipeline keeps the state of the post-completion pipeline, which
+// copies torrents into the Plex libraries once they complete. Every torrent
+// it has seen is remembered, so nothing is processed twice across restarts.
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/plex"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// DefaultConfidence is the detection confidence needed to move a torrent
+// when the config doesn't set one
+const DefaultConfidence = 0.8
+
+// MinConfidence returns the detection confidence needed to move a torrent
+// without asking
+func MinConfidence(p config.PlexConfig) float64 {
+	if p.AutoMoveConfidence <= 0 {
+		return DefaultConfidence
+	}
+	return p.AutoMoveConfidence
+}
+
+// Status is where a torrent is in the pipeline
+type Status string
+
+const (
+	StatusBaseline  Status = "baseline"  // Already completed when the pipeline started
+	StatusMoving    Status = "moving"    // Being copied into a library
+	StatusMoved     Status = "moved"     // In a library
+	StatusReview    Status = "review"    // Waiting for the user to move it
+	StatusDismissed Status = "dismissed" // Taken out of review by the user
+)
+
+// Entry is a torrent the pipeline has seen
+type Entry struct {
+	Name   string    `json:"name"`
+	Status Status    `json:"status"`
+	Detail string    `json:"detail,omitempty"` // Destination once moved, why it needs review otherwise
+	At     time.Time `json:"at"`
+}
+
+// State is what the pipeline has done, kept in a JSON file. It is safe for
+// concurrent use.
+type State struct {
+	path string
+
+	mu       sync.Mutex
+	Started  bool              `json:"started"`  // Completed torrents were baselined
+	Torrents map[string]*Entry `json:"torrents"` // Hash -> entry
+}
+
+// StatePath returns the path of the state file, next to the config file
+func StatePath() string {
+	return filepath.Join(filepath.Dir(config.ConfigPath()), "pipeline.json")
+}
+
+// Load reads the state file at path. A missing file gives an empty state.
+// A move the app was closed during is put up for review, as it may not have
+// finished.
+func Load(path string) (*State, error) {
+	s := &State{path: path, Torrents: make(map[string]*Entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, err
+	}
+	if s.Torrents == nil {
+		s.Torrents = make(map[string]*Entry)
+	}
+	for _, e := range s.Torrents {
+		if e.Status == StatusMoving {
+			e.Status, e.Detail = StatusReview, "move interrupted"
+		}
+	}
+	return s, nil
+}
+
+// IsStarted reports whether the pipeline has baselined the completed
+// torrents
+func (s *State) IsStarted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Started
+}
+
+// Baseline starts the pipeline. Torrents completed before it are left
+// alone: only torrents completing from now on are moved.
+func (s *State) Baseline(completed []qbit.TorrentInfo, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range completed {
+		if _, ok := s.Torrents[t.Hash]; !ok {
+			s.Torrents[t.Hash] = &Entry{Name: t.Name, Status: StatusBaseline, At: at}
+		}
+	}
+	s.Started = true
+}
+
+// Next returns the first completed torrent the pipeline hasn't seen
+func (s *State) Next(completed []qbit.TorrentInfo) (qbit.TorrentInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range completed {
+		if _, ok := s.Torrents[t.Hash]; !ok {
+			return t, true
+		}
+	}
+	return qbit.TorrentInfo{}, false
+}
+
+// Set records where a torrent is in the pipeline
+func (s *State) Set(hash, name string, status Status, detail string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Torrents[hash] = &Entry{Name: name, Status: status, Detail: detail, At: at}
+}
+
+// Get returns a torrent's entry
+func (s *State) Get(hash string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.Torrents[hash]; ok {
+		return *e, true
+	}
+	return Entry{}, false
+}
+
+// InReview reports whether a torrent waits for review
+func (s *State) InReview(hash string) bool {
+	e, ok := s.Get(hash)
+	return ok && e.Status == StatusReview
+}
+
+// Dismiss takes a torrent out of review without moving it
+func (s *State) Dismiss(hash string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.Torrents[hash]; ok && e.Status == StatusReview {
+		e.Status, e.Detail, e.At = StatusDismissed, "", at
+	}
+}
+
+// Save writes the state back to its file
+func (s *State) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if s.path == "" {
+		return nil
+	}
+	return config.WriteFileAtomic(s.path, data)
+}
+
+// Decide reports whether a detection is certain enough to move without
+// asking, and if not, why
+func Decide(d plex.DetectionResult, minConfidence float64) (bool, string) {
+	switch {
+	case d.Type == plex.MediaTypeUnknown:
+		return false, "not recognized as a movie or show"
+	case d.Title == "":
+		return false, "no title found"
+	case d.Confidence < minConfidence:
+		return false, fmt.Sprintf("low confidence (%.0f%%)", d.Confidence*100)
+	}
+	return true, ""
+}
@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/plex"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		name   string
+		ok     bool
+		reason string
+	}{
+		{"The.Matrix.1999.1080p.BluRay.x264-GRP.mkv", true, ""},
+		{"The.Expanse.S03E05.1080p.WEB-DL.x264-GRP.mkv", true, ""},
+		{"Some Random Video.mkv", false, "not recognized"},
+	}
+	for _, tt := range tests {
+		d, _ := plex.Detect(tt.name)
+		ok, reason := Decide(d, DefaultConfidence)
+		if ok != tt.ok || !strings.HasPrefix(reason, tt.reason) {
+			t.Errorf("Decide(%q) = %v %q, want %v %q", tt.name, ok, reason, tt.ok, tt.reason)
+		}
+	}
+
+	d := plex.DetectionResult{Type: plex.MediaTypeMovie, Title: "Movie", Confidence: 0.5}
+	if ok, reason := Decide(d, DefaultConfidence); ok || reason != "low confidence (50%)" {
+		t.Errorf("Decide(50%%) = %v %q", ok, reason)
+	}
+	if ok, _ := Decide(d, 0.5); !ok {
+		t.Error("Decide refused a detection at the minimum confidence")
+	}
+	d.Title = ""
+	if ok, reason := Decide(d, 0); ok || reason != "no title found" {
+		t.Errorf("Decide(no title) = %v %q", ok, reason)
+	}
+}
+
+func TestMinConfidence(t *testing.T) {
+	if got := MinConfidence(config.PlexConfig{}); got != DefaultConfidence {
+		t.Errorf("MinConfidence(unset) = %v", got)
+	}
+	if got := MinConfidence(config.PlexConfig{AutoMoveConfidence: 0.6}); got != 0.6 {
+		t.Errorf("MinConfidence = %v, want 0.6", got)
+	}
+}
+
+func TestStatePersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	completed := []qbit.TorrentInfo{{Hash: "a", Name: "Old"}}
+	if s.IsStarted() {
+		t.Fatal("new state already started")
+	}
+	s.Baseline(completed, now)
+	if _, ok := s.Next(completed); ok {
+		t.Error("a torrent completed before the pipeline started is pending")
+	}
+
+	completed = append(completed,
+		qbit.TorrentInfo{Hash: "b", Name: "Moving"},
+		qbit.TorrentInfo{Hash: "c", Name: "Unsure"})
+	if next, ok := s.Next(completed); !ok || next.Hash != "b" {
+		t.Fatalf("Next = %q, %v, want b", next.Hash, ok)
+	}
+	s.Set("b", "Moving", StatusMoving, "", now)
+	s.Set("c", "Unsure", StatusReview, "low confidence (40%)", now)
+	if _, ok := s.Next(completed); ok {
+		t.Error("Next returned a torrent already processed")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsStarted() {
+		t.Error("started flag lost on reload")
+	}
+	// Closing the app mid-move leaves it for review
+	if e, _ := loaded.Get("b"); e.Status != StatusReview || e.Detail != "move interrupted" {
+		t.Errorf("interrupted move = %q %q", e.Status, e.Detail)
+	}
+	if !loaded.InReview("c") || loaded.InReview("a") {
+		t.Error("review state lost on reload")
+	}
+	loaded.Dismiss("c", now)
+	loaded.Dismiss("a", now)
+	if e, _ := loaded.Get("c"); e.Status != StatusDismissed {
+		t.Errorf("dismissed entry = %q", e.Status)
+	}
+	if e, _ := loaded.Get("a"); e.Status != StatusBaseline {
+		t.Errorf("Dismiss changed an entry not in review: %q", e.Status)
+	}
+}
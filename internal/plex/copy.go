@@ -0,0 +1,223 @@
+package plex
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Checksum is how a finished copy is verified against its source.
+type Checksum string
+
+const (
+	ChecksumNone   Checksum = ""
+	ChecksumXXHash Checksum = "xxhash" // Fastest, catches corruption
+	ChecksumCRC32C Checksum = "crc32c" // Fast, catches corruption
+	ChecksumSHA256 Checksum = "sha256" // Slower, cryptographic
+)
+
+// ErrChecksumMismatch indicates a copy differs from its source.
+var ErrChecksumMismatch = errors.New("copy doesn't match its source")
+
+// DefaultChunkSize is how much CopyFile copies at a time.
+const DefaultChunkSize = 4 << 20
+
+// resumeCheckSize is how much of the end of a partial copy is compared
+// with the source before resuming it.
+const resumeCheckSize = 1 << 20
+
+// ParseChecksum reads a checksum from the config; empty means none.
+func ParseChecksum(s string) (Checksum, error) {
+	switch c := Checksum(s); c {
+	case ChecksumNone, ChecksumXXHash, ChecksumCRC32C, ChecksumSHA256:
+		return c, nil
+	case "none":
+		return ChecksumNone, nil
+	}
+	return ChecksumNone, fmt.Errorf("unknown checksum %q (want xxhash, crc32c or sha256)", s)
+}
+
+// newHash returns a hash computing the checksum, nil for none.
+func (c Checksum) newHash() hash.Hash {
+	switch c {
+	case ChecksumXXHash:
+		return xxhash.New()
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case ChecksumSHA256:
+		return sha256.New()
+	}
+	return nil
+}
+
+// CopyOptions tunes CopyFile.
+type CopyOptions struct {
+	ChunkSize int                // Bytes copied at a time (default 4 MiB)
+	Verify    Checksum           // Check the finished copy against the source
+	Progress  func(copied int64) // Called after each chunk with the bytes in place so far
+}
+
+// CopyFile copies src to dst in chunks, keeping its permissions and
+// modification time. The copy is written to dst.part, synced to disk,
+// verified if asked and only then renamed over dst, so dst is never left
+// half-written. A partial file left by an interrupted or cancelled copy is
+// resumed if its end still matches the source.
+func CopyFile(ctx context.Context, src, dst string, opts CopyOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	part := dst + ".part"
+	offset := resumeOffset(in, part, info.Size())
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	out, err := os.OpenFile(part, flags, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if err := copyChunks(ctx, in, out, offset, opts); err != nil {
+		out.Close()
+		if errors.Is(err, ErrChecksumMismatch) {
+			os.Remove(part)
+		}
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chtimes(part, info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	if err := os.Rename(part, dst); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(dst))
+	return nil
+}
+
+// copyChunks copies in to out from offset on, then syncs out and checks it
+// against the source.
+func copyChunks(ctx context.Context, in, out *os.File, offset int64, opts CopyOptions) error {
+	h := opts.Verify.newHash()
+	if h != nil && offset > 0 {
+		// The resumed part was copied earlier, hash its source too
+		if _, err := io.Copy(h, io.NewSectionReader(in, 0, offset)); err != nil {
+			return err
+		}
+	}
+	if _, err := in.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	chunk := opts.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+	buf := make([]byte, chunk)
+	copied := offset
+	if opts.Progress != nil {
+		opts.Progress(copied)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, readErr := in.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			if h != nil {
+				h.Write(buf[:n])
+			}
+			copied += int64(n)
+			if opts.Progress != nil {
+				opts.Progress(copied)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+
+	if h == nil {
+		return nil
+	}
+	want := h.Sum(nil)
+	h.Reset()
+	written, err := os.Open(out.Name())
+	if err != nil {
+		return err
+	}
+	defer written.Close()
+	if _, err := io.Copy(h, written); err != nil {
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), want) {
+		return fmt.Errorf("%w (%s)", ErrChecksumMismatch, opts.Verify)
+	}
+	return nil
+}
+
+// resumeOffset returns how much of a partial copy can be kept: all of it
+// if it is no longer than the source and its end matches, nothing otherwise.
+func resumeOffset(in *os.File, part string, size int64) int64 {
+	f, err := os.Open(part)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 || info.Size() > size {
+		return 0
+	}
+
+	n := info.Size()
+	check := min(n, resumeCheckSize)
+	have, want := make([]byte, check), make([]byte, check)
+	if _, err := f.ReadAt(have, n-check); err != nil {
+		return 0
+	}
+	if _, err := in.ReadAt(want, n-check); err != nil {
+		return 0
+	}
+	if !bytes.Equal(have, want) {
+		return 0
+	}
+	return n
+}
+
+// syncDir flushes a directory entry to disk, where the platform allows.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
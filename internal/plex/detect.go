@@ -4,10 +4,10 @@ package plex
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
-	"regexp"
-	"strconv"
-	"strings"
+
+	"github.com/litescript/ls-torrent-tui/internal/release"
 )
 
 // MediaType represents the detected type of media content.
@@ -42,43 +42,36 @@ type DetectionResult struct {
 	Season     int     // Season number (TV) or 0
 	Episode    int     // Episode number (TV) or 0
 	Confidence float64 // 0.0-1.0 confidence score
-}
-
-// TV show patterns - check these first (more specific)
-var (
-	// S01E02, s01e02, S1E2
-	tvPatternSE = regexp.MustCompile(`(?i)[sS](\d{1,2})[eE](\d{1,2})`)
-	// 1x02, 01x02
-	tvPatternX = regexp.MustCompile(`(?i)(\d{1,2})x(\d{2})`)
-)
 
-// Movie year pattern - 4 digit year between 1900-2099
-var yearPattern = regexp.MustCompile(`(19\d{2}|20\d{2})`)
+	Release release.Info // Everything parsed from the name
+}
 
 // Detect analyzes a filename to determine media type.
-// Checks TV patterns first (more specific), then falls back to movie year detection.
+// Episodes and season packs are TV; otherwise a release year means a movie.
 func Detect(filename string) (DetectionResult, error) {
 	// Get just the filename without path
 	name := filepath.Base(filename)
-	// Remove extension for cleaner parsing
-	ext := filepath.Ext(name)
-	nameNoExt := strings.TrimSuffix(name, ext)
+	info := release.Parse(name)
 
-	// Try TV detection first (more specific patterns)
-	if result, ok := detectTV(nameNoExt); ok {
+	if result, ok := detectTV(info); ok {
 		return result, nil
 	}
-
-	// Try movie detection (year-based)
-	if result, ok := detectMovie(nameNoExt); ok {
-		return result, nil
+	if info.Year > 0 && info.Title != "" {
+		return DetectionResult{
+			Type:       MediaTypeMovie,
+			Title:      info.Title,
+			Year:       info.Year,
+			Confidence: min(info.Confidence.Title, info.Confidence.Year),
+			Release:    info,
+		}, nil
 	}
 
 	// Fallback: return unknown with cleaned title
 	return DetectionResult{
 		Type:       MediaTypeUnknown,
-		Title:      cleanTitle(nameNoExt),
+		Title:      info.Title,
 		Confidence: 0.1,
+		Release:    info,
 	}, ErrDetectionFailed
 }
 
@@ -96,92 +89,35 @@ func DetectFromPath(path string) (DetectionResult, error) {
 	return Detect(name)
 }
 
-// detectTV attempts to identify TV show patterns in the filename.
-func detectTV(name string) (DetectionResult, bool) {
-	// Try S##E## pattern first
-	if matches := tvPatternSE.FindStringSubmatch(name); matches != nil {
-		season, _ := strconv.Atoi(matches[1])
-		episode, _ := strconv.Atoi(matches[2])
-
-		// Extract show title (everything before the pattern)
-		idx := tvPatternSE.FindStringIndex(name)
-		titleRaw := name[:idx[0]]
-		title := cleanTitle(titleRaw)
-
-		return DetectionResult{
-			Type:       MediaTypeTV,
-			Title:      title,
-			Season:     season,
-			Episode:    episode,
-			Confidence: 0.9,
-		}, true
+// detectTV maps parsed episode numbering onto a season and episode.
+// Date-based episodes go in a season named after their year, and anime
+// numbered from the first episode in season 1, as Plex expects.
+func detectTV(info release.Info) (DetectionResult, bool) {
+	if info.Title == "" {
+		return DetectionResult{}, false
 	}
-
-	// Try ##x## pattern
-	if matches := tvPatternX.FindStringSubmatch(name); matches != nil {
-		season, _ := strconv.Atoi(matches[1])
-		episode, _ := strconv.Atoi(matches[2])
-
-		// Extract show title
-		idx := tvPatternX.FindStringIndex(name)
-		titleRaw := name[:idx[0]]
-		title := cleanTitle(titleRaw)
-
-		return DetectionResult{
-			Type:       MediaTypeTV,
-			Title:      title,
-			Season:     season,
-			Episode:    episode,
-			Confidence: 0.85,
-		}, true
+	result := DetectionResult{
+		Type:       MediaTypeTV,
+		Title:      info.Title,
+		Season:     info.Season(),
+		Episode:    info.Episode(),
+		Confidence: info.Confidence.Episode,
+		Release:    info,
 	}
-
-	return DetectionResult{}, false
-}
-
-// detectMovie attempts to identify movie patterns (year-based).
-func detectMovie(name string) (DetectionResult, bool) {
-	// Find year in filename
-	matches := yearPattern.FindStringSubmatch(name)
-	if matches == nil {
+	switch {
+	case len(info.Episodes) > 0 || len(info.Seasons) > 0:
+	case !info.AirDate.IsZero():
+		result.Season = info.AirDate.Year()
+	case len(info.Absolute) > 0:
+		result.Season = 1
+		result.Episode = info.Absolute[0]
+	default:
 		return DetectionResult{}, false
 	}
-
-	year, _ := strconv.Atoi(matches[1])
-
-	// Extract title (everything before the year)
-	idx := yearPattern.FindStringIndex(name)
-	titleRaw := name[:idx[0]]
-	title := cleanTitle(titleRaw)
-
-	// Validate: title should not be empty
-	if title == "" {
-		return DetectionResult{}, false
+	// A show's year is part of its folder name ("Show 2019"), as it was
+	// before titles were parsed
+	if info.Year > 0 {
+		result.Title = fmt.Sprintf("%s %d", info.Title, info.Year)
 	}
-
-	return DetectionResult{
-		Type:       MediaTypeMovie,
-		Title:      title,
-		Year:       year,
-		Confidence: 0.8,
-	}, true
-}
-
-// cleanTitle converts a raw filename segment into a clean title.
-// Replaces dots, underscores with spaces and trims whitespace.
-func cleanTitle(raw string) string {
-	// Replace dots and underscores with spaces
-	cleaned := strings.ReplaceAll(raw, ".", " ")
-	cleaned = strings.ReplaceAll(cleaned, "_", " ")
-
-	// Remove common release tags that might be left at the end
-	// (These often appear between title and year/episode)
-	// Also trim opening parenthesis/bracket that precedes year
-	cleaned = strings.TrimRight(cleaned, " -([{")
-
-	// Collapse multiple spaces
-	spacePattern := regexp.MustCompile(`\s+`)
-	cleaned = spacePattern.ReplaceAllString(cleaned, " ")
-
-	return strings.TrimSpace(cleaned)
+	return result, true
 }
@@ -21,36 +21,42 @@ var (
 	ErrDestinationExists = errors.New("destination already exists")
 	ErrPermissionDenied  = errors.New("permission denied")
 	ErrPathEscape        = errors.New("path escapes allowed directory")
+	ErrDuplicateName     = errors.New("files would get the same name")
 )
 
 // MoveConfig holds configuration for media file operations.
 type MoveConfig struct {
-	MovieLibraryPath string // Base path for movie library
-	TVLibraryPath    string // Base path for TV library
-	UseSudo          bool   // Use sudo for rsync operations
+	MovieLibraryPath string       // Base path for movie library
+	TVLibraryPath    string       // Base path for TV library
+	UseSudo          bool         // Use sudo for rsync operations
+	TransferMode     TransferMode // How files get into the library (auto by default)
+	Verify           Checksum     // Check native copies against their source
+	MovieTemplate    Template     // How movies are named (default if zero)
+	TVTemplate       Template     // How episodes are named (default if zero)
 }
 
 // MoveResult contains the outcome of a move operation.
 type MoveResult struct {
-	SourcePath      string   // First/main source file
-	DestinationPath string   // Destination directory (TV) or file (movie)
+	SourcePath      string // First/main source file
+	DestinationPath string // Destination directory (TV) or file (movie)
 	MediaType       MediaType
 	BytesMoved      int64
-	FilesMoved      int      // Number of video files moved (1 for movies, N for TV)
+	FilesMoved      int // Number of video files moved (1 for movies, N for TV)
 	Success         bool
 	Error           error
-	RemainingFiles  []string // Files left in source directory (for cleanup prompt)
-	SourceDir       string   // Source directory path (for cleanup)
+	RemainingFiles  []string     // Files left in source directory (for cleanup prompt)
+	SourceDir       string       // Source directory path (for cleanup)
+	Transfer        TransferMode // How the (first) video got there, never auto
 }
 
 // MoveProgress reports progress during a move operation.
 type MoveProgress struct {
 	BytesCopied int64
 	TotalBytes  int64
-	Percentage  float64   // Overall progress (0.0-1.0)
+	Percentage  float64 // Overall progress (0.0-1.0)
 	CurrentFile string
-	Rate        string    // Transfer rate (e.g., "10.5MB/s")
-	ETA         string    // Estimated time remaining (e.g., "0:01:23")
+	Rate        string // Transfer rate (e.g., "10.5MB/s")
+	ETA         string // Estimated time remaining (e.g., "0:01:23")
 	// TV multi-episode fields
 	EpisodeIndex    int     // Current episode index (1-based), 0 for movies
 	EpisodeTotal    int     // Total episodes, 0 for movies
@@ -128,17 +134,11 @@ func (m *Mover) moveMovie(
 	totalBytes := info.Size()
 
 	// Generate destination path
-	ext := filepath.Ext(mainVideo)
-	movieFilename, err := FormatMoviePath(MovieNaming{
-		Title:     detection.Title,
-		Year:      detection.Year,
-		Extension: ext,
-	})
+	destFile, err := m.moviePath(mainVideo, detection)
 	if err != nil {
-		return nil, fmt.Errorf("format movie path: %w", err)
+		return nil, err
 	}
-	destDir := m.config.MovieLibraryPath
-	destFile := filepath.Join(destDir, movieFilename)
+	destDir := filepath.Dir(destFile)
 
 	// Find subtitles
 	subtitles := FindSubtitles(sourcePath)
@@ -150,15 +150,21 @@ func (m *Mover) moveMovie(
 		}
 	}
 
-	// Copy video with progress
-	if err := m.rsyncWithProgress(ctx, mainVideo, destFile, totalBytes, progress); err != nil {
-		return nil, fmt.Errorf("copy video: %w", err)
+	// Transfer video with progress
+	transfer, err := m.transfer(ctx, mainVideo, destFile, fileProgress{
+		ch:         progress,
+		name:       filepath.Base(mainVideo),
+		fileBytes:  totalBytes,
+		totalBytes: totalBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s video: %w", transfer, err)
 	}
 
-	// Copy subtitles
+	// Transfer subtitles, named after the movie
 	for _, sub := range subtitles {
-		subDest := filepath.Join(destDir, filepath.Base(sub))
-		_ = m.rsyncFile(sub, subDest)
+		subDest := filepath.Join(destDir, subtitleName(filepath.Base(sub), filepath.Base(mainVideo), filepath.Base(destFile)))
+		_, _ = m.transfer(ctx, sub, subDest, fileProgress{})
 	}
 
 	// Find remaining files for cleanup
@@ -176,6 +182,7 @@ func (m *Mover) moveMovie(
 		Success:         true,
 		RemainingFiles:  remaining,
 		SourceDir:       sourceDir,
+		Transfer:        transfer,
 	}, nil
 }
 
@@ -203,31 +210,31 @@ func (m *Mover) moveTV(
 		}
 	}
 
+	// Name every episode first, so a template that gives two the same name
+	// fails before anything is moved
+	dests := make([]string, len(videos))
+	for i, video := range videos {
+		if dests[i], err = m.episodePath(video, detection); err != nil {
+			return nil, err
+		}
+	}
+	if err := uniqueNames(dests); err != nil {
+		return nil, err
+	}
+
 	// Track all moved files and subtitles for cleanup calculation
 	var allMovedVideos []string
 	var allMovedSubs []string
 	var destDir string // Will be set to last destination for result
+	var transfer TransferMode
 
 	// Move each video file
 	var bytesCopied int64
 	for i, video := range videos {
-		// Detect season from THIS video's filename (e.g., S03E16 -> season 3)
-		videoDetection, _ := DetectFromPath(video)
-		season := videoDetection.Season
-		if season == 0 {
-			season = detection.Season // Fallback to modal's detection
-		}
-
-		// Build destination: TV/<Show>/Season XX/<original filename>
-		tvDir, err := FormatTVPath(TVNaming{
-			ShowTitle: detection.Title, // Use show title from modal (user can edit)
-			Season:    season,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("format tv path: %w", err)
-		}
-		destDir = filepath.Join(m.config.TVLibraryPath, tvDir)
-		destFile := filepath.Join(destDir, filepath.Base(video))
+		// Season from THIS video's filename (e.g., S03E16 -> season 3),
+		// show title from modal (user can edit)
+		destFile := dests[i]
+		destDir = filepath.Dir(destFile)
 
 		// Create destination directory
 		if !m.config.UseSudo {
@@ -240,18 +247,30 @@ func (m *Mover) moveTV(
 		videoInfo, _ := os.Stat(video)
 		videoSize := videoInfo.Size()
 
-		// Copy video with progress (reports as part of total)
-		if err := m.rsyncWithProgressOffset(ctx, video, destFile, videoSize, totalBytes, bytesCopied, i+1, len(videos), progress); err != nil {
-			return nil, fmt.Errorf("copy %s: %w", filepath.Base(video), err)
+		// Transfer video with progress (reports as part of total)
+		used, err := m.transfer(ctx, video, destFile, fileProgress{
+			ch:           progress,
+			name:         filepath.Base(video),
+			fileBytes:    videoSize,
+			totalBytes:   totalBytes,
+			offset:       bytesCopied,
+			episodeIndex: i + 1,
+			episodeTotal: len(videos),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", used, filepath.Base(video), err)
+		}
+		if transfer == TransferAuto {
+			transfer = used
 		}
 		bytesCopied += videoSize
 		allMovedVideos = append(allMovedVideos, video)
 
-		// Find and copy matching subtitles for THIS episode
+		// Find and copy matching subtitles for THIS episode, named after it
 		subs := FindSubtitlesForVideo(sourceDir, video)
 		for _, sub := range subs {
-			subDest := filepath.Join(destDir, filepath.Base(sub))
-			_ = m.rsyncFile(sub, subDest)
+			subDest := filepath.Join(destDir, subtitleName(filepath.Base(sub), filepath.Base(video), filepath.Base(destFile)))
+			_, _ = m.transfer(ctx, sub, subDest, fileProgress{})
 			allMovedSubs = append(allMovedSubs, sub)
 		}
 
@@ -286,9 +305,62 @@ func (m *Mover) moveTV(
 		Success:         true,
 		RemainingFiles:  remaining,
 		SourceDir:       sourceDir,
+		Transfer:        transfer,
 	}, nil
 }
 
+// Destination returns where a move would put the movie, or the first
+// episode of a show, for previews.
+func (m *Mover) Destination(sourcePath string, detection DetectionResult) (string, error) {
+	switch detection.Type {
+	case MediaTypeMovie:
+		video, err := FindMainVideo(sourcePath)
+		if err != nil {
+			return "", err
+		}
+		return m.moviePath(video, detection)
+	case MediaTypeTV:
+		videos, err := FindAllVideos(sourcePath)
+		if err != nil {
+			return "", err
+		}
+		return m.episodePath(videos[0], detection)
+	default:
+		return "", fmt.Errorf("unknown media type")
+	}
+}
+
+// moviePath returns where a movie's video goes, named by the template.
+func (m *Mover) moviePath(video string, detection DetectionResult) (string, error) {
+	name, err := m.config.MovieTemplate.Movie(movieNaming(detection, filepath.Ext(video)))
+	if err != nil {
+		return "", fmt.Errorf("format movie path: %w", err)
+	}
+	return filepath.Join(m.config.MovieLibraryPath, filepath.FromSlash(name)), nil
+}
+
+// episodePath returns where an episode goes, named by the template.
+func (m *Mover) episodePath(video string, detection DetectionResult) (string, error) {
+	name, err := m.config.TVTemplate.TV(tvNaming(detection, filepath.Base(video)))
+	if err != nil {
+		return "", fmt.Errorf("format tv path: %w", err)
+	}
+	return filepath.Join(m.config.TVLibraryPath, filepath.FromSlash(name)), nil
+}
+
+// uniqueNames checks that no two files are given the same name, as a TV
+// template without {episode} or {original} does to a season pack.
+func uniqueNames(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateName, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
 // MoveToLibrary moves a completed download without progress reporting.
 func (m *Mover) MoveToLibrary(ctx context.Context, sourcePath string) (*MoveResult, error) {
 	detection, err := DetectFromPath(sourcePath)
@@ -521,26 +593,8 @@ func (m *Mover) mkdirAll(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
-// rsyncFile copies a single file using rsync.
-func (m *Mover) rsyncFile(src, dst string) error {
-	args := []string{"-avh", "--inplace", "--mkpath", src, dst}
-	var cmd *exec.Cmd
-	if m.config.UseSudo {
-		sudoArgs := append([]string{"-n", "rsync"}, args...)
-		cmd = exec.Command("sudo", sudoArgs...)
-	} else {
-		cmd = exec.Command("rsync", args...)
-	}
-	return cmd.Run()
-}
-
-// rsyncWithProgress runs rsync and parses progress output.
-func (m *Mover) rsyncWithProgress(
-	ctx context.Context,
-	src, dst string,
-	totalBytes int64,
-	progress chan<- MoveProgress,
-) error {
+// rsyncWithProgress copies a file with rsync, parsing its progress output.
+func (m *Mover) rsyncWithProgress(ctx context.Context, src, dst string, p fileProgress) error {
 	args := []string{"-avh", "--info=progress2", "--no-inc-recursive", "--partial", "--inplace", "--mkpath", src, dst}
 
 	var cmd *exec.Cmd
@@ -565,80 +619,11 @@ func (m *Mover) rsyncWithProgress(
 		return err
 	}
 
-	// Parse progress from rsync output
-	// Format: "  5.70G  86%   10.12MB/s    0:00:45"
-	progressRegex := regexp.MustCompile(`(\d+)%`)
-	// Match human-readable sizes like "5.70G", "123.45M", "1.2K", "500"
-	bytesRegex := regexp.MustCompile(`^\s*([\d.]+)([KMGT]?)`)
-	// Match transfer rate like "10.12MB/s" or "1.5GB/s"
-	rateRegex := regexp.MustCompile(`([\d.]+[KMGT]?B/s)`)
-	// Match ETA like "0:01:23" or "0:00:45"
-	etaRegex := regexp.MustCompile(`(\d+:\d+:\d+)`)
-
 	scanner := bufio.NewScanner(stdout)
 	scanner.Split(scanRsyncLines)
-
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Try to extract percentage
-		if matches := progressRegex.FindStringSubmatch(line); matches != nil {
-			pct, _ := strconv.Atoi(matches[1])
-
-			// Try to extract bytes copied (human-readable format)
-			var copied int64
-			if byteMatches := bytesRegex.FindStringSubmatch(line); byteMatches != nil {
-				value, _ := strconv.ParseFloat(byteMatches[1], 64)
-				suffix := byteMatches[2]
-				switch suffix {
-				case "K":
-					copied = int64(value * 1024)
-				case "M":
-					copied = int64(value * 1024 * 1024)
-				case "G":
-					copied = int64(value * 1024 * 1024 * 1024)
-				case "T":
-					copied = int64(value * 1024 * 1024 * 1024 * 1024)
-				default:
-					copied = int64(value)
-				}
-			}
-
-			// Extract rate
-			var rate string
-			if rateMatches := rateRegex.FindStringSubmatch(line); rateMatches != nil {
-				rate = rateMatches[1]
-			}
-
-			// Extract ETA
-			var eta string
-			if etaMatches := etaRegex.FindStringSubmatch(line); etaMatches != nil {
-				eta = etaMatches[1]
-			}
-
-			if progress != nil {
-				// Clamp values to avoid rsync protocol overhead showing >100%
-				if copied > totalBytes {
-					copied = totalBytes
-				}
-				if pct > 100 {
-					pct = 100
-				}
-
-				// Non-blocking send to prevent rsync from hanging
-				select {
-				case progress <- MoveProgress{
-					BytesCopied: copied,
-					TotalBytes:  totalBytes,
-					Percentage:  float64(pct) / 100.0,
-					CurrentFile: filepath.Base(src),
-					Rate:        rate,
-					ETA:         eta,
-				}:
-				default:
-					// Channel full, skip this update
-				}
-			}
+		if copied, rate, eta, ok := parseRsyncProgress(scanner.Text()); ok {
+			p.report(copied, rate, eta)
 		}
 	}
 
@@ -651,118 +636,41 @@ func (m *Mover) rsyncWithProgress(
 	return nil
 }
 
-// rsyncWithProgressOffset runs rsync with progress tracking that accounts for previously copied bytes.
-// Used when copying multiple files to show overall progress.
-func (m *Mover) rsyncWithProgressOffset(
-	ctx context.Context,
-	src, dst string,
-	fileBytes, totalBytes, byteOffset int64,
-	episodeIndex, episodeTotal int,
-	progress chan<- MoveProgress,
-) error {
-	args := []string{"-avh", "--info=progress2", "--no-inc-recursive", "--partial", "--inplace", "--mkpath", src, dst}
-
-	var cmd *exec.Cmd
-	if m.config.UseSudo {
-		sudoArgs := append([]string{"-n", "rsync"}, args...)
-		cmd = exec.CommandContext(ctx, "sudo", sudoArgs...)
-	} else {
-		cmd = exec.CommandContext(ctx, "rsync", args...)
-	}
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
-	}
-
-	var stderrBuf bytes.Buffer
-	cmd.Stderr = &stderrBuf
-
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	progressRegex := regexp.MustCompile(`(\d+)%`)
-	bytesRegex := regexp.MustCompile(`^\s*([\d.]+)([KMGT]?)`)
-	rateRegex := regexp.MustCompile(`([\d.]+[KMGT]?B/s)`)
-	etaRegex := regexp.MustCompile(`(\d+:\d+:\d+)`)
-
-	scanner := bufio.NewScanner(stdout)
-	scanner.Split(scanRsyncLines)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if matches := progressRegex.FindStringSubmatch(line); matches != nil {
-			var fileCopied int64
-			if byteMatches := bytesRegex.FindStringSubmatch(line); byteMatches != nil {
-				value, _ := strconv.ParseFloat(byteMatches[1], 64)
-				switch byteMatches[2] {
-				case "K":
-					fileCopied = int64(value * 1024)
-				case "M":
-					fileCopied = int64(value * 1024 * 1024)
-				case "G":
-					fileCopied = int64(value * 1024 * 1024 * 1024)
-				case "T":
-					fileCopied = int64(value * 1024 * 1024 * 1024 * 1024)
-				default:
-					fileCopied = int64(value)
-				}
-			}
-
-			var rate string
-			if rateMatches := rateRegex.FindStringSubmatch(line); rateMatches != nil {
-				rate = rateMatches[1]
-			}
-
-			var eta string
-			if etaMatches := etaRegex.FindStringSubmatch(line); etaMatches != nil {
-				eta = etaMatches[1]
-			}
+// rsync --info=progress2 output, e.g. "  5.70G  86%   10.12MB/s    0:00:45"
+var (
+	rsyncPercentRegex = regexp.MustCompile(`(\d+)%`)
+	rsyncBytesRegex   = regexp.MustCompile(`^\s*([\d.]+)([KMGT]?)`) // "5.70G", "123.45M", "500"
+	rsyncRateRegex    = regexp.MustCompile(`([\d.]+[KMGT]?B/s)`)    // "10.12MB/s"
+	rsyncETARegex     = regexp.MustCompile(`(\d+:\d+:\d+)`)         // "0:01:23"
+)
 
-			if progress != nil {
-				// Calculate overall progress including offset
-				overallCopied := byteOffset + fileCopied
-				if overallCopied > totalBytes {
-					overallCopied = totalBytes
-				}
-				overallPct := float64(overallCopied) / float64(totalBytes)
-				if overallPct > 1.0 {
-					overallPct = 1.0
-				}
-
-				// Calculate episode progress
-				episodePct := float64(fileCopied) / float64(fileBytes)
-				if episodePct > 1.0 {
-					episodePct = 1.0
-				}
-
-				select {
-				case progress <- MoveProgress{
-					BytesCopied:     overallCopied,
-					TotalBytes:      totalBytes,
-					Percentage:      overallPct,
-					CurrentFile:     filepath.Base(src),
-					Rate:            rate,
-					ETA:             eta,
-					EpisodeIndex:    episodeIndex,
-					EpisodeTotal:    episodeTotal,
-					EpisodeProgress: episodePct,
-				}:
-				default:
-				}
-			}
+// parseRsyncProgress reads the bytes copied, rate and time left from a
+// line of rsync progress output. ok is false for other lines.
+func parseRsyncProgress(line string) (copied int64, rate, eta string, ok bool) {
+	if rsyncPercentRegex.FindStringSubmatch(line) == nil {
+		return 0, "", "", false
+	}
+	if matches := rsyncBytesRegex.FindStringSubmatch(line); matches != nil {
+		value, _ := strconv.ParseFloat(matches[1], 64)
+		switch matches[2] {
+		case "K":
+			value *= 1 << 10
+		case "M":
+			value *= 1 << 20
+		case "G":
+			value *= 1 << 30
+		case "T":
+			value *= 1 << 40
 		}
+		copied = int64(value)
 	}
-
-	if err := cmd.Wait(); err != nil {
-		if stderrBuf.Len() > 0 {
-			return fmt.Errorf("%w: %s", err, stderrBuf.String())
-		}
-		return err
+	if matches := rsyncRateRegex.FindStringSubmatch(line); matches != nil {
+		rate = matches[1]
 	}
-	return nil
+	if matches := rsyncETARegex.FindStringSubmatch(line); matches != nil {
+		eta = matches[1]
+	}
+	return copied, rate, eta, true
 }
 
 // scanRsyncLines is a custom scanner that handles rsync's carriage return progress updates.
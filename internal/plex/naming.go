@@ -2,7 +2,6 @@ package plex
 
 import (
 	"errors"
-	"fmt"
 	"path/filepath"
 	"strings"
 )
@@ -14,7 +13,11 @@ var ErrInvalidInput = errors.New("invalid input for naming")
 type MovieNaming struct {
 	Title      string
 	Year       int
+	Edition    string // e.g., "Director's Cut", "Extended"
 	Resolution string // e.g., "1080p", "4K"
+	Source     string // e.g., "BluRay", "WEB-DL"
+	Codec      string // e.g., "x265"
+	Group      string // Release group
 	Extension  string // e.g., ".mkv", ".mp4"
 }
 
@@ -24,49 +27,80 @@ type TVNaming struct {
 	Season       int
 	Episode      int
 	EpisodeTitle string // Optional episode title
+	Original     string // Original filename without extension
 	Resolution   string
+	Source       string
+	Codec        string
+	Group        string
 	Extension    string
 }
 
-// FormatMoviePath generates a Plex-compatible filename for a movie.
-// Returns: "Title (Year).ext" (directly in Movies folder, like the bash script)
-func FormatMoviePath(m MovieNaming) (string, error) {
-	if m.Title == "" {
-		return "", ErrInvalidInput
-	}
-
-	title := SanitizeFilename(m.Title)
+// editions maps release tags to the edition names Plex shows.
+var editions = map[string]string{
+	"EXTENDED":   "Extended",
+	"UNRATED":    "Unrated",
+	"REMASTERED": "Remastered",
+	"UNCUT":      "Uncut",
+	"IMAX":       "IMAX",
+	"DC":         "Director's Cut",
+	"CRITERION":  "Criterion",
+}
 
-	if m.Year > 0 {
-		return fmt.Sprintf("%s (%d)%s", title, m.Year, m.Extension), nil
+// movieNaming collects what a movie's name can use from its detection.
+func movieNaming(d DetectionResult, ext string) MovieNaming {
+	var edition []string
+	for _, tag := range d.Release.Tags {
+		if name, ok := editions[tag]; ok {
+			edition = append(edition, name)
+		}
+	}
+	return MovieNaming{
+		Title:      d.Title,
+		Year:       d.Year,
+		Edition:    strings.Join(edition, " "),
+		Resolution: d.Release.Resolution,
+		Source:     d.Release.SourceLabel(),
+		Codec:      d.Release.Codec,
+		Group:      d.Release.Group,
+		Extension:  ext,
 	}
-	return title + m.Extension, nil
 }
 
-// FormatTVPath generates a Plex-compatible directory path for a TV episode.
-// Returns: "Show Title/Season ##" - caller appends original filename.
-func FormatTVPath(t TVNaming) (string, error) {
-	if t.ShowTitle == "" {
-		return "", ErrInvalidInput
+// tvNaming collects what an episode's name can use from its filename.
+// The show comes from the detection of the whole torrent, which the user
+// may have edited, and so do the season and episode if the filename
+// doesn't have them.
+func tvNaming(show DetectionResult, filename string) TVNaming {
+	ext := filepath.Ext(filename)
+	episode, _ := Detect(filename)
+	naming := TVNaming{
+		ShowTitle:  show.Title,
+		Season:     episode.Season,
+		Episode:    episode.Episode,
+		Original:   strings.TrimSuffix(filename, ext),
+		Resolution: episode.Release.Resolution,
+		Source:     episode.Release.SourceLabel(),
+		Codec:      episode.Release.Codec,
+		Group:      episode.Release.Group,
+		Extension:  ext,
 	}
-
-	showDir := SanitizeFilename(t.ShowTitle)
-	seasonDir := fmt.Sprintf("Season %02d", t.Season)
-
-	// Return just the directory path - original filename is kept for TV
-	return filepath.Join(showDir, seasonDir), nil
+	if naming.Season == 0 {
+		naming.Season = show.Season
+	}
+	if naming.Episode == 0 {
+		naming.Episode = show.Episode
+	}
+	return naming
 }
 
+// tvFilenameTemplate names episodes the way FormatTVFilename does.
+var tvFilenameTemplate = mustParseTemplate("{show} - S{season:02}E{episode:02} - {episode_title}{ext}", MediaTypeTV)
+
 // FormatTVFilename generates a Plex-compatible filename for a TV episode.
 // Returns: "Show Title - S##E## - Episode Title.ext" or "Show Title - S##E##.ext"
 func FormatTVFilename(t TVNaming) string {
-	title := SanitizeFilename(t.ShowTitle)
-
-	if t.EpisodeTitle != "" {
-		epTitle := SanitizeFilename(t.EpisodeTitle)
-		return fmt.Sprintf("%s - S%02dE%02d - %s%s", title, t.Season, t.Episode, epTitle, t.Extension)
-	}
-	return fmt.Sprintf("%s - S%02dE%02d%s", title, t.Season, t.Episode, t.Extension)
+	name, _ := tvFilenameTemplate.TV(t)
+	return name
 }
 
 // SanitizeFilename removes or replaces characters that are invalid
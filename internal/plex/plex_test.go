@@ -1,7 +1,552 @@
 package plex
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestPackageCompiles(t *testing.T) {
 	// placeholder
 }
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    MediaType
+		title   string
+		year    int
+		season  int
+		episode int
+	}{
+		{"Show.Name.S03E16.1080p.WEB.x264-GRP.mkv", MediaTypeTV, "Show Name", 0, 3, 16},
+		{"Show.Name.2019.S01E02.720p.mkv", MediaTypeTV, "Show Name 2019", 0, 1, 2},
+		{"Show Name 2x05 HDTV.avi", MediaTypeTV, "Show Name", 0, 2, 5},
+		{"Show.Name.S02.1080p.BluRay.x265-GRP", MediaTypeTV, "Show Name", 0, 2, 0},
+		{"The.Daily.Show.2024.03.05.720p.WEB.h264-GRP.mkv", MediaTypeTV, "The Daily Show", 0, 2024, 0},
+		{"[SubsPlease] Anime Title - 12 (1080p) [ABCD1234].mkv", MediaTypeTV, "Anime Title", 0, 1, 12},
+		{"Movie.Title.2019.1080p.BluRay.x264-GRP.mkv", MediaTypeMovie, "Movie Title", 2019, 0, 0},
+		{"Blade Runner 2049 (2017) [1080p].mkv", MediaTypeMovie, "Blade Runner 2049", 2017, 0, 0},
+		{"home-video.mkv", MediaTypeUnknown, "home-video", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		got, err := Detect(tt.name)
+		if (err != nil) != (tt.want == MediaTypeUnknown) {
+			t.Errorf("Detect(%q) error = %v", tt.name, err)
+		}
+		if got.Type != tt.want || got.Title != tt.title || got.Year != tt.year || got.Season != tt.season || got.Episode != tt.episode {
+			t.Errorf("Detect(%q) = %v %q year %d S%dE%d", tt.name, got.Type, got.Title, got.Year, got.Season, got.Episode)
+		}
+	}
+}
+
+// newLibrary creates a download holding a movie and empty libraries
+func newLibrary(t *testing.T) (download string, config MoveConfig) {
+	t.Helper()
+	dir := t.TempDir()
+	download = filepath.Join(dir, "downloads", "Movie.Title.2019.1080p.BluRay.x264-GRP")
+	config = MoveConfig{
+		MovieLibraryPath: filepath.Join(dir, "Movies"),
+		TVLibraryPath:    filepath.Join(dir, "TV"),
+	}
+	if err := os.MkdirAll(download, 0755); err != nil {
+		t.Fatal(err)
+	}
+	video := bytes.Repeat([]byte("frame"), 100000)
+	if err := os.WriteFile(filepath.Join(download, "Movie.Title.2019.1080p.BluRay.x264-GRP.mkv"), video, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(download, "Movie.Title.2019.1080p.BluRay.x264-GRP.srt"), []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return download, config
+}
+
+func TestTransferModes(t *testing.T) {
+	detection := DetectionResult{Type: MediaTypeMovie, Title: "Movie Title", Year: 2019}
+	tests := []struct {
+		mode TransferMode
+		want TransferMode
+	}{
+		{TransferAuto, TransferHardlink}, // The temp dir is one filesystem
+		{TransferHardlink, TransferHardlink},
+		{TransferCopy, TransferCopy},
+		{TransferMove, TransferMove},
+	}
+	for _, tt := range tests {
+		download, config := newLibrary(t)
+		config.TransferMode = tt.mode
+		src := filepath.Join(download, "Movie.Title.2019.1080p.BluRay.x264-GRP.mkv")
+		srcInfo, _ := os.Stat(src)
+
+		progress := make(chan MoveProgress, 100)
+		result, err := NewMover(config).MoveToLibraryWithProgress(context.Background(), download, detection, false, progress)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.mode, err)
+		}
+		if result.Transfer != tt.want {
+			t.Errorf("%s: transferred by %s, want %s", tt.mode, result.Transfer, tt.want)
+		}
+		dst := filepath.Join(config.MovieLibraryPath, "Movie Title (2019).mkv")
+		dstInfo, err := os.Stat(dst)
+		if err != nil || result.DestinationPath != dst {
+			t.Fatalf("%s: destination %q: %v", tt.mode, result.DestinationPath, err)
+		}
+		if _, err := os.Stat(filepath.Join(config.MovieLibraryPath, "Movie Title (2019).srt")); err != nil {
+			t.Errorf("%s: subtitle not transferred: %v", tt.mode, err)
+		}
+
+		_, srcErr := os.Stat(src)
+		switch tt.want {
+		case TransferHardlink:
+			if !os.SameFile(srcInfo, dstInfo) {
+				t.Errorf("%s: destination isn't a link to the source", tt.mode)
+			}
+		case TransferCopy:
+			if srcErr != nil || os.SameFile(srcInfo, dstInfo) || dstInfo.Size() != srcInfo.Size() || !dstInfo.ModTime().Equal(srcInfo.ModTime()) {
+				t.Errorf("%s: destination isn't a separate copy", tt.mode)
+			}
+		case TransferMove:
+			if srcErr == nil {
+				t.Errorf("%s: source still there", tt.mode)
+			}
+		}
+
+		// The last update shows the move done
+		var last MoveProgress
+		for len(progress) > 0 {
+			last = <-progress
+		}
+		if last.Percentage != 1 || last.BytesCopied != srcInfo.Size() {
+			t.Errorf("%s: last progress %.2f, %d bytes", tt.mode, last.Percentage, last.BytesCopied)
+		}
+	}
+}
+
+func TestAutoCopyMode(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	config := MoveConfig{UseSudo: true}
+	if mode := config.copyMode(); mode != TransferCopy || config.UsesRsync() {
+		t.Errorf("without rsync: copy mode %s, uses rsync %v", mode, config.UsesRsync())
+	}
+
+	if err := os.WriteFile(filepath.Join(bin, "rsync"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if mode := config.copyMode(); mode != TransferRsync || !config.UsesRsync() {
+		t.Errorf("with rsync: copy mode %s, uses rsync %v", mode, config.UsesRsync())
+	}
+	config.UseSudo = false
+	if mode := config.copyMode(); mode != TransferCopy {
+		t.Errorf("without sudo: copy mode %s", mode)
+	}
+}
+
+func TestTransferAgain(t *testing.T) {
+	// Moving again replaces the file (or keeps the link) without leftovers
+	download, config := newLibrary(t)
+	detection := DetectionResult{Type: MediaTypeMovie, Title: "Movie Title", Year: 2019}
+	for _, mode := range []TransferMode{TransferHardlink, TransferHardlink, TransferCopy} {
+		config.TransferMode = mode
+		if _, err := NewMover(config).MoveToLibraryWithProgress(context.Background(), download, detection, false, nil); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+	}
+	entries, _ := os.ReadDir(config.MovieLibraryPath)
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("library holds %q, want the movie and its subtitle", names)
+	}
+}
+
+func TestParseTransferMode(t *testing.T) {
+	for _, s := range []string{"", "auto", "hardlink", "reflink", "move", "rsync", "copy"} {
+		if _, err := ParseTransferMode(s); err != nil {
+			t.Errorf("ParseTransferMode(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseTransferMode("symlink"); err == nil {
+		t.Error("ParseTransferMode accepted symlink")
+	}
+}
+
+func TestPlanRelocation(t *testing.T) {
+	config := MoveConfig{MovieLibraryPath: "/media/Movies", TVLibraryPath: "/media/TV"}
+	movie := DetectionResult{Type: MediaTypeMovie, Title: "The Matrix", Year: 1999}
+	show := DetectionResult{Type: MediaTypeTV, Title: "The Expanse", Season: 3}
+
+	tests := []struct {
+		name      string
+		files     []TorrentFile
+		detection DetectionResult
+		location  string
+		renames   string
+		dest      string
+	}{
+		{
+			"movie file",
+			[]TorrentFile{{Path: "The.Matrix.1999.1080p.mkv", Size: 100}, {Path: "The.Matrix.1999.1080p.en.srt"}},
+			movie, "/media/Movies",
+			"The.Matrix.1999.1080p.en.srt>The Matrix (1999)/The Matrix (1999).en.srt The.Matrix.1999.1080p.mkv>The Matrix (1999)/The Matrix (1999).mkv",
+			"/media/Movies/The Matrix (1999)/The Matrix (1999).mkv",
+		},
+		{
+			"single movie file",
+			[]TorrentFile{{Path: "The.Matrix.1999.1080p.mkv", Size: 100}},
+			movie, "/media/Movies",
+			"The.Matrix.1999.1080p.mkv>The Matrix (1999).mkv",
+			"/media/Movies/The Matrix (1999).mkv",
+		},
+		{
+			"movie files without a folder",
+			[]TorrentFile{{Path: "movie.mkv", Size: 100}, {Path: "Extras/making-of.mkv", Size: 10}, {Path: "info.nfo"}},
+			movie, "/media/Movies",
+			"movie.mkv>The Matrix (1999)/The Matrix (1999).mkv Extras/making-of.mkv>The Matrix (1999)/Extras/making-of.mkv info.nfo>The Matrix (1999)/info.nfo",
+			"/media/Movies/The Matrix (1999)/The Matrix (1999).mkv",
+		},
+		{
+			"subtitle case",
+			[]TorrentFile{{Path: "Expanse.S02E05.mkv"}, {Path: "EXPANSE.S02E05.EN.SRT"}},
+			show, "/media/TV/The Expanse",
+			"EXPANSE.S02E05.EN.SRT>Season 02/Expanse.S02E05.EN.SRT Expanse.S02E05.mkv>Season 02/Expanse.S02E05.mkv",
+			"/media/TV/The Expanse/Season 02",
+		},
+		{
+			"movie folder",
+			[]TorrentFile{{Path: "The.Matrix.1999/movie.mkv", Size: 100}, {Path: "The.Matrix.1999/sample.mkv", Size: 1}, {Path: "The.Matrix.1999/info.nfo"}},
+			movie, "/media/Movies",
+			"The.Matrix.1999/>The Matrix (1999) The Matrix (1999)/movie.mkv>The Matrix (1999)/The Matrix (1999).mkv",
+			"/media/Movies/The Matrix (1999)/The Matrix (1999).mkv",
+		},
+		{
+			"season pack",
+			[]TorrentFile{{Path: "Expanse.S03/Expanse.S03E01.mkv"}, {Path: "Expanse.S03/Expanse.S03E02.mkv"}},
+			show, "/media/TV/The Expanse",
+			"Expanse.S03/>Season 03",
+			"/media/TV/The Expanse/Season 03",
+		},
+		{
+			"episode file",
+			[]TorrentFile{{Path: "Expanse.S02E05.mkv"}, {Path: "Expanse.S02E05.srt"}},
+			show, "/media/TV/The Expanse",
+			"Expanse.S02E05.srt>Season 02/Expanse.S02E05.srt Expanse.S02E05.mkv>Season 02/Expanse.S02E05.mkv",
+			"/media/TV/The Expanse/Season 02",
+		},
+		{
+			"several seasons",
+			[]TorrentFile{{Path: "Expanse/Expanse.S01E01.mkv"}, {Path: "Expanse/Expanse.S02E01.mkv"}},
+			show, "/media/TV/The Expanse",
+			"Expanse/Expanse.S01E01.mkv>Season 01/Expanse.S01E01.mkv Expanse/Expanse.S02E01.mkv>Season 02/Expanse.S02E01.mkv",
+			"/media/TV/The Expanse",
+		},
+	}
+	for _, tt := range tests {
+		r, err := PlanRelocation(tt.files, tt.detection, config)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var renames []string
+		for _, rn := range r.Renames {
+			if rn.Folder {
+				rn.Old += "/"
+			}
+			renames = append(renames, rn.Old+">"+rn.New)
+		}
+		if got := strings.Join(renames, " "); got != tt.renames {
+			t.Errorf("%s: renames %q, want %q", tt.name, got, tt.renames)
+		}
+		if r.Location != tt.location || r.DestinationPath != tt.dest {
+			t.Errorf("%s: location %q dest %q, want %q %q", tt.name, r.Location, r.DestinationPath, tt.location, tt.dest)
+		}
+	}
+
+	// Named by the templates
+	config.TVTemplate, _ = ParseTemplate("{show}/S{season:02}/{show} {season}x{episode:02}{ext}", MediaTypeTV)
+	r, err := PlanRelocation([]TorrentFile{{Path: "Pack/Expanse.S03E01.mkv"}, {Path: "Pack/Expanse.S03E01.en.srt"}}, show, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rename{
+		{Folder: true, Old: "Pack", New: "S03"},
+		{Old: "S03/Expanse.S03E01.en.srt", New: "S03/The Expanse 3x01.en.srt"},
+		{Old: "S03/Expanse.S03E01.mkv", New: "S03/The Expanse 3x01.mkv"},
+	}
+	if !slices.Equal(r.Renames, want) || r.Location != "/media/TV/The Expanse" {
+		t.Errorf("templated relocation %q %+v", r.Location, r.Renames)
+	}
+
+	if _, err := PlanRelocation([]TorrentFile{{Path: "readme.txt"}}, movie, config); err == nil {
+		t.Error("PlanRelocation accepted a torrent without videos")
+	}
+
+	// Subtitles are renamed after the video, whatever they are called
+	for _, tt := range []struct{ sub, want string }{
+		{"Movie.2019.EN.srt", "Alien (1979).EN.srt"},
+		{"english.srt", "Alien (1979).english.srt"},
+		{"İ.srt", "Alien (1979).İ.srt"}, // Longer in lower case
+	} {
+		if got := subtitleName(tt.sub, "movie.2019.mkv", "Alien (1979)/Alien (1979).mkv"); got != tt.want {
+			t.Errorf("subtitleName(%q) = %q, want %q", tt.sub, got, tt.want)
+		}
+	}
+
+	// A template without the episode names every episode the same
+	config.TVTemplate, _ = ParseTemplate("{show}/Season {season:02}/{show}{ext}", MediaTypeTV)
+	pack := []TorrentFile{{Path: "Pack/Expanse.S03E01.mkv"}, {Path: "Pack/Expanse.S03E02.mkv"}}
+	if _, err := PlanRelocation(pack, show, config); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("duplicate names: %v, want ErrDuplicateName", err)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	valid := []struct {
+		text string
+		kind MediaType
+	}{
+		{"", MediaTypeMovie},
+		{"{title} ({year})/{title} ({year}) {edition} [{resolution}]{ext}", MediaTypeMovie},
+		{"{show}/Season {season:02}/{show} - S{season:02}E{episode:02}{ext}", MediaTypeTV},
+	}
+	for _, tt := range valid {
+		if _, err := ParseTemplate(tt.text, tt.kind); err != nil {
+			t.Errorf("ParseTemplate(%q): %v", tt.text, err)
+		}
+	}
+
+	invalid := []struct {
+		text string
+		kind MediaType
+	}{
+		{"{title} {show}{ext}", MediaTypeMovie},  // Unknown placeholder
+		{"{show}/{title}{ext}", MediaTypeTV},     // Movie placeholder
+		{"{title}", MediaTypeMovie},              // No extension
+		{"{title}{ext}.bak", MediaTypeMovie},     // Extension not last
+		{"({year}){ext}", MediaTypeMovie},        // No title
+		{"{title:02}{ext}", MediaTypeMovie},      // Padded text
+		{"{show} {season:2}{ext}", MediaTypeTV},  // Bad format
+		{"{title} {year{ext}", MediaTypeMovie},   // Unclosed
+		{"{title}}{ext}", MediaTypeMovie},        // Stray brace
+		{"../{title}{ext}", MediaTypeMovie},      // Leaves the library
+		{"/movies/{title}{ext}", MediaTypeMovie}, // Absolute
+	}
+	for _, tt := range invalid {
+		if _, err := ParseTemplate(tt.text, tt.kind); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("ParseTemplate(%q) = %v, want ErrInvalidTemplate", tt.text, err)
+		}
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	movie, _ := ParseTemplate("{title} ({year})/{title} ({year}) {edition} [{resolution}]{ext}", MediaTypeMovie)
+	tv, _ := ParseTemplate("{show}/Season {season:02}/{show} - S{season:02}E{episode:02} - {episode_title}{ext}", MediaTypeTV)
+
+	tests := []struct {
+		got  func() (string, error)
+		want string
+	}{
+		{func() (string, error) {
+			return movie.Movie(MovieNaming{Title: "Alien", Year: 1979, Edition: "Director's Cut", Resolution: "2160p", Extension: ".mkv"})
+		}, "Alien (1979)/Alien (1979) Director's Cut [2160p].mkv"},
+		// Empty placeholders leave no traces
+		{func() (string, error) {
+			return movie.Movie(MovieNaming{Title: "Alien", Extension: ".mkv"})
+		}, "Alien/Alien.mkv"},
+		// Values can't add folders
+		{func() (string, error) {
+			return movie.Movie(MovieNaming{Title: "AC/DC: Live", Year: 1992, Extension: ".mp4"})
+		}, "AC-DC- Live (1992)/AC-DC- Live (1992).mp4"},
+		{func() (string, error) {
+			return tv.TV(TVNaming{ShowTitle: "The Expanse", Season: 3, Episode: 5, Extension: ".mkv"})
+		}, "The Expanse/Season 03/The Expanse - S03E05.mkv"},
+		// The zero template is the default
+		{func() (string, error) {
+			return Template{}.TV(TVNaming{ShowTitle: "Show", Season: 0, Original: "show.s00e01", Extension: ".mkv"})
+		}, "Show/Season 00/show.s00e01.mkv"},
+	}
+	for _, tt := range tests {
+		got, err := tt.got()
+		if err != nil || got != tt.want {
+			t.Errorf("got %q, %v, want %q", got, err, tt.want)
+		}
+	}
+
+	if _, err := movie.TV(TVNaming{ShowTitle: "Show", Extension: ".mkv"}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("a movie template named an episode: %v", err)
+	}
+	if got := FormatTVFilename(TVNaming{ShowTitle: "Show", Season: 1, Episode: 2, Extension: ".mkv"}); got != "Show - S01E02.mkv" {
+		t.Errorf("FormatTVFilename = %q", got)
+	}
+}
+
+func TestMoveWithTemplate(t *testing.T) {
+	download, config := newLibrary(t)
+	config.MovieTemplate, _ = ParseTemplate("{title} ({year})/{title} ({year}) [{resolution}]{ext}", MediaTypeMovie)
+	src := filepath.Join(download, "Movie.Title.2019.1080p.BluRay.x264-GRP.mkv")
+	detection, _ := Detect(src)
+	detection.Title = "Movie Title" // As if edited in the modal
+
+	mover := NewMover(config)
+	want := filepath.Join(config.MovieLibraryPath, "Movie Title (2019)", "Movie Title (2019) [1080p].mkv")
+	if dest, err := mover.Destination(download, detection); err != nil || dest != want {
+		t.Errorf("Destination = %q, %v, want %q", dest, err, want)
+	}
+	result, err := mover.MoveToLibraryWithProgress(context.Background(), download, detection, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DestinationPath != want {
+		t.Errorf("moved to %q, want %q", result.DestinationPath, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Error(err)
+	}
+
+	// Episodes and their subtitles are named by the TV template
+	pack := filepath.Join(t.TempDir(), "Show.S01")
+	if err := os.Mkdir(pack, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Show.S01E01.mkv", "Show.S01E01.en.srt"} {
+		if err := os.WriteFile(filepath.Join(pack, name), []byte("1"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config.TVTemplate, _ = ParseTemplate("{show}/Season {season:02}/{show} - S{season:02}E{episode:02}{ext}", MediaTypeTV)
+	show := DetectionResult{Type: MediaTypeTV, Title: "Show", Season: 1}
+	if _, err := NewMover(config).MoveToLibraryWithProgress(context.Background(), pack, show, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(config.TVLibraryPath, "Show", "Season 01", "Show - S01E01.en.srt")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoveDuplicateNames(t *testing.T) {
+	download, config := newLibrary(t)
+	for _, name := range []string{"Show.S01E01.mkv", "Show.S01E02.mkv"} {
+		if err := os.WriteFile(filepath.Join(download, name), []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config.TVTemplate, _ = ParseTemplate("{show}/Season {season:02}/{show} - S{season:02}{ext}", MediaTypeTV)
+	detection := DetectionResult{Type: MediaTypeTV, Title: "Show", Season: 1}
+
+	_, err := NewMover(config).MoveToLibraryWithProgress(context.Background(), download, detection, false, nil)
+	if !errors.Is(err, ErrDuplicateName) {
+		t.Fatalf("err = %v, want ErrDuplicateName", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.TVLibraryPath, "Show")); !os.IsNotExist(err) {
+		t.Errorf("something was moved: %v", err)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mkv")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 4096) // 64 KiB
+	if err := os.WriteFile(src, data, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, verify := range []Checksum{ChecksumNone, ChecksumXXHash, ChecksumCRC32C, ChecksumSHA256} {
+		dst := filepath.Join(dir, "dst-"+string(verify)+".mkv")
+		var reports []int64
+		err := CopyFile(context.Background(), src, dst, CopyOptions{
+			ChunkSize: 10000,
+			Verify:    verify,
+			Progress:  func(copied int64) { reports = append(reports, copied) },
+		})
+		if err != nil {
+			t.Fatalf("verify %q: %v", verify, err)
+		}
+		got, _ := os.ReadFile(dst)
+		if !bytes.Equal(got, data) {
+			t.Errorf("verify %q: copy differs", verify)
+		}
+		if len(reports) != 8 || reports[0] != 0 || reports[7] != int64(len(data)) {
+			t.Errorf("verify %q: progress %v", verify, reports)
+		}
+		if _, err := os.Stat(dst + ".part"); err == nil {
+			t.Errorf("verify %q: partial file left behind", verify)
+		}
+		info, _ := os.Stat(dst)
+		if info.Mode().Perm() != 0640 {
+			t.Errorf("verify %q: mode %v", verify, info.Mode())
+		}
+	}
+}
+
+func TestCopyFileResume(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mkv")
+	dst := filepath.Join(dir, "dst.mkv")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelling keeps what was copied
+	ctx, cancel := context.WithCancel(context.Background())
+	err := CopyFile(ctx, src, dst, CopyOptions{ChunkSize: 16384, Progress: func(copied int64) {
+		if copied >= 32768 {
+			cancel()
+		}
+	}})
+	if err != context.Canceled {
+		t.Fatalf("cancelled copy: %v", err)
+	}
+	if info, err := os.Stat(dst + ".part"); err != nil || info.Size() != 32768 {
+		t.Fatalf("partial file after cancelling: %v", err)
+	}
+
+	// Copying again picks up where it stopped
+	var first int64 = -1
+	err = CopyFile(context.Background(), src, dst, CopyOptions{Verify: ChecksumSHA256, Progress: func(copied int64) {
+		if first < 0 {
+			first = copied
+		}
+	}})
+	if err != nil || first != 32768 {
+		t.Fatalf("resumed copy from %d: %v", first, err)
+	}
+	if got, _ := os.ReadFile(dst); !bytes.Equal(got, data) {
+		t.Error("resumed copy differs")
+	}
+
+	// A partial file that doesn't match the source is started over
+	if err := os.WriteFile(dst+".part", []byte("something else"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first = -1
+	err = CopyFile(context.Background(), src, dst, CopyOptions{Progress: func(copied int64) {
+		if first < 0 {
+			first = copied
+		}
+	}})
+	if err != nil || first != 0 {
+		t.Fatalf("mismatched partial resumed from %d: %v", first, err)
+	}
+	if got, _ := os.ReadFile(dst); !bytes.Equal(got, data) {
+		t.Error("restarted copy differs")
+	}
+}
+
+func TestParseRsyncProgress(t *testing.T) {
+	copied, rate, eta, ok := parseRsyncProgress("  1.50G  86%   10.12MB/s    0:00:45")
+	if !ok || copied != 3<<29 || rate != "10.12MB/s" || eta != "0:00:45" {
+		t.Errorf("parseRsyncProgress = %d %q %q %v", copied, rate, eta, ok)
+	}
+	if _, _, _, ok := parseRsyncProgress("sending incremental file list"); ok {
+		t.Error("parsed a line without progress")
+	}
+}
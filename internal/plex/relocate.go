@@ -0,0 +1,210 @@
+package plex
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TorrentFile is a file of a torrent, as qBittorrent lists it.
+type TorrentFile struct {
+	Path string // Relative to the save path, "/" separated
+	Size int64
+}
+
+// Rename is a file or folder of a torrent to rename, relative to its save
+// path. A file can be renamed into another folder.
+type Rename struct {
+	Folder bool
+	Old    string
+	New    string
+}
+
+// Relocation is how qBittorrent can put a torrent into a library itself:
+// rename its files into the Plex layout, then move its save path into the
+// library. The torrent keeps seeding from there.
+type Relocation struct {
+	Location        string   // New save path
+	Renames         []Rename // Applied in order, before the save path is moved
+	DestinationPath string   // Where the movie file or season folder ends up
+	FilesMoved      int      // Number of video files
+}
+
+// PlanRelocation works out a relocation for a torrent's files, named by
+// the config's templates.
+//
+// A movie's largest video is renamed by the movie template and moved into
+// the movie library. The rest of a multi-file torrent goes in the movie's
+// folder ("Title (Year)" if the template has none): its root folder
+// becomes that folder, or its files are moved into it. A show is moved
+// to its folder in the TV library, and each episode renamed by the TV
+// template: the root folder of a torrent whose episodes all go in one
+// folder becomes that folder. Subtitles named after a video follow it.
+// Nothing is planned if the template gives two episodes the same name.
+func PlanRelocation(files []TorrentFile, detection DetectionResult, config MoveConfig) (Relocation, error) {
+	videos := torrentVideos(files)
+	if len(videos) == 0 {
+		return Relocation{}, fmt.Errorf("no video files in torrent")
+	}
+
+	var r Relocation
+	var targets []string // Where each video goes, relative to the new location
+	switch detection.Type {
+	case MediaTypeMovie:
+		main := videos[0]
+		for _, v := range videos[1:] {
+			if v.Size > main.Size {
+				main = v
+			}
+		}
+		name, err := config.MovieTemplate.Movie(movieNaming(detection, path.Ext(main.Path)))
+		if err != nil {
+			return Relocation{}, fmt.Errorf("format movie path: %w", err)
+		}
+		if path.Dir(name) == "." && len(files) > 1 {
+			// The folder keeps everything else in the torrent together
+			name = path.Join(strings.TrimSuffix(name, path.Ext(name)), name)
+		}
+		r = Relocation{Location: config.MovieLibraryPath, FilesMoved: 1}
+		videos, targets = []TorrentFile{main}, []string{name}
+
+	case MediaTypeTV:
+		for _, v := range videos {
+			name, err := config.TVTemplate.TV(tvNaming(detection, path.Base(v.Path)))
+			if err != nil {
+				return Relocation{}, fmt.Errorf("format tv path: %w", err)
+			}
+			targets = append(targets, name)
+		}
+		if err := uniqueNames(targets); err != nil {
+			return Relocation{}, err
+		}
+		// The show's folder becomes the location
+		showDir := commonFolder(targets)
+		r = Relocation{Location: filepath.Join(config.TVLibraryPath, filepath.FromSlash(showDir)), FilesMoved: len(videos)}
+		for i := range targets {
+			targets[i] = strings.TrimPrefix(targets[i], showDir+"/")
+		}
+
+	default:
+		return Relocation{}, fmt.Errorf("unknown media type")
+	}
+
+	dirs := make(map[string]bool)
+	for _, target := range targets {
+		dirs[path.Dir(target)] = true
+	}
+	moved := func(p string) string { return p }
+	if root := rootFolder(files); root != "" && len(dirs) == 1 && !dirs["."] {
+		dir := path.Dir(targets[0])
+		r.rename(true, root, dir)
+		moved = func(p string) string { return dir + strings.TrimPrefix(p, root) }
+	}
+	named := make(map[string]bool) // Files renamed after a video
+	for i, v := range videos {
+		for _, sub := range torrentSubtitles(files, v.Path) {
+			r.rename(false, moved(sub), path.Join(path.Dir(targets[i]), subtitleName(sub, v.Path, targets[i])))
+			named[sub] = true
+		}
+		r.rename(false, moved(v.Path), targets[i])
+		named[v.Path] = true
+	}
+	if detection.Type == MediaTypeMovie && len(files) > 1 && rootFolder(files) == "" {
+		// Without a root folder to rename, the rest of the torrent is moved
+		// into the movie's folder file by file, not left in the library
+		dir := path.Dir(targets[0])
+		for _, f := range files {
+			if !named[f.Path] {
+				r.rename(false, f.Path, path.Join(dir, f.Path))
+			}
+		}
+	}
+
+	switch {
+	case detection.Type == MediaTypeMovie:
+		r.DestinationPath = filepath.Join(r.Location, filepath.FromSlash(targets[0]))
+	case len(dirs) == 1 && !dirs["."]:
+		r.DestinationPath = filepath.Join(r.Location, filepath.FromSlash(path.Dir(targets[0])))
+	default:
+		r.DestinationPath = r.Location
+	}
+	return r, nil
+}
+
+// rename adds a rename, unless the name doesn't change.
+func (r *Relocation) rename(folder bool, oldPath, newPath string) {
+	if oldPath != newPath {
+		r.Renames = append(r.Renames, Rename{Folder: folder, Old: oldPath, New: newPath})
+	}
+}
+
+// torrentVideos returns the video files of a torrent, ignoring samples,
+// sorted by path.
+func torrentVideos(files []TorrentFile) []TorrentFile {
+	var videos []TorrentFile
+	for _, f := range files {
+		name := strings.ToLower(path.Base(f.Path))
+		if videoExtensions[path.Ext(name)] && !strings.Contains(name, "sample") {
+			videos = append(videos, f)
+		}
+	}
+	sort.Slice(videos, func(i, j int) bool { return videos[i].Path < videos[j].Path })
+	return videos
+}
+
+// torrentSubtitles returns the .srt files of a torrent named after a video.
+func torrentSubtitles(files []TorrentFile, video string) []string {
+	noExt := strings.TrimSuffix(path.Base(video), path.Ext(video))
+	var subs []string
+	for _, f := range files {
+		name := path.Base(f.Path)
+		if strings.EqualFold(path.Ext(name), ".srt") && hasPrefixFold(name, noExt) {
+			subs = append(subs, f.Path)
+		}
+	}
+	return subs
+}
+
+// rootFolder returns the folder all of a torrent's files are in, or "" if
+// they aren't in one.
+func rootFolder(files []TorrentFile) string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return commonFolder(paths)
+}
+
+// commonFolder returns the first folder all paths are in, or "" if they
+// aren't all in one.
+func commonFolder(paths []string) string {
+	common := ""
+	for _, p := range paths {
+		first, _, ok := strings.Cut(p, "/")
+		if !ok || (common != "" && first != common) {
+			return ""
+		}
+		common = first
+	}
+	return common
+}
+
+// subtitleName renames a subtitle after its video's new name, keeping
+// what follows the old name (e.g. ".en.srt"). A subtitle not named after
+// the video keeps its whole name after the new one.
+func subtitleName(sub, video, dest string) string {
+	name := path.Base(sub)
+	videoNoExt := strings.TrimSuffix(path.Base(video), path.Ext(video))
+	destNoExt := strings.TrimSuffix(path.Base(dest), path.Ext(dest))
+	if !hasPrefixFold(name, videoNoExt) {
+		return destNoExt + "." + name
+	}
+	return destNoExt + name[len(videoNoExt):]
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,261 @@
+package plex
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default naming templates, the layout used before templates existed.
+const (
+	DefaultMovieTemplate = "{title} ({year}){ext}"
+	DefaultTVTemplate    = "{show}/Season {season:02}/{original}{ext}"
+)
+
+// ErrInvalidTemplate indicates a naming template can't be used.
+var ErrInvalidTemplate = errors.New("invalid naming template")
+
+// Placeholders a template may use, by media type.
+var (
+	MoviePlaceholders = []string{"title", "year", "edition", "resolution", "source", "codec", "group", "ext"}
+	TVPlaceholders    = []string{"show", "season", "episode", "episode_title", "original", "resolution", "source", "codec", "group", "ext"}
+)
+
+// numericPlaceholders can be zero-padded, e.g. {season:02}.
+var numericPlaceholders = map[string]bool{"year": true, "season": true, "episode": true}
+
+// Template names files in a library, e.g. "{title} ({year})/{title}
+// ({year}) [{resolution}]{ext}". A "/" starts a folder. Placeholder values
+// are sanitized, and whatever an empty placeholder leaves behind, such as
+// "()" or a dangling " - ", is tidied away.
+type Template struct {
+	kind  MediaType
+	text  string
+	parts []templatePart
+}
+
+// templatePart is literal text or a placeholder.
+type templatePart struct {
+	literal string
+	name    string // Placeholder name, empty for literal text
+	width   int    // Zero-pad numbers to this many digits
+}
+
+var (
+	defaultMovie = mustParseTemplate(DefaultMovieTemplate, MediaTypeMovie)
+	defaultTV    = mustParseTemplate(DefaultTVTemplate, MediaTypeTV)
+)
+
+func mustParseTemplate(text string, kind MediaType) Template {
+	t, err := ParseTemplate(text, kind)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ParseTemplate reads a naming template for movies or TV. An empty
+// template is the default one.
+func ParseTemplate(text string, kind MediaType) (Template, error) {
+	var allowed []string
+	var required string
+	switch kind {
+	case MediaTypeMovie:
+		allowed, required = MoviePlaceholders, "title"
+		if text == "" {
+			text = DefaultMovieTemplate
+		}
+	case MediaTypeTV:
+		allowed, required = TVPlaceholders, "show"
+		if text == "" {
+			text = DefaultTVTemplate
+		}
+	default:
+		return Template{}, fmt.Errorf("%w: unknown media type", ErrInvalidTemplate)
+	}
+
+	t := Template{kind: kind, text: text}
+	rest := text
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			t.parts = append(t.parts, templatePart{literal: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return Template{}, fmt.Errorf("%w: unexpected } in %q", ErrInvalidTemplate, text)
+		}
+		if open > 0 {
+			t.parts = append(t.parts, templatePart{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return Template{}, fmt.Errorf("%w: unclosed { in %q", ErrInvalidTemplate, text)
+		}
+		part, err := parsePlaceholder(rest[open+1:open+end], allowed)
+		if err != nil {
+			return Template{}, err
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[open+end+1:]
+	}
+
+	if err := t.validate(required); err != nil {
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// parsePlaceholder reads what is between braces, e.g. "season:02".
+func parsePlaceholder(s string, allowed []string) (templatePart, error) {
+	name, spec, hasSpec := strings.Cut(s, ":")
+	if !slices.Contains(allowed, name) {
+		return templatePart{}, fmt.Errorf("%w: unknown placeholder {%s} (want %s)",
+			ErrInvalidTemplate, s, strings.Join(allowed, ", "))
+	}
+	part := templatePart{name: name}
+	if !hasSpec {
+		return part, nil
+	}
+	width, err := strconv.Atoi(strings.TrimPrefix(spec, "0"))
+	if !numericPlaceholders[name] || !strings.HasPrefix(spec, "0") || err != nil || width < 1 || width > 9 {
+		return templatePart{}, fmt.Errorf("%w: bad format {%s} (want e.g. {season:02})", ErrInvalidTemplate, s)
+	}
+	part.width = width
+	return part, nil
+}
+
+// validate checks that a template names a file inside the library.
+func (t Template) validate(required string) error {
+	last := len(t.parts) - 1
+	hasRequired := false
+	for i, p := range t.parts {
+		switch {
+		case p.name == "ext" && i != last:
+			return fmt.Errorf("%w: {ext} must end %q", ErrInvalidTemplate, t.text)
+		case p.name == required:
+			hasRequired = true
+		}
+	}
+	if last < 0 || t.parts[last].name != "ext" {
+		return fmt.Errorf("%w: %q must end with {ext}", ErrInvalidTemplate, t.text)
+	}
+	if !hasRequired {
+		return fmt.Errorf("%w: %q must use {%s}", ErrInvalidTemplate, t.text, required)
+	}
+	if strings.HasPrefix(t.text, "/") {
+		return fmt.Errorf("%w: %q must be relative to the library", ErrInvalidTemplate, t.text)
+	}
+	for _, p := range t.parts {
+		for _, dir := range strings.Split(p.literal, "/") {
+			if dir == "." || dir == ".." || strings.Contains(dir, "\\") {
+				return fmt.Errorf("%w: %q leaves the library", ErrInvalidTemplate, t.text)
+			}
+		}
+	}
+	return nil
+}
+
+// Movie names a movie, relative to the movie library. The zero Template is
+// the default one.
+func (t Template) Movie(m MovieNaming) (string, error) {
+	if t.kind == MediaTypeUnknown {
+		t = defaultMovie
+	}
+	if t.kind != MediaTypeMovie {
+		return "", fmt.Errorf("%w: not a movie template", ErrInvalidTemplate)
+	}
+	if m.Title == "" {
+		return "", ErrInvalidInput
+	}
+	return t.render(map[string]string{
+		"title":      m.Title,
+		"year":       year(m.Year),
+		"edition":    m.Edition,
+		"resolution": m.Resolution,
+		"source":     m.Source,
+		"codec":      m.Codec,
+		"group":      m.Group,
+		"ext":        m.Extension,
+	})
+}
+
+// TV names an episode, relative to the TV library. The zero Template is
+// the default one.
+func (t Template) TV(e TVNaming) (string, error) {
+	if t.kind == MediaTypeUnknown {
+		t = defaultTV
+	}
+	if t.kind != MediaTypeTV {
+		return "", fmt.Errorf("%w: not a TV template", ErrInvalidTemplate)
+	}
+	if e.ShowTitle == "" {
+		return "", ErrInvalidInput
+	}
+	return t.render(map[string]string{
+		"show":          e.ShowTitle,
+		"season":        strconv.Itoa(e.Season), // Season 00 holds specials
+		"episode":       strconv.Itoa(e.Episode),
+		"episode_title": e.EpisodeTitle,
+		"original":      e.Original,
+		"resolution":    e.Resolution,
+		"source":        e.Source,
+		"codec":         e.Codec,
+		"group":         e.Group,
+		"ext":           e.Extension,
+	})
+}
+
+// year formats a year for a placeholder, empty if unknown.
+func year(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// render fills in the placeholders and tidies each folder and the file
+// name. The extension is kept apart, so nothing is tidied away before it.
+func (t Template) render(values map[string]string) (string, error) {
+	var b strings.Builder
+	for _, p := range t.parts[:len(t.parts)-1] {
+		if p.name == "" {
+			b.WriteString(p.literal)
+			continue
+		}
+		value := values[p.name]
+		if value != "" && len(value) < p.width {
+			value = strings.Repeat("0", p.width-len(value)) + value
+		}
+		b.WriteString(SanitizeFilename(value))
+	}
+
+	var dirs []string
+	for _, dir := range strings.Split(b.String(), "/") {
+		if dir = tidy(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return "", ErrInvalidInput
+	}
+	return strings.Join(dirs, "/") + values["ext"], nil
+}
+
+var (
+	emptyBracketsRegex = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+	repeatedDashRegex  = regexp.MustCompile(`(\s+-)+\s+`)
+	spacesRegex        = regexp.MustCompile(`\s{2,}`)
+)
+
+// tidy removes what empty placeholders leave in a name: empty brackets,
+// repeated " - " separators and doubled or trailing spaces.
+func tidy(name string) string {
+	name = emptyBracketsRegex.ReplaceAllString(name, "")
+	name = repeatedDashRegex.ReplaceAllString(name, " - ")
+	name = spacesRegex.ReplaceAllString(name, " ")
+	return strings.Trim(name, " .-")
+}
@@ -0,0 +1,305 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// TransferMode is how files are put into a library.
+type TransferMode string
+
+const (
+	// TransferAuto hardlinks (or reflinks) when the library is on the same
+	// filesystem as the download, and copies otherwise: with rsync when sudo
+	// is enabled, with the native copy engine if not.
+	TransferAuto     TransferMode = ""
+	TransferHardlink TransferMode = "hardlink" // Same file under a second name; seeding continues, no extra space
+	TransferReflink  TransferMode = "reflink"  // Copy-on-write clone (Btrfs, XFS); seeding continues, no extra space
+	TransferMove     TransferMode = "move"     // Atomic rename; the torrent loses its data
+	TransferRsync    TransferMode = "rsync"    // rsync copy, with sudo if enabled; needs rsync installed
+	TransferCopy     TransferMode = "copy"     // Native copy: resumable, synced, optionally verified
+)
+
+// ErrReflinkUnsupported indicates the platform can't clone files.
+var ErrReflinkUnsupported = errors.New("reflink not supported on this platform")
+
+// TransferModes lists the modes in the order they are offered.
+var TransferModes = []TransferMode{TransferAuto, TransferHardlink, TransferReflink, TransferMove, TransferRsync, TransferCopy}
+
+// ParseTransferMode reads a transfer mode from the config; empty means auto.
+func ParseTransferMode(s string) (TransferMode, error) {
+	for _, mode := range TransferModes {
+		if string(mode) == s {
+			return mode, nil
+		}
+	}
+	if s == "auto" {
+		return TransferAuto, nil
+	}
+	return TransferAuto, fmt.Errorf("unknown transfer mode %q (want hardlink, reflink, move, rsync or copy)", s)
+}
+
+// String returns the mode's name.
+func (t TransferMode) String() string {
+	if t == TransferAuto {
+		return "auto"
+	}
+	return string(t)
+}
+
+// Done describes a finished transfer, e.g. "Hardlinked".
+func (t TransferMode) Done() string {
+	switch t {
+	case TransferHardlink:
+		return "Hardlinked"
+	case TransferReflink:
+		return "Reflinked"
+	case TransferMove:
+		return "Moved"
+	default:
+		return "Copied"
+	}
+}
+
+// Linked reports whether the library file shares its data with the
+// source, so the torrent stops seeding if the source is deleted while the
+// library keeps the file.
+func (t TransferMode) Linked() bool {
+	return t == TransferHardlink || t == TransferReflink
+}
+
+// fileProgress turns the progress of one file into progress of the whole
+// operation. Episode fields are only set for TV.
+type fileProgress struct {
+	ch           chan<- MoveProgress
+	name         string
+	fileBytes    int64
+	totalBytes   int64
+	offset       int64 // Bytes of earlier files
+	episodeIndex int
+	episodeTotal int
+}
+
+// report sends progress without blocking, so a slow reader can't stall the
+// transfer.
+func (p fileProgress) report(copied int64, rate, eta string) {
+	if p.ch == nil {
+		return
+	}
+	copied = min(copied, p.fileBytes)
+	overall := min(p.offset+copied, p.totalBytes)
+	update := MoveProgress{
+		BytesCopied:  overall,
+		TotalBytes:   p.totalBytes,
+		Percentage:   1,
+		CurrentFile:  p.name,
+		Rate:         rate,
+		ETA:          eta,
+		EpisodeIndex: p.episodeIndex,
+		EpisodeTotal: p.episodeTotal,
+	}
+	if p.totalBytes > 0 {
+		update.Percentage = float64(overall) / float64(p.totalBytes)
+	}
+	if p.episodeTotal > 0 {
+		update.EpisodeProgress = 1
+		if p.fileBytes > 0 {
+			update.EpisodeProgress = float64(copied) / float64(p.fileBytes)
+		}
+	}
+	select {
+	case p.ch <- update:
+	default:
+	}
+}
+
+// transfer puts src at dst with the mover's transfer mode and returns the
+// mode used. In auto mode, a link that fails falls back to a copy.
+func (m *Mover) transfer(ctx context.Context, src, dst string, p fileProgress) (TransferMode, error) {
+	mode := m.config.TransferMode
+	if mode == TransferAuto {
+		if sameDevice(src, dst) {
+			for _, link := range []TransferMode{TransferHardlink, TransferReflink} {
+				if err := m.transferWith(ctx, link, src, dst, p); err == nil {
+					return link, nil
+				}
+			}
+		}
+		mode = m.config.copyMode()
+	}
+	return mode, m.transferWith(ctx, mode, src, dst, p)
+}
+
+// copyMode returns how auto mode copies: with rsync when sudo is on (the
+// only mode that can use it) and rsync is installed, natively otherwise.
+func (c MoveConfig) copyMode() TransferMode {
+	if c.UseSudo {
+		if _, err := exec.LookPath("rsync"); err == nil {
+			return TransferRsync
+		}
+	}
+	return TransferCopy
+}
+
+// UsesRsync reports whether files may be copied with rsync: in rsync mode,
+// or in auto mode when it copies with rsync.
+func (c MoveConfig) UsesRsync() bool {
+	return c.TransferMode == TransferRsync || (c.TransferMode == TransferAuto && c.copyMode() == TransferRsync)
+}
+
+// transferWith puts src at dst with one transfer mode.
+func (m *Mover) transferWith(ctx context.Context, mode TransferMode, src, dst string, p fileProgress) error {
+	if mode == TransferRsync {
+		return m.rsyncWithProgress(ctx, src, dst, p)
+	}
+
+	// rsync creates the directory itself, the others need it in place
+	if err := m.mkdirAll(filepath.Dir(dst)); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	var err error
+	switch mode {
+	case TransferHardlink:
+		if sameFile(src, dst) {
+			break // Linked by an earlier move
+		}
+		err = replaceWith(dst, func(tmp string) error { return os.Link(src, tmp) })
+	case TransferReflink:
+		err = replaceWith(dst, func(tmp string) error { return reflink(src, tmp) })
+	case TransferMove:
+		err = os.Rename(src, dst)
+	case TransferCopy:
+		return m.copyWithProgress(ctx, src, dst, p)
+	default:
+		return fmt.Errorf("unknown transfer mode %q", mode)
+	}
+	if err != nil {
+		return err
+	}
+	p.report(p.fileBytes, "", "")
+	return nil
+}
+
+// replaceWith creates a link next to dst and renames it over dst, so an
+// existing file is only replaced once the new one is in place.
+func replaceWith(dst string, create func(tmp string) error) error {
+	tmp := dst + ".link"
+	os.Remove(tmp) // Left over from an earlier attempt
+	if err := create(tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	os.Remove(dst + ".part") // A copy that was interrupted before
+	return nil
+}
+
+// copyWithProgress copies src to dst with the native copy engine,
+// reporting progress a few times a second.
+func (m *Mover) copyWithProgress(ctx context.Context, src, dst string, p fileProgress) error {
+	meter := rateMeter{total: p.fileBytes}
+	err := CopyFile(ctx, src, dst, CopyOptions{
+		Verify: m.config.Verify,
+		Progress: func(copied int64) {
+			if meter.due(copied) {
+				p.report(copied, meter.rate(copied), meter.eta(copied))
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	p.report(p.fileBytes, meter.rate(p.fileBytes), "0:00:00")
+	return nil
+}
+
+// rateMeter works out the rate and time left of a copy, which may have
+// resumed partway, and keeps progress reports to a few a second.
+type rateMeter struct {
+	total      int64
+	start      time.Time
+	first      int64 // Bytes in place when the copy started
+	lastReport time.Time
+}
+
+// due reports whether progress should be reported, at the start and then
+// every quarter second.
+func (r *rateMeter) due(copied int64) bool {
+	now := time.Now()
+	if r.start.IsZero() {
+		r.start, r.first, r.lastReport = now, copied, now
+		return true
+	}
+	if now.Sub(r.lastReport) < 250*time.Millisecond {
+		return false
+	}
+	r.lastReport = now
+	return true
+}
+
+// rate formats the transfer rate like rsync does, e.g. "10.50MB/s".
+func (r *rateMeter) rate(copied int64) string {
+	elapsed := time.Since(r.start).Seconds()
+	if r.start.IsZero() || elapsed <= 0 {
+		return ""
+	}
+	return formatRate(float64(copied-r.first) / elapsed)
+}
+
+// eta formats the time left like rsync does, e.g. "0:01:23".
+func (r *rateMeter) eta(copied int64) string {
+	elapsed := time.Since(r.start)
+	done := copied - r.first
+	if r.start.IsZero() || done <= 0 {
+		return ""
+	}
+	left := time.Duration(float64(r.total-copied) / float64(done) * float64(elapsed))
+	return formatETA(left)
+}
+
+func formatRate(bytesPerSec float64) string {
+	units := []string{"B/s", "kB/s", "MB/s", "GB/s"}
+	i := 0
+	for bytesPerSec >= 1024 && i < len(units)-1 {
+		bytesPerSec /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.2f%s", bytesPerSec, units[i])
+}
+
+func formatETA(d time.Duration) string {
+	s := int(max(d, 0).Seconds())
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// sameFile reports whether a and b are the same file.
+func sameFile(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	return err == nil && os.SameFile(ia, ib)
+}
+
+// existingDir returns path or its closest parent that exists, as a library
+// folder may not have been created yet.
+func existingDir(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
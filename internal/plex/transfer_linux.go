@@ -0,0 +1,42 @@
+package plex
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones src to dst with FICLONE, sharing their data blocks until
+// either is changed.
+func reflink(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd())); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// sameDevice reports whether src and the directory dst goes in are on the
+// same filesystem, so dst can be a link to src.
+func sameDevice(src, dst string) bool {
+	var a, b unix.Stat_t
+	if unix.Stat(src, &a) != nil || unix.Stat(existingDir(filepath.Dir(dst)), &b) != nil {
+		return false
+	}
+	return a.Dev == b.Dev
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package plex
+
+// reflink is only implemented on Linux.
+func reflink(src, dst string) error {
+	return ErrReflinkUnsupported
+}
+
+// sameDevice can't tell filesystems apart here, so auto mode copies.
+func sameDevice(src, dst string) bool {
+	return false
+}
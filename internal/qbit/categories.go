@@ -0,0 +1,92 @@
+package qbit
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Category is a qBittorrent category
+type Category struct {
+	Name     string `json:"name"`
+	SavePath string `json:"savePath"` // Empty = default save path
+}
+
+// Categories returns all categories keyed by name
+func (c *Client) Categories(ctx context.Context) (map[string]Category, error) {
+	categories := make(map[string]Category)
+	if err := c.getJSON(ctx, "/api/v2/torrents/categories", nil, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// CreateCategory creates a new category with an optional save path
+func (c *Client) CreateCategory(ctx context.Context, name, savePath string) error {
+	data := url.Values{}
+	data.Set("category", name)
+	data.Set("savePath", savePath)
+	return c.postForm(ctx, "/api/v2/torrents/createCategory", data)
+}
+
+// EditCategory changes the save path of an existing category
+func (c *Client) EditCategory(ctx context.Context, name, savePath string) error {
+	data := url.Values{}
+	data.Set("category", name)
+	data.Set("savePath", savePath)
+	return c.postForm(ctx, "/api/v2/torrents/editCategory", data)
+}
+
+// RemoveCategories deletes categories. Torrents in them become uncategorized.
+func (c *Client) RemoveCategories(ctx context.Context, names ...string) error {
+	data := url.Values{}
+	data.Set("categories", strings.Join(names, "\n"))
+	return c.postForm(ctx, "/api/v2/torrents/removeCategories", data)
+}
+
+// SetCategory assigns a category to torrents (empty category clears it)
+func (c *Client) SetCategory(ctx context.Context, hashes []string, category string) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("category", category)
+	return c.postForm(ctx, "/api/v2/torrents/setCategory", data)
+}
+
+// Tags returns all tags known to qBittorrent
+func (c *Client) Tags(ctx context.Context) ([]string, error) {
+	var tags []string
+	if err := c.getJSON(ctx, "/api/v2/torrents/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// CreateTags registers new tags without assigning them
+func (c *Client) CreateTags(ctx context.Context, tags ...string) error {
+	data := url.Values{}
+	data.Set("tags", strings.Join(tags, ","))
+	return c.postForm(ctx, "/api/v2/torrents/createTags", data)
+}
+
+// DeleteTags removes tags from qBittorrent and from every torrent carrying them
+func (c *Client) DeleteTags(ctx context.Context, tags ...string) error {
+	data := url.Values{}
+	data.Set("tags", strings.Join(tags, ","))
+	return c.postForm(ctx, "/api/v2/torrents/deleteTags", data)
+}
+
+// AddTags adds tags to torrents, creating any that don't exist yet
+func (c *Client) AddTags(ctx context.Context, hashes []string, tags ...string) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("tags", strings.Join(tags, ","))
+	return c.postForm(ctx, "/api/v2/torrents/addTags", data)
+}
+
+// RemoveTags removes tags from torrents
+func (c *Client) RemoveTags(ctx context.Context, hashes []string, tags ...string) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("tags", strings.Join(tags, ","))
+	return c.postForm(ctx, "/api/v2/torrents/removeTags", data)
+}
@@ -13,6 +13,9 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -44,6 +47,38 @@ type TorrentInfo struct {
 	AmountLeft     int64   `json:"amount_left"`
 	DownloadedEver int64   `json:"downloaded"`
 	UploadedEver   int64   `json:"uploaded"`
+	Ratio          float64 `json:"ratio"`
+	SeedingTime    int64   `json:"seeding_time"`       // Seconds spent seeding
+	RatioLimit     float64 `json:"ratio_limit"`        // -2 = global, -1 = none
+	SeedingLimit   int64   `json:"seeding_time_limit"` // Minutes, -2 = global, -1 = none
+	LimitAction    string  `json:"share_limit_action"` // What the share limits do (qBittorrent 5.1+, empty before)
+	Category       string  `json:"category"`
+	Tags           string  `json:"tags"`     // Comma-separated tag list
+	DlLimit        int64   `json:"dl_limit"` // bytes/s, <= 0 = unlimited
+	UpLimit        int64   `json:"up_limit"` // bytes/s, <= 0 = unlimited
+	QueuePosition  int     `json:"priority"` // 1-based, <= 0 when not queued
+	ForceStart     bool    `json:"force_start"`
+}
+
+// TagList returns the torrent's tags as a slice
+func (t TorrentInfo) TagList() []string {
+	var tags []string
+	for _, tag := range strings.Split(t.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the torrent carries the given tag
+func (t TorrentInfo) HasTag(tag string) bool {
+	for _, tt := range t.TagList() {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
 }
 
 // NewClient creates a new qBittorrent API client
@@ -121,28 +156,145 @@ func (c *Client) GetVersion(ctx context.Context) (string, error) {
 	return string(body), nil
 }
 
+// Content layouts accepted by AddTorrentOptions.ContentLayout
+const (
+	ContentLayoutOriginal    = "Original"
+	ContentLayoutSubfolder   = "Subfolder"
+	ContentLayoutNoSubfolder = "NoSubfolder"
+)
+
+// Stop conditions accepted by AddTorrentOptions.StopCondition (qBittorrent 4.5+)
+const (
+	StopConditionMetadataReceived = "MetadataReceived"
+	StopConditionFilesChecked     = "FilesChecked"
+)
+
+// AddTorrentOptions holds optional parameters for /torrents/add.
+// Zero values are not sent, so qBittorrent's own defaults apply.
+type AddTorrentOptions struct {
+	SavePath           string   // Download location (empty = qBittorrent default)
+	Category           string   // Category to assign
+	Tags               []string // Tags to assign
+	Paused             bool     // Add without starting
+	SkipChecking       bool     // Skip hash checking of existing data
+	SequentialDownload bool     // Download pieces in order
+	FirstLastPiecePrio bool     // Prioritize first and last pieces (for previews)
+	ContentLayout      string   // ContentLayoutOriginal, ContentLayoutSubfolder or ContentLayoutNoSubfolder
+	Rename             string   // Torrent name override
+	UpLimit            int64    // Upload limit in bytes/s
+	DlLimit            int64    // Download limit in bytes/s
+	RatioLimit         float64  // Share ratio limit (-1 = unlimited)
+	SeedingTimeLimit   int      // Seeding time limit in minutes (-1 = unlimited)
+	StopCondition      string   // Stop automatically once this is reached (StopCondition*)
+}
+
+// writeFields adds the non-default options to a multipart add request
+func (o AddTorrentOptions) writeFields(writer *multipart.Writer) {
+	if o.SavePath != "" {
+		_ = writer.WriteField("savepath", o.SavePath)
+	}
+	if o.Category != "" {
+		_ = writer.WriteField("category", o.Category)
+	}
+	if len(o.Tags) > 0 {
+		_ = writer.WriteField("tags", strings.Join(o.Tags, ","))
+	}
+	if o.Paused {
+		// qBittorrent 5 renamed "paused" to "stopped"; send both
+		_ = writer.WriteField("paused", "true")
+		_ = writer.WriteField("stopped", "true")
+	}
+	if o.SkipChecking {
+		_ = writer.WriteField("skip_checking", "true")
+	}
+	if o.SequentialDownload {
+		_ = writer.WriteField("sequentialDownload", "true")
+	}
+	if o.FirstLastPiecePrio {
+		_ = writer.WriteField("firstLastPiecePrio", "true")
+	}
+	if o.ContentLayout != "" {
+		_ = writer.WriteField("contentLayout", o.ContentLayout)
+	}
+	if o.Rename != "" {
+		_ = writer.WriteField("rename", o.Rename)
+	}
+	if o.UpLimit > 0 {
+		_ = writer.WriteField("upLimit", strconv.FormatInt(o.UpLimit, 10))
+	}
+	if o.DlLimit > 0 {
+		_ = writer.WriteField("dlLimit", strconv.FormatInt(o.DlLimit, 10))
+	}
+	if o.RatioLimit != 0 {
+		_ = writer.WriteField("ratioLimit", strconv.FormatFloat(o.RatioLimit, 'f', -1, 64))
+	}
+	if o.SeedingTimeLimit != 0 {
+		_ = writer.WriteField("seedingTimeLimit", strconv.Itoa(o.SeedingTimeLimit))
+	}
+	if o.StopCondition != "" {
+		_ = writer.WriteField("stopCondition", o.StopCondition)
+	}
+}
+
 // AddMagnet adds a torrent via magnet link
 func (c *Client) AddMagnet(ctx context.Context, magnet string, savePath string) error {
-	if !c.loggedIn {
-		if err := c.Login(ctx); err != nil {
-			return err
-		}
+	return c.AddURLs(ctx, []string{magnet}, AddTorrentOptions{SavePath: savePath})
+}
+
+// AddURLs adds torrents from magnet links or http(s) URLs pointing at .torrent files.
+// qBittorrent downloads remote .torrent files itself.
+func (c *Client) AddURLs(ctx context.Context, urls []string, opts AddTorrentOptions) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	_ = writer.WriteField("urls", strings.Join(urls, "\n"))
+	opts.writeFields(writer)
+	writer.Close()
+
+	return c.postAdd(ctx, &body, writer.FormDataContentType())
+}
+
+// AddTorrentFile uploads a local .torrent file
+func (c *Client) AddTorrentFile(ctx context.Context, path string, opts AddTorrentOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open torrent file: %w", err)
 	}
+	defer f.Close()
+
+	return c.AddTorrentReader(ctx, filepath.Base(path), f, opts)
+}
 
+// AddTorrentReader uploads .torrent file contents read from r.
+// The filename is sent as the multipart file name.
+func (c *Client) AddTorrentReader(ctx context.Context, filename string, r io.Reader, opts AddTorrentOptions) error {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
-	_ = writer.WriteField("urls", magnet)
-	if savePath != "" {
-		_ = writer.WriteField("savepath", savePath)
+	part, err := writer.CreateFormFile("torrents", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("read torrent file: %w", err)
 	}
+	opts.writeFields(writer)
 	writer.Close()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/torrents/add", &body)
+	return c.postAdd(ctx, &body, writer.FormDataContentType())
+}
+
+// postAdd sends a prepared multipart body to /torrents/add
+func (c *Client) postAdd(ctx context.Context, body io.Reader, contentType string) error {
+	if err := c.ensureLogin(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/torrents/add", body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -150,10 +302,14 @@ func (c *Client) AddMagnet(ctx context.Context, magnet string, savePath string)
 	}
 	defer resp.Body.Close()
 
+	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to add torrent: %s", string(respBody))
 	}
+	// qBittorrent answers 200 "Fails." when it rejects the torrent (e.g. invalid file)
+	if strings.TrimSpace(string(respBody)) == "Fails." {
+		return fmt.Errorf("qBittorrent rejected the torrent")
+	}
 
 	return nil
 }
@@ -185,37 +341,123 @@ func (c *Client) GetTorrents(ctx context.Context) ([]TorrentInfo, error) {
 	return torrents, nil
 }
 
-// Pause pauses a torrent
-func (c *Client) Pause(ctx context.Context, hash string) error {
-	return c.torrentAction(ctx, "pause", hash)
+// GetTorrentsByHash returns info for the given torrents. Unknown hashes are
+// silently left out of the result.
+func (c *Client) GetTorrentsByHash(ctx context.Context, hashes []string) ([]TorrentInfo, error) {
+	query := url.Values{}
+	query.Set("hashes", joinHashes(hashes))
+
+	var torrents []TorrentInfo
+	if err := c.getJSON(ctx, "/api/v2/torrents/info", query, &torrents); err != nil {
+		return nil, err
+	}
+	return torrents, nil
+}
+
+// Pause pauses torrents
+func (c *Client) Pause(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "pause", hashes)
 }
 
-// Resume resumes a torrent
-func (c *Client) Resume(ctx context.Context, hash string) error {
-	return c.torrentAction(ctx, "resume", hash)
+// Resume resumes torrents
+func (c *Client) Resume(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "resume", hashes)
 }
 
-// Delete removes a torrent (optionally with files)
-func (c *Client) Delete(ctx context.Context, hash string, deleteFiles bool) error {
-	if !c.loggedIn {
-		if err := c.Login(ctx); err != nil {
-			return err
-		}
-	}
+// Recheck forces a hash check of torrents
+func (c *Client) Recheck(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "recheck", hashes)
+}
+
+// Reannounce forces torrents to reannounce to their trackers
+func (c *Client) Reannounce(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "reannounce", hashes)
+}
+
+// QueueUp moves torrents one position up the queue
+func (c *Client) QueueUp(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "increasePrio", hashes)
+}
 
+// QueueDown moves torrents one position down the queue
+func (c *Client) QueueDown(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "decreasePrio", hashes)
+}
+
+// QueueTop moves torrents to the top of the queue
+func (c *Client) QueueTop(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "topPrio", hashes)
+}
+
+// QueueBottom moves torrents to the bottom of the queue
+func (c *Client) QueueBottom(ctx context.Context, hashes ...string) error {
+	return c.torrentAction(ctx, "bottomPrio", hashes)
+}
+
+// SetForceStart starts torrents regardless of the queue (or returns them to it)
+func (c *Client) SetForceStart(ctx context.Context, hashes []string, force bool) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("value", strconv.FormatBool(force))
+	return c.postForm(ctx, "/api/v2/torrents/setForceStart", data)
+}
+
+// SetLocation moves torrents' data to a new save path. qBittorrent moves
+// the files in the background and keeps seeding from the new location.
+func (c *Client) SetLocation(ctx context.Context, hashes []string, location string) error {
 	data := url.Values{}
-	data.Set("hashes", hash)
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("location", location)
+	return c.postForm(ctx, "/api/v2/torrents/setLocation", data)
+}
+
+// Delete removes torrents (optionally with files)
+func (c *Client) Delete(ctx context.Context, hashes []string, deleteFiles bool) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
 	if deleteFiles {
 		data.Set("deleteFiles", "true")
 	} else {
 		data.Set("deleteFiles", "false")
 	}
+	return c.postForm(ctx, "/api/v2/torrents/delete", data)
+}
+
+// torrentAction sends a hashes-only action for all torrents in one request
+func (c *Client) torrentAction(ctx context.Context, action string, hashes []string) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	return c.postForm(ctx, "/api/v2/torrents/"+action, data)
+}
+
+// joinHashes formats hashes for the API's pipe-separated hashes parameter
+func joinHashes(hashes []string) string {
+	return strings.Join(hashes, "|")
+}
+
+// ensureLogin logs in if the client has no session yet
+func (c *Client) ensureLogin(ctx context.Context) error {
+	if c.loggedIn {
+		return nil
+	}
+	return c.Login(ctx)
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response into out
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	if err := c.ensureLogin(ctx); err != nil {
+		return err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/torrents/delete", strings.NewReader(data.Encode()))
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -223,20 +465,26 @@ func (c *Client) Delete(ctx context.Context, hash string, deleteFiles bool) erro
 	}
 	defer resp.Body.Close()
 
-	return nil
+	if resp.StatusCode == http.StatusForbidden {
+		// Session expired - force a fresh login on the next call
+		c.loggedIn = false
+		return fmt.Errorf("%s: not authorized", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (c *Client) torrentAction(ctx context.Context, action, hash string) error {
-	if !c.loggedIn {
-		if err := c.Login(ctx); err != nil {
-			return err
-		}
+// postForm performs an authenticated form POST and discards the response body
+func (c *Client) postForm(ctx context.Context, path string, data url.Values) error {
+	if err := c.ensureLogin(ctx); err != nil {
+		return err
 	}
 
-	data := url.Values{}
-	data.Set("hashes", hash)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v2/torrents/"+action, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -248,5 +496,14 @@ func (c *Client) torrentAction(ctx context.Context, action, hash string) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusForbidden {
+		c.loggedIn = false
+		return fmt.Errorf("%s: not authorized", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
 	return nil
 }
@@ -0,0 +1,299 @@
+package qbit
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TorrentProperties holds the generic properties of a single torrent
+type TorrentProperties struct {
+	SavePath           string  `json:"save_path"`
+	CreationDate       int64   `json:"creation_date"` // Unix time, -1 if unknown
+	PieceSize          int64   `json:"piece_size"`
+	Comment            string  `json:"comment"`
+	CreatedBy          string  `json:"created_by"`
+	TotalWasted        int64   `json:"total_wasted"`
+	TotalUploaded      int64   `json:"total_uploaded"`
+	TotalDownloaded    int64   `json:"total_downloaded"`
+	UpLimit            int64   `json:"up_limit"` // bytes/s, -1 = unlimited
+	DlLimit            int64   `json:"dl_limit"` // bytes/s, -1 = unlimited
+	TimeElapsed        int64   `json:"time_elapsed"`
+	SeedingTime        int64   `json:"seeding_time"`
+	NbConnections      int     `json:"nb_connections"`
+	NbConnectionsLimit int     `json:"nb_connections_limit"`
+	ShareRatio         float64 `json:"share_ratio"`
+	AdditionDate       int64   `json:"addition_date"`
+	CompletionDate     int64   `json:"completion_date"` // -1 if not completed
+	LastSeen           int64   `json:"last_seen"`       // Last time a seed was seen
+	DlSpeedAvg         int64   `json:"dl_speed_avg"`
+	UpSpeedAvg         int64   `json:"up_speed_avg"`
+	Eta                int64   `json:"eta"` // Seconds
+	Seeds              int     `json:"seeds"`
+	SeedsTotal         int     `json:"seeds_total"`
+	Peers              int     `json:"peers"`
+	PeersTotal         int     `json:"peers_total"`
+	PiecesHave         int     `json:"pieces_have"`
+	PiecesNum          int     `json:"pieces_num"`
+	Reannounce         int64   `json:"reannounce"` // Seconds until next announce
+	TotalSize          int64   `json:"total_size"`
+}
+
+// TorrentFile is a file inside a torrent
+type TorrentFile struct {
+	Index        int     `json:"index"`
+	Name         string  `json:"name"` // Path relative to the torrent root, "/" separated
+	Size         int64   `json:"size"`
+	Progress     float64 `json:"progress"`
+	Priority     int     `json:"priority"`
+	IsSeed       bool    `json:"is_seed"`
+	PieceRange   []int   `json:"piece_range"`
+	Availability float64 `json:"availability"`
+}
+
+// File priorities accepted by SetFilePriority
+const (
+	FilePrioritySkip   = 0 // Do not download
+	FilePriorityNormal = 1
+	FilePriorityHigh   = 6
+	FilePriorityMax    = 7
+)
+
+// Tracker status values reported by /torrents/trackers
+const (
+	TrackerDisabled     = 0 // Used for DHT, PeX and LSD
+	TrackerNotContacted = 1
+	TrackerWorking      = 2
+	TrackerUpdating     = 3
+	TrackerNotWorking   = 4
+)
+
+// Tracker is a tracker (or DHT/PeX/LSD pseudo-tracker) of a torrent
+type Tracker struct {
+	URL           string `json:"url"`
+	Status        int    `json:"status"`
+	NumPeers      int    `json:"num_peers"`
+	NumSeeds      int    `json:"num_seeds"`
+	NumLeeches    int    `json:"num_leeches"`
+	NumDownloaded int    `json:"num_downloaded"`
+	Msg           string `json:"msg"`
+}
+
+// StatusString returns a short description of the tracker status
+func (t Tracker) StatusString() string {
+	switch t.Status {
+	case TrackerDisabled:
+		return "Disabled"
+	case TrackerNotContacted:
+		return "Not contacted"
+	case TrackerWorking:
+		return "Working"
+	case TrackerUpdating:
+		return "Updating"
+	case TrackerNotWorking:
+		return "Not working"
+	}
+	return "Unknown"
+}
+
+// Peer is a peer connected to a torrent
+type Peer struct {
+	Address     string  `json:"-"` // ip:port, the key in /sync/torrentPeers
+	IP          string  `json:"ip"`
+	Port        int     `json:"port"`
+	Client      string  `json:"client"`
+	Connection  string  `json:"connection"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code"`
+	Flags       string  `json:"flags"`
+	FlagsDesc   string  `json:"flags_desc"`
+	Progress    float64 `json:"progress"`
+	DLSpeed     int64   `json:"dl_speed"`
+	UPSpeed     int64   `json:"up_speed"`
+	Downloaded  int64   `json:"downloaded"`
+	Uploaded    int64   `json:"uploaded"`
+	Relevance   float64 `json:"relevance"`
+}
+
+// Piece states reported by /torrents/pieceStates
+const (
+	PieceMissing     = 0
+	PieceDownloading = 1
+	PieceDownloaded  = 2
+)
+
+// hashQuery builds the hash= query used by the per-torrent endpoints
+func hashQuery(hash string) url.Values {
+	query := url.Values{}
+	query.Set("hash", hash)
+	return query
+}
+
+// Properties returns the generic properties of a torrent
+func (c *Client) Properties(ctx context.Context, hash string) (*TorrentProperties, error) {
+	var props TorrentProperties
+	if err := c.getJSON(ctx, "/api/v2/torrents/properties", hashQuery(hash), &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// Files returns the files of a torrent in index order
+func (c *Client) Files(ctx context.Context, hash string) ([]TorrentFile, error) {
+	var files []TorrentFile
+	if err := c.getJSON(ctx, "/api/v2/torrents/files", hashQuery(hash), &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// SetFilePriority sets the download priority of files (by TorrentFile.Index)
+func (c *Client) SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error {
+	ids := make([]string, len(indexes))
+	for i, idx := range indexes {
+		ids[i] = strconv.Itoa(idx)
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("id", strings.Join(ids, "|"))
+	data.Set("priority", strconv.Itoa(priority))
+	return c.postForm(ctx, "/api/v2/torrents/filePrio", data)
+}
+
+// RenameFile renames (or moves) a file of a torrent. Paths are relative to
+// its save path, "/" separated, as TorrentFile.Name.
+func (c *Client) RenameFile(ctx context.Context, hash, oldPath, newPath string) error {
+	return c.postForm(ctx, "/api/v2/torrents/renameFile", renameForm(hash, oldPath, newPath))
+}
+
+// RenameFolder renames a folder of a torrent, e.g. its root folder
+func (c *Client) RenameFolder(ctx context.Context, hash, oldPath, newPath string) error {
+	return c.postForm(ctx, "/api/v2/torrents/renameFolder", renameForm(hash, oldPath, newPath))
+}
+
+func renameForm(hash, oldPath, newPath string) url.Values {
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("oldPath", oldPath)
+	data.Set("newPath", newPath)
+	return data
+}
+
+// Trackers returns the trackers of a torrent
+func (c *Client) Trackers(ctx context.Context, hash string) ([]Tracker, error) {
+	var trackers []Tracker
+	if err := c.getJSON(ctx, "/api/v2/torrents/trackers", hashQuery(hash), &trackers); err != nil {
+		return nil, err
+	}
+	return trackers, nil
+}
+
+// PieceStates returns the download state of every piece (Piece* constants)
+func (c *Client) PieceStates(ctx context.Context, hash string) ([]int, error) {
+	var states []int
+	if err := c.getJSON(ctx, "/api/v2/torrents/pieceStates", hashQuery(hash), &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// PeerData is a single /sync/torrentPeers response. Like MainData, partial
+// updates only carry the fields that changed.
+type PeerData struct {
+	RID          int64                      `json:"rid"`
+	FullUpdate   bool                       `json:"full_update"`
+	Peers        map[string]json.RawMessage `json:"peers"`
+	PeersRemoved []string                   `json:"peers_removed"`
+}
+
+// TorrentPeers fetches peer changes for a torrent since rid (0 = full snapshot)
+func (c *Client) TorrentPeers(ctx context.Context, hash string, rid int64) (*PeerData, error) {
+	query := hashQuery(hash)
+	query.Set("rid", strconv.FormatInt(rid, 10))
+
+	var data PeerData
+	if err := c.getJSON(ctx, "/api/v2/sync/torrentPeers", query, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// PeerSync keeps a local copy of one torrent's peer list, updated
+// incrementally like Sync. It is safe for concurrent use.
+type PeerSync struct {
+	client *Client
+	hash   string
+
+	mu    sync.Mutex
+	rid   int64
+	peers map[string]Peer
+}
+
+// NewPeerSync creates an empty peer store for a torrent
+func NewPeerSync(client *Client, hash string) *PeerSync {
+	return &PeerSync{
+		client: client,
+		hash:   hash,
+		peers:  make(map[string]Peer),
+	}
+}
+
+// Hash returns the torrent the store tracks
+func (s *PeerSync) Hash() string {
+	return s.hash
+}
+
+// Update pulls the latest peer changes and merges them into the store
+func (s *PeerSync) Update(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.client.TorrentPeers(ctx, s.hash, s.rid)
+	if err != nil {
+		s.rid = 0
+		return err
+	}
+
+	if data.FullUpdate {
+		s.peers = make(map[string]Peer, len(data.Peers))
+	}
+	for addr, raw := range data.Peers {
+		p := s.peers[addr]
+		if err := json.Unmarshal(raw, &p); err != nil {
+			continue
+		}
+		p.Address = addr
+		s.peers[addr] = p
+	}
+	for _, addr := range data.PeersRemoved {
+		delete(s.peers, addr)
+	}
+	s.rid = data.RID
+	return nil
+}
+
+// Peers returns a snapshot of the peers, fastest downloads first
+func (s *PeerSync) Peers() []Peer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	peers := make([]Peer, 0, len(s.peers))
+	for _, p := range s.peers {
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].DLSpeed != peers[j].DLSpeed {
+			return peers[i].DLSpeed > peers[j].DLSpeed
+		}
+		if peers[i].UPSpeed != peers[j].UPSpeed {
+			return peers[i].UPSpeed > peers[j].UPSpeed
+		}
+		return peers[i].Address < peers[j].Address
+	})
+	return peers
+}
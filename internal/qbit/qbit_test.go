@@ -1,7 +1,495 @@
 package qbit
 
-import "testing"
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
 
 func TestPackageCompiles(t *testing.T) {
 	// placeholder
 }
+
+// newTestClient starts a fake qBittorrent server and returns a client for it.
+// The handler receives every request except /api/v2/auth/login, which always succeeds.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Ok."))
+	})
+	mux.HandleFunc("/", handler)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return NewClient(host, port, "admin", "adminadmin")
+}
+
+func TestSyncAppliesDeltas(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,
+			"torrents":{
+				"aaa":{"name":"first","progress":0.5,"state":"downloading","dlspeed":100},
+				"bbb":{"name":"second","progress":1,"state":"uploading"}
+			},
+			"server_state":{"dl_info_speed":100,"free_space_on_disk":5000,"dht_nodes":42}}`,
+		"1": `{"rid":2,
+			"torrents":{"aaa":{"progress":0.75},"ccc":{"name":"third","state":"metaDL"}},
+			"torrents_removed":["bbb"],
+			"server_state":{"dl_info_speed":250}}`,
+	}
+
+	var rids []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/sync/maindata" {
+			http.NotFound(w, r)
+			return
+		}
+		rid := r.URL.Query().Get("rid")
+		rids = append(rids, rid)
+		_, _ = w.Write([]byte(responses[rid]))
+	})
+
+	store := NewSync(client)
+	ctx := context.Background()
+	if err := store.Update(ctx); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if got := len(store.Torrents()); got != 2 {
+		t.Fatalf("after full update: got %d torrents, want 2", got)
+	}
+
+	if err := store.Update(ctx); err != nil {
+		t.Fatalf("second update: %v", err)
+	}
+
+	torrents := store.Torrents()
+	if len(torrents) != 2 {
+		t.Fatalf("after delta: got %d torrents, want 2", len(torrents))
+	}
+	first := torrents[0]
+	if first.Hash != "aaa" || first.Name != "first" || first.Progress != 0.75 || first.DLSpeed != 100 {
+		t.Errorf("delta merge lost fields: %+v", first)
+	}
+	if torrents[1].Hash != "ccc" || torrents[1].State != "metaDL" {
+		t.Errorf("new torrent not added: %+v", torrents[1])
+	}
+
+	state := store.ServerState()
+	if state.DLInfoSpeed != 250 || state.FreeSpaceOnDisk != 5000 || state.DHTNodes != 42 {
+		t.Errorf("server state not merged: %+v", state)
+	}
+
+	if len(rids) != 2 || rids[0] != "0" || rids[1] != "1" {
+		t.Errorf("unexpected rid sequence: %v", rids)
+	}
+}
+
+func TestSyncResetsRIDOnError(t *testing.T) {
+	fail := true
+	var lastRID string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		lastRID = r.URL.Query().Get("rid")
+		if fail {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"rid":7,"full_update":true,"torrents":{}}`))
+	})
+
+	store := NewSync(client)
+	store.rid = 5
+	if err := store.Update(context.Background()); err == nil {
+		t.Fatal("expected error from failing server")
+	}
+
+	fail = false
+	if err := store.Update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if lastRID != "0" {
+		t.Errorf("expected full resync after error, got rid=%s", lastRID)
+	}
+}
+
+func TestAddTorrentReaderUploadsMultipart(t *testing.T) {
+	var gotName, gotContent, gotSavePath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/torrents/add" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("torrents")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		gotName = header.Filename
+		gotContent = string(data)
+		gotSavePath = r.FormValue("savepath")
+		_, _ = w.Write([]byte("Ok."))
+	})
+
+	err := client.AddTorrentReader(context.Background(), "linux.torrent",
+		strings.NewReader("d4:infod4:name5:linuxee"), AddTorrentOptions{SavePath: "/data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "linux.torrent" || gotContent != "d4:infod4:name5:linuxee" || gotSavePath != "/data" {
+		t.Errorf("unexpected upload: name=%q content=%q savepath=%q", gotName, gotContent, gotSavePath)
+	}
+}
+
+func TestAddReportsRejectedTorrent(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Fails."))
+	})
+
+	err := client.AddURLs(context.Background(), []string{"https://example.local/x.torrent"}, AddTorrentOptions{})
+	if err == nil {
+		t.Fatal("expected error when qBittorrent answers Fails.")
+	}
+}
+
+func TestAddTorrentOptionsFields(t *testing.T) {
+	var form map[string][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		form = r.MultipartForm.Value
+		_, _ = w.Write([]byte("Ok."))
+	})
+
+	opts := AddTorrentOptions{
+		Category:           "tv",
+		Tags:               []string{"a", "b"},
+		Paused:             true,
+		SequentialDownload: true,
+		ContentLayout:      ContentLayoutSubfolder,
+		DlLimit:            2048,
+		RatioLimit:         1.5,
+		SeedingTimeLimit:   60,
+		StopCondition:      StopConditionMetadataReceived,
+	}
+	if err := client.AddURLs(context.Background(), []string{"magnet:?xt=urn:btih:abc"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"urls":               "magnet:?xt=urn:btih:abc",
+		"category":           "tv",
+		"tags":               "a,b",
+		"paused":             "true",
+		"stopped":            "true",
+		"sequentialDownload": "true",
+		"contentLayout":      "Subfolder",
+		"dlLimit":            "2048",
+		"ratioLimit":         "1.5",
+		"seedingTimeLimit":   "60",
+		"stopCondition":      "MetadataReceived",
+	}
+	for key, value := range want {
+		if got := form[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %v, want %q", key, got, value)
+		}
+	}
+	for _, key := range []string{"savepath", "skip_checking", "upLimit", "rename", "firstLastPiecePrio"} {
+		if _, ok := form[key]; ok {
+			t.Errorf("unset option %s was sent", key)
+		}
+	}
+}
+
+func TestTorrentTagList(t *testing.T) {
+	info := TorrentInfo{Tags: "hd, keep,,  linux "}
+	got := info.TagList()
+	if len(got) != 3 || got[0] != "hd" || got[1] != "keep" || got[2] != "linux" {
+		t.Errorf("TagList() = %q", got)
+	}
+	if !info.HasTag("keep") || info.HasTag("kee") {
+		t.Error("HasTag matched incorrectly")
+	}
+}
+
+func TestSetCategoryAndTags(t *testing.T) {
+	forms := make(map[string]url.Values)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		forms[r.URL.Path] = r.PostForm
+	})
+
+	ctx := context.Background()
+	if err := client.SetCategory(ctx, []string{"aaa", "bbb"}, "movies"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddTags(ctx, []string{"aaa"}, "hd", "keep"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := forms["/api/v2/torrents/setCategory"]; got.Get("hashes") != "aaa|bbb" || got.Get("category") != "movies" {
+		t.Errorf("setCategory form = %v", got)
+	}
+	if got := forms["/api/v2/torrents/addTags"]; got.Get("hashes") != "aaa" || got.Get("tags") != "hd,keep" {
+		t.Errorf("addTags form = %v", got)
+	}
+}
+
+func TestSyncTracksCategoriesAndTags(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,
+			"categories":{"movies":{"name":"movies","savePath":"/m"},"tv":{"name":"tv","savePath":""}},
+			"tags":["keep","hd"]}`,
+		"1": `{"rid":2,
+			"categories":{"tv":{"savePath":"/tv"}},
+			"categories_removed":["movies"],
+			"tags_removed":["hd"]}`,
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Query().Get("rid")]))
+	})
+
+	store := NewSync(client)
+	for i := 0; i < 2; i++ {
+		if err := store.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cats := store.Categories()
+	if len(cats) != 1 || cats["tv"].SavePath != "/tv" || cats["tv"].Name != "tv" {
+		t.Errorf("categories = %+v", cats)
+	}
+	if tags := store.Tags(); len(tags) != 1 || tags[0] != "keep" {
+		t.Errorf("tags = %v", tags)
+	}
+}
+
+func TestBulkActionsSendOneRequest(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+	})
+
+	ctx := context.Background()
+	if err := client.Pause(ctx, "a", "b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Delete(ctx, []string{"a", "b"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"/api/v2/torrents/pause?hashes=a%7Cb%7Cc",
+		"/api/v2/torrents/delete?deleteFiles=true&hashes=a%7Cb",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %v", len(requests), len(want), requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %s, want %s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestSetFilePriority(t *testing.T) {
+	var request string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request = r.URL.Path + "?" + r.PostForm.Encode()
+	})
+
+	if err := client.SetFilePriority(context.Background(), "abc", []int{0, 2}, FilePriorityHigh); err != nil {
+		t.Fatal(err)
+	}
+	want := "/api/v2/torrents/filePrio?hash=abc&id=0%7C2&priority=6"
+	if request != want {
+		t.Errorf("request = %s, want %s", request, want)
+	}
+}
+
+func TestRelocate(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+	})
+
+	ctx := context.Background()
+	if err := client.RenameFolder(ctx, "abc", "Show.S01", "Season 01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RenameFile(ctx, "abc", "Season 01/a.mkv", "Season 01/b.mkv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetLocation(ctx, []string{"abc", "def"}, "/media/TV/Show"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/api/v2/torrents/renameFolder?hash=abc&newPath=Season+01&oldPath=Show.S01",
+		"/api/v2/torrents/renameFile?hash=abc&newPath=Season+01%2Fb.mkv&oldPath=Season+01%2Fa.mkv",
+		"/api/v2/torrents/setLocation?hashes=abc%7Cdef&location=%2Fmedia%2FTV%2FShow",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n%s\nwant:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPeerSyncMergesDeltas(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,"peers":{
+			"1.2.3.4:5000":{"client":"qBittorrent 4.6","dl_speed":10,"progress":0.5},
+			"5.6.7.8:6000":{"client":"Transmission","dl_speed":50}}}`,
+		"1": `{"rid":2,"peers":{"1.2.3.4:5000":{"dl_speed":90}},"peers_removed":["5.6.7.8:6000"]}`,
+	}
+	var hash string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hash = r.URL.Query().Get("hash")
+		_, _ = w.Write([]byte(responses[r.URL.Query().Get("rid")]))
+	})
+
+	store := NewPeerSync(client, "abc")
+	ctx := context.Background()
+	if err := store.Update(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if peers := store.Peers(); len(peers) != 2 || peers[0].Client != "Transmission" {
+		t.Fatalf("expected fastest peer first, got %+v", peers)
+	}
+	if err := store.Update(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	peers := store.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("got %d peers, want 1", len(peers))
+	}
+	p := peers[0]
+	if p.Address != "1.2.3.4:5000" || p.DLSpeed != 90 || p.Client != "qBittorrent 4.6" || p.Progress != 0.5 {
+		t.Errorf("delta merge lost fields: %+v", p)
+	}
+	if hash != "abc" {
+		t.Errorf("hash = %q", hash)
+	}
+}
+
+func TestSpeedLimits(t *testing.T) {
+	var requests []string
+	altMode := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/transfer/speedLimitsMode":
+			_, _ = io.WriteString(w, strconv.Itoa(altMode))
+			return
+		case "/api/v2/transfer/toggleSpeedLimitsMode":
+			altMode = 1 - altMode
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+	})
+
+	ctx := context.Background()
+	if err := client.SetDownloadLimit(ctx, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetTorrentUploadLimit(ctx, []string{"a", "b"}, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetShareLimits(ctx, []string{"a"}, 2, 60, LimitActionStop); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ToggleSpeedLimitsMode(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if alt, err := client.SpeedLimitsMode(ctx); err != nil || !alt {
+		t.Errorf("SpeedLimitsMode = %v, %v, want true", alt, err)
+	}
+
+	want := []string{
+		"/api/v2/transfer/setDownloadLimit?limit=1024",
+		"/api/v2/torrents/setUploadLimit?hashes=a%7Cb&limit=0",
+		"/api/v2/torrents/setShareLimits?hashes=a&inactiveSeedingTimeLimit=-2&ratioLimit=2&seedingTimeLimit=60&shareLimitAction=Stop",
+		"/api/v2/transfer/toggleSpeedLimitsMode?",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %v", len(requests), len(want), requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %s, want %s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestQueueActions(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+	})
+
+	ctx := context.Background()
+	if err := client.QueueTop(ctx, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.QueueDown(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetForceStart(ctx, []string{"c"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"/api/v2/torrents/topPrio?hashes=a%7Cb",
+		"/api/v2/torrents/decreasePrio?hashes=a",
+		"/api/v2/torrents/setForceStart?hashes=c&value=true",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %v", len(requests), len(want), requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %s, want %s", i, requests[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package qbit
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ServerState holds the global transfer state reported by /sync/maindata
+type ServerState struct {
+	DLInfoSpeed       int64  `json:"dl_info_speed"`        // Global download rate (bytes/s)
+	DLInfoData        int64  `json:"dl_info_data"`         // Bytes downloaded this session
+	UPInfoSpeed       int64  `json:"up_info_speed"`        // Global upload rate (bytes/s)
+	UPInfoData        int64  `json:"up_info_data"`         // Bytes uploaded this session
+	DLRateLimit       int64  `json:"dl_rate_limit"`        // Global download limit (0 = unlimited)
+	UPRateLimit       int64  `json:"up_rate_limit"`        // Global upload limit (0 = unlimited)
+	DHTNodes          int64  `json:"dht_nodes"`            // Connected DHT nodes
+	ConnectionStatus  string `json:"connection_status"`    // connected, firewalled or disconnected
+	FreeSpaceOnDisk   int64  `json:"free_space_on_disk"`   // Free space in the default save path
+	UseAltSpeedLimits bool   `json:"use_alt_speed_limits"` // Alternative ("turtle") limits active
+	Queueing          bool   `json:"queueing"`             // Torrent queueing enabled
+}
+
+// MainData is a single /sync/maindata response.
+// When FullUpdate is false, Torrents only contains the fields that changed
+// since the previous RID, so entries are kept as raw JSON for merging.
+type MainData struct {
+	RID               int64                      `json:"rid"`
+	FullUpdate        bool                       `json:"full_update"`
+	Torrents          map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved   []string                   `json:"torrents_removed"`
+	Categories        map[string]json.RawMessage `json:"categories"`
+	CategoriesRemoved []string                   `json:"categories_removed"`
+	Tags              []string                   `json:"tags"`
+	TagsRemoved       []string                   `json:"tags_removed"`
+	ServerState       json.RawMessage            `json:"server_state"`
+}
+
+// MainData fetches the changes since rid (0 requests a full snapshot)
+func (c *Client) MainData(ctx context.Context, rid int64) (*MainData, error) {
+	query := url.Values{}
+	query.Set("rid", strconv.FormatInt(rid, 10))
+
+	var data MainData
+	if err := c.getJSON(ctx, "/api/v2/sync/maindata", query, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Sync keeps a local copy of qBittorrent's torrents, categories, tags and server state,
+// updated incrementally via the rid-based /sync/maindata endpoint.
+// It is safe for concurrent use.
+type Sync struct {
+	client *Client
+
+	mu         sync.Mutex
+	rid        int64
+	torrents   map[string]TorrentInfo
+	categories map[string]Category
+	tags       map[string]bool
+	server     ServerState
+}
+
+// NewSync creates an empty sync store backed by the client.
+// The first Update performs a full fetch; later calls only transfer deltas.
+func NewSync(client *Client) *Sync {
+	return &Sync{
+		client:     client,
+		torrents:   make(map[string]TorrentInfo),
+		categories: make(map[string]Category),
+		tags:       make(map[string]bool),
+	}
+}
+
+// Update pulls the latest changes from qBittorrent and merges them into the store
+func (s *Sync) Update(ctx context.Context) error {
+	// Held for the whole round trip so concurrent callers can't apply
+	// two deltas computed against the same rid
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.client.MainData(ctx, s.rid)
+	if err != nil {
+		// Start over with a full snapshot next time rather than risk
+		// applying a delta to a store that missed an update
+		s.rid = 0
+		return err
+	}
+	s.apply(data)
+	return nil
+}
+
+// apply merges a maindata response into the store. Caller must hold s.mu.
+func (s *Sync) apply(data *MainData) {
+	if data.FullUpdate {
+		s.torrents = make(map[string]TorrentInfo, len(data.Torrents))
+		s.categories = make(map[string]Category, len(data.Categories))
+		s.tags = make(map[string]bool, len(data.Tags))
+		s.server = ServerState{}
+	}
+
+	for hash, raw := range data.Torrents {
+		// Unmarshalling onto the existing entry only overwrites the
+		// fields present in the delta
+		t := s.torrents[hash]
+		if err := json.Unmarshal(raw, &t); err != nil {
+			continue
+		}
+		t.Hash = hash
+		s.torrents[hash] = t
+	}
+
+	for _, hash := range data.TorrentsRemoved {
+		delete(s.torrents, hash)
+	}
+
+	for name, raw := range data.Categories {
+		cat := s.categories[name]
+		if err := json.Unmarshal(raw, &cat); err != nil {
+			continue
+		}
+		cat.Name = name
+		s.categories[name] = cat
+	}
+	for _, name := range data.CategoriesRemoved {
+		delete(s.categories, name)
+	}
+
+	for _, tag := range data.Tags {
+		s.tags[tag] = true
+	}
+	for _, tag := range data.TagsRemoved {
+		delete(s.tags, tag)
+	}
+
+	if len(data.ServerState) > 0 {
+		_ = json.Unmarshal(data.ServerState, &s.server)
+	}
+
+	s.rid = data.RID
+}
+
+// Torrents returns a snapshot of all known torrents, ordered by hash
+func (s *Sync) Torrents() []TorrentInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	torrents := make([]TorrentInfo, 0, len(s.torrents))
+	for _, t := range s.torrents {
+		torrents = append(torrents, t)
+	}
+	sort.Slice(torrents, func(i, j int) bool {
+		return torrents[i].Hash < torrents[j].Hash
+	})
+	return torrents
+}
+
+// Categories returns a snapshot of all categories keyed by name
+func (s *Sync) Categories() map[string]Category {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := make(map[string]Category, len(s.categories))
+	for name, cat := range s.categories {
+		categories[name] = cat
+	}
+	return categories
+}
+
+// Tags returns all known tags in alphabetical order
+func (s *Sync) Tags() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := make([]string, 0, len(s.tags))
+	for tag := range s.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ServerState returns the latest global transfer state
+func (s *Sync) ServerState() ServerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.server
+}
+
+// Reset discards all local state so the next Update fetches a full snapshot
+func (s *Sync) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rid = 0
+	s.torrents = make(map[string]TorrentInfo)
+	s.categories = make(map[string]Category)
+	s.tags = make(map[string]bool)
+	s.server = ServerState{}
+}
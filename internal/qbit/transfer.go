@@ -0,0 +1,88 @@
+package qbit
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// TransferInfo returns the global transfer state. Only the transfer fields
+// of ServerState (speeds, totals, limits, DHT nodes, connection status) are
+// filled in; /sync/maindata reports the rest.
+func (c *Client) TransferInfo(ctx context.Context) (*ServerState, error) {
+	var state ServerState
+	if err := c.getJSON(ctx, "/api/v2/transfer/info", nil, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SpeedLimitsMode reports whether the alternative speed limits are active
+func (c *Client) SpeedLimitsMode(ctx context.Context) (bool, error) {
+	var mode int
+	if err := c.getJSON(ctx, "/api/v2/transfer/speedLimitsMode", nil, &mode); err != nil {
+		return false, err
+	}
+	return mode == 1, nil
+}
+
+// ToggleSpeedLimitsMode switches between the regular and alternative speed limits
+func (c *Client) ToggleSpeedLimitsMode(ctx context.Context) error {
+	return c.postForm(ctx, "/api/v2/transfer/toggleSpeedLimitsMode", url.Values{})
+}
+
+// SetDownloadLimit sets the global download limit in bytes/s (0 = unlimited)
+func (c *Client) SetDownloadLimit(ctx context.Context, limit int64) error {
+	return c.postForm(ctx, "/api/v2/transfer/setDownloadLimit", limitForm(limit))
+}
+
+// SetUploadLimit sets the global upload limit in bytes/s (0 = unlimited)
+func (c *Client) SetUploadLimit(ctx context.Context, limit int64) error {
+	return c.postForm(ctx, "/api/v2/transfer/setUploadLimit", limitForm(limit))
+}
+
+// SetTorrentDownloadLimit sets the download limit of torrents in bytes/s (0 = unlimited)
+func (c *Client) SetTorrentDownloadLimit(ctx context.Context, hashes []string, limit int64) error {
+	data := limitForm(limit)
+	data.Set("hashes", joinHashes(hashes))
+	return c.postForm(ctx, "/api/v2/torrents/setDownloadLimit", data)
+}
+
+// SetTorrentUploadLimit sets the upload limit of torrents in bytes/s (0 = unlimited)
+func (c *Client) SetTorrentUploadLimit(ctx context.Context, hashes []string, limit int64) error {
+	data := limitForm(limit)
+	data.Set("hashes", joinHashes(hashes))
+	return c.postForm(ctx, "/api/v2/torrents/setUploadLimit", data)
+}
+
+// limitForm builds the limit= form used by the speed limit endpoints
+func limitForm(limit int64) url.Values {
+	data := url.Values{}
+	data.Set("limit", strconv.FormatInt(max(limit, 0), 10))
+	return data
+}
+
+// Special values for SetShareLimits
+const (
+	ShareLimitGlobal = -2 // Use qBittorrent's global share limit
+	ShareLimitNone   = -1 // No limit
+)
+
+// LimitActionStop stops a torrent once it reaches its share limits
+// (TorrentInfo.LimitAction)
+const LimitActionStop = "Stop"
+
+// SetShareLimits sets the share ratio and seeding time (minutes) limits of
+// torrents, and what happens once one is reached. qBittorrent before 5.1
+// ignores the action and uses its global share limit action, which may
+// remove the torrent. The inactive seeding time limit is left at the global
+// default; qBittorrent 4.6+ requires it to be sent.
+func (c *Client) SetShareLimits(ctx context.Context, hashes []string, ratio float64, seedingMinutes int64, action string) error {
+	data := url.Values{}
+	data.Set("hashes", joinHashes(hashes))
+	data.Set("ratioLimit", strconv.FormatFloat(ratio, 'f', -1, 64))
+	data.Set("seedingTimeLimit", strconv.FormatInt(seedingMinutes, 10))
+	data.Set("inactiveSeedingTimeLimit", strconv.Itoa(ShareLimitGlobal))
+	data.Set("shareLimitAction", action)
+	return c.postForm(ctx, "/api/v2/torrents/setShareLimits", data)
+}
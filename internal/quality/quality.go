@@ -0,0 +1,249 @@
+// Package quality scores search results against user-defined quality
+// profiles, using what their release names say, and picks the best one.
+package quality
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/release"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+)
+
+// Points for matching a profile. A list's first entry is worth the full
+// points, later ones proportionally less.
+const (
+	resolutionPoints = 40
+	sourcePoints     = 30
+	codecPoints      = 15
+	preferPoints     = 10 // Each preferred tag or word
+	properPoints     = 5  // PROPER or REPACK
+)
+
+// defaultSeasonEpisodes is how many episodes a season pack is assumed to
+// have when the profile doesn't say
+const defaultSeasonEpisodes = 10
+
+// Score is how well a result fits a profile
+type Score struct {
+	Points   int
+	Reasons  []string // What earned the points, e.g. "1080p +40"
+	Rejected []string // Why the result is ruled out, e.g. "CAM"
+}
+
+// OK reports whether the result may be picked
+func (s Score) OK() bool {
+	return len(s.Rejected) == 0
+}
+
+// Reason explains the score, e.g. "1080p +40, WEB-DL +30, health +9" or
+// "rejected: CAM, 2 seeders"
+func (s Score) Reason() string {
+	if !s.OK() {
+		return "rejected: " + strings.Join(s.Rejected, ", ")
+	}
+	if len(s.Reasons) == 0 {
+		return "nothing preferred"
+	}
+	return strings.Join(s.Reasons, ", ")
+}
+
+// Default returns the index of the profile marked default, the first one
+// if none is, or -1 if there are none
+func Default(profiles []config.QualityProfile) int {
+	for i, p := range profiles {
+		if p.Default {
+			return i
+		}
+	}
+	if len(profiles) > 0 {
+		return 0
+	}
+	return -1
+}
+
+// Evaluate scores a result against a profile. The zero profile scores
+// results by health alone.
+func Evaluate(p config.QualityProfile, t scraper.Torrent, now time.Time) Score {
+	info := release.Parse(t.Name)
+	var s Score
+
+	for _, term := range p.Reject {
+		if has(info, t.Name, term) {
+			s.Rejected = append(s.Rejected, term)
+		}
+	}
+	if p.MinSeeders > 0 && t.Seeders < p.MinSeeders {
+		s.Rejected = append(s.Rejected, fmt.Sprintf("%d seeders", t.Seeders))
+	}
+	if size, episodes := sizePerEpisode(info, t.SizeBytes, p.SeasonEpisodes); p.MaxSizeGB > 0 && size > int64(p.MaxSizeGB*(1<<30)) {
+		reason := fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+		if episodes > 1 {
+			reason += "/episode"
+		}
+		s.Rejected = append(s.Rejected, reason+fmt.Sprintf(" over %g GB", p.MaxSizeGB))
+	}
+
+	sources := []string{info.Source}
+	if info.Remux {
+		sources = []string{"Remux", info.Source}
+	}
+	s.rank(p.Resolutions, []string{info.Resolution}, resolutionPoints)
+	s.rank(p.Sources, sources, sourcePoints)
+	s.rank(p.Codecs, []string{info.Codec}, codecPoints)
+	for _, term := range p.Prefer {
+		if has(info, t.Name, term) {
+			s.add(term, preferPoints)
+		}
+	}
+	if info.Proper || info.Repack {
+		label := "PROPER"
+		if !info.Proper {
+			label = "REPACK"
+		}
+		s.add(label, properPoints)
+	}
+	s.add("health", t.HealthAt(now)/10)
+	return s
+}
+
+// add awards points, noting why
+func (s *Score) add(reason string, points int) {
+	if points > 0 {
+		s.Points += points
+		s.Reasons = append(s.Reasons, fmt.Sprintf("%s +%d", reason, points))
+	}
+}
+
+// rank awards the points for the best of values found in a preference list
+func (s *Score) rank(list, values []string, points int) {
+	for i, want := range list {
+		want, _ = release.Canonical(want)
+		for _, v := range values {
+			if v != "" && strings.EqualFold(v, want) {
+				s.add(v, points-i*points/len(list))
+				return
+			}
+		}
+	}
+}
+
+// sizePerEpisode returns the size of each episode of a release, and how
+// many episodes it has. A season pack's episodes aren't listed in its name,
+// so each season is assumed to have seasonEpisodes (default 10). Anything
+// else, such as a movie marked COMPLETE, is held to the maximum as a whole.
+func sizePerEpisode(info release.Info, size int64, seasonEpisodes int) (int64, int) {
+	switch {
+	case info.Kind == release.KindEpisode && len(info.Episodes) > 1:
+		n := len(info.Episodes)
+		return size / int64(n), n
+	case info.Kind == release.KindSeason:
+		if seasonEpisodes <= 0 {
+			seasonEpisodes = defaultSeasonEpisodes
+		}
+		n := seasonEpisodes * max(len(info.Seasons), 1)
+		return size / int64(n), n
+	}
+	return size, 1
+}
+
+// has reports whether a release has a tag (in any spelling, e.g. "HEVC" for
+// x265) or, for a term that isn't a tag, whether its name has the word
+func has(info release.Info, name, term string) bool {
+	canon, isTag := release.Canonical(term)
+	if !isTag {
+		return hasWord(name, term)
+	}
+	fields := []string{info.Resolution, info.Source, info.Codec}
+	fields = append(fields, info.HDR...)
+	fields = append(fields, info.Audio...)
+	fields = append(fields, info.Tags...)
+	if info.Remux {
+		fields = append(fields, "Remux")
+	}
+	if info.Proper {
+		fields = append(fields, "PROPER", "REAL")
+	}
+	if info.Repack {
+		fields = append(fields, "REPACK", "RERIP")
+	}
+	return slices.ContainsFunc(fields, func(f string) bool {
+		return f != "" && strings.EqualFold(f, canon)
+	})
+}
+
+// hasWord reports whether word appears in name, case-insensitively, not as
+// part of a longer word
+func hasWord(name, word string) bool {
+	name, word = strings.ToLower(name), strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return false
+	}
+	for i := 0; ; {
+		j := strings.Index(name[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isAlnum(name[start-1])) && (end == len(name) || !isAlnum(name[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// better reports whether a ranks above b: allowed before rejected, then
+// more points, then healthier, then more seeders
+func better(a, b Score, ta, tb scraper.Torrent, now time.Time) bool {
+	if a.OK() != b.OK() {
+		return a.OK()
+	}
+	if a.Points != b.Points {
+		return a.Points > b.Points
+	}
+	if ha, hb := ta.HealthAt(now), tb.HealthAt(now); ha != hb {
+		return ha > hb
+	}
+	return ta.Seeders > tb.Seeders
+}
+
+// Rank sorts results best first
+func Rank(p config.QualityProfile, torrents []scraper.Torrent, now time.Time) {
+	type scored struct {
+		t scraper.Torrent
+		s Score
+	}
+	ranked := make([]scored, len(torrents))
+	for i, t := range torrents {
+		ranked[i] = scored{t, Evaluate(p, t, now)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return better(ranked[i].s, ranked[j].s, ranked[i].t, ranked[j].t, now)
+	})
+	for i, r := range ranked {
+		torrents[i] = r.t
+	}
+}
+
+// Best returns the top-scoring healthy result: one the profile doesn't
+// reject, with at least one seeder. ok is false if there is none.
+func Best(p config.QualityProfile, torrents []scraper.Torrent, now time.Time) (best scraper.Torrent, score Score, ok bool) {
+	for _, t := range torrents {
+		s := Evaluate(p, t, now)
+		if !s.OK() || t.Seeders == 0 {
+			continue
+		}
+		if !ok || better(s, score, t, best, now) {
+			best, score, ok = t, s, true
+		}
+	}
+	return best, score, ok
+}
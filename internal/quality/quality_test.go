@@ -0,0 +1,182 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+)
+
+const gb = 1 << 30
+
+var tvProfile = config.QualityProfile{
+	Name:        "TV",
+	Resolutions: []string{"1080p", "720p"},
+	Sources:     []string{"WEB-DL", "HDTV"},
+	Codecs:      []string{"HEVC", "x264"},
+	Prefer:      []string{"DDP5.1", "nordic"},
+	Reject:      []string{"CAM", "TS", "HDR"},
+	MaxSizeGB:   8,
+	MinSeeders:  5,
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		torrent scraper.Torrent
+		points  int
+		reason  string
+	}{
+		{
+			scraper.Torrent{Name: "Show.S01E02.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 2 * gb},
+			40 + 30 + 15,
+			"1080p +40, WEB-DL +30, x265 +15",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01E02.PROPER.720p.HDTV.DD+5.1.x264-GRP", Seeders: 5, SizeBytes: gb},
+			20 + 15 + 8 + 10 + 5,
+			"720p +20, HDTV +15, x264 +8, DDP5.1 +10, PROPER +5",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01E02.NORDIC.480p.DVDRip-GRP", Seeders: 5, SizeBytes: gb},
+			10,
+			"nordic +10",
+		},
+		{
+			scraper.Torrent{Name: "Movie.2019.TS.x264-GRP", Seeders: 50, SizeBytes: gb},
+			0,
+			"rejected: TS",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01E02.2160p.WEB-DL.HDR.x265-GRP", Seeders: 2, SizeBytes: 9 * gb},
+			0,
+			"rejected: HDR, 2 seeders, 9.0 GB over 8 GB",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01E01E02.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 10 * gb},
+			85,
+			"1080p +40, WEB-DL +30, x265 +15",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01E01-E03.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 27 * gb},
+			0,
+			"rejected: 9.0 GB/episode over 8 GB",
+		},
+		{
+			// Season packs are assumed to have 10 episodes a season
+			scraper.Torrent{Name: "Show.S01.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 40 * gb},
+			85,
+			"1080p +40, WEB-DL +30, x265 +15",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 90 * gb},
+			0,
+			"rejected: 9.0 GB/episode over 8 GB",
+		},
+		{
+			scraper.Torrent{Name: "Show.S01-S03.COMPLETE.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 270 * gb},
+			0,
+			"rejected: 9.0 GB/episode over 8 GB",
+		},
+		{
+			// Without seasons, COMPLETE isn't known to be a pack
+			scraper.Torrent{Name: "Show.COMPLETE.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 20 * gb},
+			0,
+			"rejected: 20.0 GB over 8 GB",
+		},
+		{
+			// A movie marked COMPLETE is one movie
+			scraper.Torrent{Name: "Inception.2010.COMPLETE.BLURAY-GRP", Seeders: 5, SizeBytes: 45 * gb},
+			0,
+			"rejected: 45.0 GB over 8 GB",
+		},
+		{
+			// "Camera" isn't the CAM tag
+			scraper.Torrent{Name: "Hidden.Camera.2019.1080p.WEB-DL.x264-GRP", Seeders: 5, SizeBytes: gb},
+			40 + 30 + 8,
+			"1080p +40, WEB-DL +30, x264 +8",
+		},
+	}
+	for _, tt := range tests {
+		// Health is left out of the expected points
+		tt.torrent.Leechers = 1000
+		s := Evaluate(tvProfile, tt.torrent, now)
+		if s.OK() {
+			s.Points -= tt.torrent.HealthAt(now) / 10
+			if n := len(s.Reasons); n > 0 && strings.HasPrefix(s.Reasons[n-1], "health") {
+				s.Reasons = s.Reasons[:len(s.Reasons)-1]
+			}
+		} else {
+			s.Points = 0
+		}
+		if s.Points != tt.points || s.Reason() != tt.reason {
+			t.Errorf("Evaluate(%q) = %d %q, want %d %q", tt.torrent.Name, s.Points, s.Reason(), tt.points, tt.reason)
+		}
+	}
+}
+
+func TestSeasonEpisodes(t *testing.T) {
+	p := tvProfile
+	p.SeasonEpisodes = 20
+	pack := scraper.Torrent{Name: "Show.S01.1080p.WEB-DL.x265-GRP", Seeders: 5, SizeBytes: 90 * gb}
+	if s := Evaluate(p, pack, time.Now()); !s.OK() {
+		t.Errorf("20-episode pack of 4.5 GB episodes rejected: %s", s.Reason())
+	}
+}
+
+func TestEvaluateZeroProfile(t *testing.T) {
+	now := time.Now()
+	s := Evaluate(config.QualityProfile{}, scraper.Torrent{Name: "Movie.2019.CAM", Seeders: 100}, now)
+	if !s.OK() || s.Points != 10 || s.Reason() != "health +10" {
+		t.Errorf("zero profile = %d %q, want 10 %q", s.Points, s.Reason(), "health +10")
+	}
+}
+
+func TestRankAndBest(t *testing.T) {
+	now := time.Now()
+	results := []scraper.Torrent{
+		{Name: "Show.S01E02.720p.HDTV.x264-A", Seeders: 50},
+		{Name: "Show.S01E02.HDCAM-B", Seeders: 500},
+		{Name: "Show.S01E02.1080p.WEB-DL.x265-C", Seeders: 3}, // Too few seeders
+		{Name: "Show.S01E02.1080p.WEB-DL.x264-D", Seeders: 20},
+	}
+
+	best, score, ok := Best(tvProfile, results, now)
+	if !ok || best.Name != results[3].Name {
+		t.Fatalf("Best = %q, %v, want %q", best.Name, ok, results[3].Name)
+	}
+	if !score.OK() || score.Points < 78 {
+		t.Errorf("best score = %d %q", score.Points, score.Reason())
+	}
+
+	Rank(tvProfile, results, now)
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name[len(r.Name)-1:])
+	}
+	if got := names[0] + names[1]; got != "DA" {
+		t.Errorf("Rank order = %v, want D, A first", names)
+	}
+
+	if _, _, ok := Best(tvProfile, []scraper.Torrent{{Name: "Show.S01E02.HDCAM-B", Seeders: 500}}, now); ok {
+		t.Error("Best picked a rejected result")
+	}
+	if _, _, ok := Best(config.QualityProfile{}, []scraper.Torrent{{Name: "dead"}}, now); ok {
+		t.Error("Best picked a result without seeders")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if got := Default(nil); got != -1 {
+		t.Errorf("Default(nil) = %d, want -1", got)
+	}
+	profiles := []config.QualityProfile{{Name: "a"}, {Name: "b", Default: true}}
+	if got := Default(profiles); got != 1 {
+		t.Errorf("Default = %d, want 1", got)
+	}
+	if got := Default(profiles[:1]); got != 0 {
+		t.Errorf("Default without a default = %d, want 0", got)
+	}
+}
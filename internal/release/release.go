@@ -0,0 +1,584 @@
+// Package release parses scene and P2P style release names, such as
+// "Show.Name.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP", into the title,
+// episode numbering and quality attributes they encode.
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is what a release name describes
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindMovie
+	KindEpisode // One or more episodes
+	KindSeason  // One or more whole seasons
+)
+
+// String returns a human-readable kind
+func (k Kind) String() string {
+	switch k {
+	case KindMovie:
+		return "Movie"
+	case KindEpisode:
+		return "Episode"
+	case KindSeason:
+		return "Season"
+	default:
+		return "Unknown"
+	}
+}
+
+// Info is what Parse found in a release name. Fields are left empty when
+// the name doesn't say.
+type Info struct {
+	Kind  Kind
+	Title string // Separators replaced by spaces, e.g. "Show Name"
+	Year  int
+
+	Seasons  []int     // More than one for multi-season packs (S01-S03)
+	Episodes []int     // More than one for multi-episode files (S01E01E02)
+	Absolute []int     // Absolute episode numbers, as anime uses
+	AirDate  time.Time // Date-based episodes (Show.2024.03.05)
+	Complete bool      // Marked COMPLETE
+
+	Resolution string   // 2160p, 1080p, 1080i, 720p, 576p or 480p
+	Source     string   // BluRay, BDRip, WEB-DL, WEBRip, HDTV, SDTV, DVD, DVDRip, HDRip, SCR, TC, TS or CAM
+	Remux      bool     // An untouched copy of the disc's streams
+	Codec      string   // x264, x265, AV1, VP9 or XviD
+	HDR        []string // HDR, HDR10, HDR10+, DV or HLG
+	Audio      []string // e.g. DDP5.1, TrueHD, Atmos, DTS-HD MA
+	Group      string   // Release group
+	Proper     bool     // PROPER or REAL: fixes another group's release
+	Repack     bool     // REPACK or RERIP: fixes the group's own release
+	Version    int      // Release version (anime 01v2), 0 if not given
+	Tags       []string // Other recognised tags, e.g. EXTENDED, MULTI
+
+	Confidence Confidence
+}
+
+// Confidence scores, from 0 to 1, how sure Parse is of each part of a
+// name it read. A part that wasn't found scores 0.
+type Confidence struct {
+	Title   float64
+	Year    float64
+	Episode float64 // Seasons, episodes or air date
+	Quality float64 // Resolution and source
+	Group   float64
+}
+
+// Season returns the (first) season number, 0 if none
+func (i Info) Season() int {
+	if len(i.Seasons) == 0 {
+		return 0
+	}
+	return i.Seasons[0]
+}
+
+// Episode returns the (first) episode number, 0 if none
+func (i Info) Episode() int {
+	if len(i.Episodes) == 0 {
+		return 0
+	}
+	return i.Episodes[0]
+}
+
+// EpisodeString formats the episode numbering compactly: "S01E02",
+// "S01E01-E03", "S01", "S01-S03", "#1071" or "2024-03-05". It returns ""
+// if the name has none.
+func (i Info) EpisodeString() string {
+	switch {
+	case !i.AirDate.IsZero():
+		return i.AirDate.Format("2006-01-02")
+	case len(i.Episodes) > 0:
+		s := fmt.Sprintf("S%02dE%02d", i.Season(), i.Episodes[0])
+		if n := len(i.Episodes); n > 1 {
+			s += fmt.Sprintf("-E%02d", i.Episodes[n-1])
+		}
+		return s
+	case len(i.Seasons) > 1:
+		return fmt.Sprintf("S%02d-S%02d", i.Seasons[0], i.Seasons[len(i.Seasons)-1])
+	case len(i.Seasons) == 1:
+		return fmt.Sprintf("S%02d", i.Seasons[0])
+	case len(i.Absolute) > 1:
+		return fmt.Sprintf("#%d-%d", i.Absolute[0], i.Absolute[len(i.Absolute)-1])
+	case len(i.Absolute) == 1:
+		return fmt.Sprintf("#%d", i.Absolute[0])
+	}
+	return ""
+}
+
+// Quality summarises the resolution, source and codec, e.g.
+// "1080p WEB-DL x265"
+func (i Info) Quality() string {
+	var parts []string
+	for _, s := range []string{i.Resolution, i.SourceLabel(), i.Codec} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// SourceLabel is the source as shown in lists: "Remux" for a remux
+func (i Info) SourceLabel() string {
+	if i.Remux {
+		return "Remux"
+	}
+	return i.Source
+}
+
+// titleOnly is the title confidence of a name where nothing else was
+// recognised, so all of it is taken as the title
+const titleOnly = 0.3
+
+// maxRange bounds multi-episode and multi-season ranges, so a misread
+// number can't produce thousands of episodes
+const maxRange = 100
+
+// videoExtensions are stripped from the end of a name before parsing.
+// Only known extensions are, since "x264-GROUP" isn't one (nor is ".TS",
+// the telesync tag).
+var videoExtensions = []string{
+	".mkv", ".mp4", ".m4v", ".avi", ".mov", ".wmv", ".m2ts", ".webm", ".mpg", ".mpeg", ".torrent",
+}
+
+var (
+	// Anime style group first: "[Group] Title - 01 [1080p]"
+	leadingGroupRe = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+	// Scene style group last: "...x264-GROUP", maybe followed by site tags
+	trailingGroupRe = regexp.MustCompile(`-([A-Za-z0-9]+)((?:\s*(?:\[[^\]]*\]|\([^)]*\)))*)$`)
+	// File checksum: "[ABCD1234]"
+	checksumRe = regexp.MustCompile(`\[[0-9A-Fa-f]{8}\]`)
+	spaceRe    = regexp.MustCompile(`\s+`)
+
+	// Multi-token spellings joined into one token before matching
+	joinRes = []struct {
+		re   *regexp.Regexp
+		with string
+	}{
+		{regexp.MustCompile(`(?i)\bWEB DL\b`), "WEB-DL"},
+		{regexp.MustCompile(`(?i)\bWEB RIP\b`), "WEBRip"},
+		{regexp.MustCompile(`(?i)\bBlu Ray\b`), "BluRay"},
+		{regexp.MustCompile(`(?i)\bDolby Vision\b`), "DV"},
+		{regexp.MustCompile(`(?i)\bH (26[45])\b`), "H$1"},
+		{regexp.MustCompile(`(?i)\bDTS HD\b`), "DTS-HD"},
+		{regexp.MustCompile(`(?i)\bDD\+ (\d\.\d)\b`), "DD+$1"},
+		{regexp.MustCompile(`(?i)\bDirector'?s Cut\b`), "DC"},
+	}
+
+	seasonEpisodeRe = regexp.MustCompile(`(?i)\bS(\d{1,3})[ -]?E(\d{1,4})((?:-?E\d{1,4})+|-\d{1,4})?(?:v(\d))?\b`)
+	crossRe         = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})(?:-(\d{2,3}))?\b`)
+	seasonPackRe    = regexp.MustCompile(`(?i)\bS(\d{1,2})(?:-S?(\d{1,2}))?\b`)
+	seasonWordRe    = regexp.MustCompile(`(?i)\bSeasons? ?(\d{1,2})(?: ?(?:-|to|&) ?(\d{1,2}))?\b`)
+	episodeWordRe   = regexp.MustCompile(`(?i)\b(?:Ep|Episode) ?(\d{1,4})\b`)
+	dateRe          = regexp.MustCompile(`\b((?:19|20)\d{2})[ -](\d{2})[ -](\d{2})\b`)
+	yearRe          = regexp.MustCompile(`\b(19\d{2}|20\d{2})\b`)
+	animeDashRe     = regexp.MustCompile(`(?i)(?:^| )- (\d{1,4})(?:-(\d{1,4}))?(?:v(\d))?(?: |$)`)
+	animeNumberRe   = regexp.MustCompile(`(?i)^(\d{2,4})(?:v(\d))?$`)
+	numberRe        = regexp.MustCompile(`\d+`)
+)
+
+// Parse reads a release name. It never fails: whatever it can't place is
+// taken as part of the title, and Confidence says how sure each part is.
+func Parse(name string) Info {
+	var info Info
+	s := strings.TrimSpace(name)
+	lower := strings.ToLower(s)
+	for _, ext := range videoExtensions {
+		if strings.HasSuffix(lower, ext) {
+			s = s[:len(s)-len(ext)]
+			break
+		}
+	}
+	s = checksumRe.ReplaceAllString(s, " ")
+
+	anime := false
+	if m := leadingGroupRe.FindStringSubmatch(s); m != nil && !isTag(m[1]) {
+		info.Group = strings.TrimSpace(m[1])
+		info.Confidence.Group = 0.85
+		s = s[len(m[0]):]
+		anime = true
+	} else if m := trailingGroupRe.FindStringSubmatchIndex(s); m != nil && validGroup(s[m[2]:m[3]]) {
+		rest := s[:m[0]] + s[m[4]:m[5]]
+		// Only a name with something else recognised ends in a group;
+		// "home-video" doesn't
+		if parsed := parseBody(rest, false); parsed.Confidence.Title != titleOnly {
+			parsed.Group = s[m[2]:m[3]]
+			parsed.Confidence.Group = 0.9
+			return parsed
+		}
+	}
+
+	parsed := parseBody(s, anime)
+	parsed.Group = info.Group
+	parsed.Confidence.Group = info.Confidence.Group
+	return parsed
+}
+
+// parseBody parses a name once its extension and group are removed
+func parseBody(s string, anime bool) Info {
+	var info Info
+	norm := normalize(s)
+	p := parser{info: &info, norm: norm, end: len(norm)}
+	p.numbering(anime)
+	p.tokens()
+	p.year()
+	if anime && len(info.Episodes) == 0 && len(info.Absolute) == 0 && len(info.Seasons) == 0 {
+		p.animeNumber()
+	}
+	p.title()
+	p.finish()
+	return info
+}
+
+// normalize turns separators into single spaces. A dot between two single
+// digits is kept, since it's part of an audio channel layout (DDP5.1).
+func normalize(s string) string {
+	var b strings.Builder
+	isDigit := func(i int) bool { return i >= 0 && i < len(s) && s[i] >= '0' && s[i] <= '9' }
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '.':
+			if isDigit(i-1) && isDigit(i+1) && !isDigit(i-2) && !isDigit(i+2) {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte(' ')
+			}
+		case '_', '[', ']', '(', ')', '{', '}', ',':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	out := strings.TrimSpace(spaceRe.ReplaceAllString(b.String(), " "))
+	for _, j := range joinRes {
+		out = j.re.ReplaceAllString(out, j.with)
+	}
+	return out
+}
+
+// parser tracks where the title ends while the parts of a name are found:
+// the title is everything before the first recognised part
+type parser struct {
+	info *Info
+	norm string
+	end  int // Start of the first recognised part
+}
+
+// mark records that a part starts at i
+func (p *parser) mark(i int) {
+	p.end = min(p.end, i)
+}
+
+// numbering finds seasons, episodes and air dates
+func (p *parser) numbering(anime bool) {
+	info := p.info
+	if m := seasonEpisodeRe.FindStringSubmatchIndex(p.norm); m != nil {
+		season, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+		first, _ := strconv.Atoi(p.norm[m[4]:m[5]])
+		info.Seasons = []int{season}
+		info.Episodes = []int{first}
+		if m[6] >= 0 {
+			extra := p.norm[m[6]:m[7]]
+			nums := numberRe.FindAllString(extra, -1)
+			if strings.Contains(extra, "-") && len(nums) == 1 {
+				last, _ := strconv.Atoi(nums[0])
+				info.Episodes = numberRange(first, last)
+			} else {
+				for _, n := range nums {
+					ep, _ := strconv.Atoi(n)
+					info.Episodes = append(info.Episodes, ep)
+				}
+			}
+		}
+		if m[8] >= 0 {
+			info.Version, _ = strconv.Atoi(p.norm[m[8]:m[9]])
+		}
+		info.Confidence.Episode = 0.95
+		p.mark(m[0])
+		return
+	}
+
+	if m := crossRe.FindStringSubmatchIndex(p.norm); m != nil {
+		season, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+		first, _ := strconv.Atoi(p.norm[m[4]:m[5]])
+		info.Seasons = []int{season}
+		info.Episodes = []int{first}
+		if m[6] >= 0 {
+			last, _ := strconv.Atoi(p.norm[m[6]:m[7]])
+			info.Episodes = numberRange(first, last)
+		}
+		info.Confidence.Episode = 0.85
+		p.mark(m[0])
+		return
+	}
+
+	if m := dateRe.FindStringSubmatchIndex(p.norm); m != nil {
+		y, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+		mo, _ := strconv.Atoi(p.norm[m[4]:m[5]])
+		d, _ := strconv.Atoi(p.norm[m[6]:m[7]])
+		date := time.Date(y, time.Month(mo), d, 0, 0, 0, 0, time.UTC)
+		if m[0] > 0 && date.Month() == time.Month(mo) && date.Day() == d {
+			info.AirDate = date
+			info.Confidence.Episode = 0.9
+			p.mark(m[0])
+			return
+		}
+	}
+
+	found := false
+	if m := seasonWordRe.FindStringSubmatchIndex(p.norm); m != nil {
+		info.Seasons = p.rangeAt(m)
+		info.Confidence.Episode = 0.9
+		p.mark(m[0])
+		found = true
+	} else if m := seasonPackRe.FindStringSubmatchIndex(p.norm); m != nil && m[0] > 0 {
+		info.Seasons = p.rangeAt(m)
+		info.Confidence.Episode = 0.85
+		p.mark(m[0])
+		found = true
+	}
+
+	if m := episodeWordRe.FindStringSubmatchIndex(p.norm); m != nil && m[0] > 0 {
+		ep, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+		if len(info.Seasons) == 1 {
+			info.Episodes = []int{ep}
+		} else {
+			info.Absolute = []int{ep}
+		}
+		info.Confidence.Episode = 0.7
+		p.mark(m[0])
+		return
+	}
+	if found {
+		return
+	}
+
+	// "Title - 01", anime style. Four digits only after an anime group, as
+	// "Movie - 2019" is more likely a year.
+	if m := animeDashRe.FindStringSubmatchIndex(p.norm); m != nil && m[0] > 0 && (anime || m[3]-m[2] < 4) {
+		first, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+		info.Absolute = []int{first}
+		if m[4] >= 0 {
+			last, _ := strconv.Atoi(p.norm[m[4]:m[5]])
+			info.Absolute = numberRange(first, last)
+		}
+		if m[6] >= 0 {
+			info.Version, _ = strconv.Atoi(p.norm[m[6]:m[7]])
+		}
+		info.Confidence.Episode = 0.8
+		p.mark(m[0])
+	}
+}
+
+// rangeAt reads a number, or a range of numbers, from submatches 1 and 2
+func (p *parser) rangeAt(m []int) []int {
+	first, _ := strconv.Atoi(p.norm[m[2]:m[3]])
+	if m[4] < 0 {
+		return []int{first}
+	}
+	last, _ := strconv.Atoi(p.norm[m[4]:m[5]])
+	return numberRange(first, last)
+}
+
+// numberRange returns first..last, or just first if the range is reversed
+// or implausibly long
+func numberRange(first, last int) []int {
+	if last <= first || last-first > maxRange {
+		return []int{first}
+	}
+	out := make([]int, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		out = append(out, n)
+	}
+	return out
+}
+
+// span is a token of the normalized name and where it starts
+type span struct {
+	text  string
+	start int
+}
+
+// split returns the space separated tokens of the normalized name
+func (p *parser) split() []span {
+	var spans []span
+	start := -1
+	for i := 0; i <= len(p.norm); i++ {
+		if i == len(p.norm) || p.norm[i] == ' ' {
+			if start >= 0 {
+				spans = append(spans, span{p.norm[start:i], start})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return spans
+}
+
+// tokens matches each token against the tag tables. Tags that are also
+// ordinary words (CAM, REAL, MULTI...) only count after the year or
+// another tag, or right before a tag, so "Cam Girl 2020" keeps its title.
+func (p *parser) tokens() {
+	spans := p.split()
+	strong := p.end
+	for _, m := range yearRe.FindAllStringIndex(p.norm, -1) {
+		if m[0] > 0 {
+			strong = min(strong, m[0])
+		}
+	}
+	for _, sp := range spans {
+		if t, ok := lookupTag(sp.text); ok && !t.weak {
+			strong = min(strong, sp.start)
+		}
+	}
+
+	prev := ""
+	for i, sp := range spans {
+		t, ok := lookupTag(sp.text)
+		if !ok {
+			prev = ""
+			continue
+		}
+		if t.weak && sp.start < strong {
+			next, nextOK := tag{}, false
+			if i+1 < len(spans) {
+				next, nextOK = lookupTag(spans[i+1].text)
+			}
+			if i == 0 || !nextOK || next.weak {
+				prev = ""
+				continue
+			}
+		}
+		if t.kind == tagAudio && t.value == "MA" && !strings.HasPrefix(prev, "DTS-HD") {
+			continue
+		}
+		t.apply(p.info)
+		p.mark(sp.start)
+		prev = t.value
+	}
+}
+
+// year picks the release year: the last year before the other parts, so
+// "Blade Runner 2049 2017" is 2017, and never the first word, so "2012
+// 2009" is 2009.
+func (p *parser) year() {
+	info := p.info
+	best := -1
+	for _, m := range yearRe.FindAllStringSubmatchIndex(p.norm, -1) {
+		if m[0] == 0 || m[0] > p.end {
+			continue
+		}
+		if !info.AirDate.IsZero() && m[0] == p.end {
+			continue // The year of the air date
+		}
+		best = m[0]
+		info.Year, _ = strconv.Atoi(p.norm[m[2]:m[3]])
+	}
+	if best < 0 {
+		return
+	}
+	// A year that is the last word may be part of the title
+	info.Confidence.Year = 0.9
+	if best+4 == len(p.norm) {
+		info.Confidence.Year = 0.6
+	}
+	p.mark(best)
+}
+
+// animeNumber reads a bare episode number after an anime group's title:
+// "[Group] Title 1071 [1080p]"
+func (p *parser) animeNumber() {
+	var last *span
+	for _, sp := range p.split() {
+		if sp.start >= p.end {
+			break
+		}
+		if sp.start > 0 && animeNumberRe.MatchString(sp.text) {
+			last = &sp
+		}
+	}
+	if last == nil || (p.info.Year > 0 && last.text == strconv.Itoa(p.info.Year)) {
+		return
+	}
+	m := animeNumberRe.FindStringSubmatch(last.text)
+	n, _ := strconv.Atoi(m[1])
+	p.info.Absolute = []int{n}
+	if m[2] != "" {
+		p.info.Version, _ = strconv.Atoi(m[2])
+	}
+	p.info.Confidence.Episode = 0.6
+	p.mark(last.start)
+}
+
+// title takes the text before the first recognised part
+func (p *parser) title() {
+	info := p.info
+	info.Title = strings.Trim(p.norm[:p.end], " -")
+	switch {
+	case info.Title == "":
+		info.Confidence.Title = 0
+	case p.end == len(p.norm):
+		info.Confidence.Title = titleOnly
+	case info.Year > 0 || info.Confidence.Episode > 0:
+		info.Confidence.Title = 0.9
+	default:
+		info.Confidence.Title = 0.7 // Ended by a quality tag
+	}
+}
+
+// finish settles the kind and the quality confidence
+func (p *parser) finish() {
+	info := p.info
+	switch {
+	case len(info.Episodes) > 0 || len(info.Absolute) > 0 || !info.AirDate.IsZero():
+		info.Kind = KindEpisode
+	case len(info.Seasons) > 0:
+		info.Kind = KindSeason
+	case info.Year > 0:
+		info.Kind = KindMovie
+	}
+	if info.Source == "" && info.Remux {
+		info.Source = "BluRay"
+	}
+	if info.Version > 1 {
+		info.Proper = true
+	}
+
+	switch {
+	case info.Resolution != "" && info.Source != "":
+		info.Confidence.Quality = 0.95
+	case info.Resolution != "" || info.Source != "":
+		info.Confidence.Quality = 0.8
+	case info.Codec != "":
+		info.Confidence.Quality = 0.4
+	}
+}
+
+// validGroup reports whether the text after a name's last hyphen is a
+// release group, rather than the end of a hyphenated tag or a number
+func validGroup(g string) bool {
+	if _, err := strconv.Atoi(g); err == nil {
+		return false
+	}
+	if slices.Contains([]string{"dl", "rip", "hd", "ray", "ma", "x", "es", "3", "ac3"}, strings.ToLower(g)) {
+		return false
+	}
+	return !isTag(g)
+}
+
+// isTag reports whether s is a recognised tag, e.g. "1080p"
+func isTag(s string) bool {
+	_, ok := lookupTag(strings.TrimSpace(s))
+	return ok
+}
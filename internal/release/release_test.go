@@ -0,0 +1,247 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describe summarises what Parse found, skipping empty fields
+func describe(i Info) string {
+	var parts []string
+	add := func(k string, v any) {
+		s := fmt.Sprint(v)
+		if s != "" && s != "0" && s != "false" {
+			parts = append(parts, k+"="+s)
+		}
+	}
+	add("kind", i.Kind)
+	add("title", i.Title)
+	add("year", i.Year)
+	add("ep", i.EpisodeString())
+	add("res", i.Resolution)
+	add("src", i.Source)
+	add("remux", i.Remux)
+	add("codec", i.Codec)
+	add("hdr", strings.Join(i.HDR, ","))
+	add("audio", strings.Join(i.Audio, ","))
+	add("group", i.Group)
+	add("proper", i.Proper)
+	add("repack", i.Repack)
+	add("v", i.Version)
+	add("complete", i.Complete)
+	add("tags", strings.Join(i.Tags, ","))
+	return strings.Join(parts, " ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Show.Name.S01E02.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+			"kind=Episode title=Show Name ep=S01E02 res=1080p src=WEB-DL codec=x264 audio=DDP5.1 group=GROUP"},
+		{"The.Matrix.1999.2160p.UHD.BluRay.REMUX.HDR.HEVC.TrueHD.7.1.Atmos-FGT",
+			"kind=Movie title=The Matrix year=1999 res=2160p src=BluRay remux=true codec=x265 hdr=HDR audio=TrueHD7.1,Atmos group=FGT"},
+		{"Blade Runner 2049 (2017) 1080p BluRay x264 DTS-HD MA 5.1-GRP [rarbg]",
+			"kind=Movie title=Blade Runner 2049 year=2017 res=1080p src=BluRay codec=x264 audio=DTS-HD MA 5.1 group=GRP"},
+		{"2001.A.Space.Odyssey.1968.720p.BluRay.x264-AMIABLE.mkv",
+			"kind=Movie title=2001 A Space Odyssey year=1968 res=720p src=BluRay codec=x264 group=AMIABLE"},
+		{"2012.2009.1080p.BluRay.x264-GRP",
+			"kind=Movie title=2012 year=2009 res=1080p src=BluRay codec=x264 group=GRP"},
+		{"[SubsPlease] One Piece - 1071 (1080p) [ABCD1234].mkv",
+			"kind=Episode title=One Piece ep=#1071 res=1080p group=SubsPlease"},
+		{"[Erai-raws] Show Name 2nd Season - 05v2 [720p][Multiple Subtitle].mkv",
+			"kind=Episode title=Show Name 2nd Season ep=#5 res=720p group=Erai-raws proper=true v=2"},
+		{"[SubsPlease] One Piece 1071 (1080p)",
+			"kind=Episode title=One Piece ep=#1071 res=1080p group=SubsPlease"},
+		{"[HorribleSubs] Anime Title - 01-12 [1080p]",
+			"kind=Episode title=Anime Title ep=#1-12 res=1080p group=HorribleSubs"},
+		{"[Group] Anime Title - 13 [BD 1080p FLAC]",
+			"kind=Episode title=Anime Title ep=#13 res=1080p src=BluRay audio=FLAC group=Group"},
+		{"Anime Title Episode 24 1080p",
+			"kind=Episode title=Anime Title ep=#24 res=1080p"},
+		{"The.Daily.Show.2024.03.05.Guest.Name.720p.WEB.h264-EDITH",
+			"kind=Episode title=The Daily Show ep=2024-03-05 res=720p src=WEB-DL codec=x264 group=EDITH"},
+		{"Late Night 2023-11-30 1080p HDTV",
+			"kind=Episode title=Late Night ep=2023-11-30 res=1080p src=HDTV"},
+		{"Show.Name.S01E01E02.720p.HDTV.x264-GRP",
+			"kind=Episode title=Show Name ep=S01E01-E02 res=720p src=HDTV codec=x264 group=GRP"},
+		{"Show.Name.S02E01-E03.1080p.AMZN.WEB-DL.DDP5.1.H.264-NTb",
+			"kind=Episode title=Show Name ep=S02E01-E03 res=1080p src=WEB-DL codec=x264 audio=DDP5.1 group=NTb"},
+		{"Show.Name.S02E01-03.1080p.WEB.h265-GRP",
+			"kind=Episode title=Show Name ep=S02E01-E03 res=1080p src=WEB-DL codec=x265 group=GRP"},
+		{"Show.Name.S1E5.480p.x264-mSD",
+			"kind=Episode title=Show Name ep=S01E05 res=480p codec=x264 group=mSD"},
+		{"Show Name - S1E2 - Title",
+			"kind=Episode title=Show Name ep=S01E02"},
+		{"Show.Name.2019.S03E10.720p",
+			"kind=Episode title=Show Name year=2019 ep=S03E10 res=720p"},
+		{"Show Name 2x05 HDTV",
+			"kind=Episode title=Show Name ep=S02E05 src=HDTV"},
+		{"Show.Name.1x01-02.DVDRip.XviD-GRP",
+			"kind=Episode title=Show Name ep=S01E01-E02 src=DVDRip codec=XviD group=GRP"},
+		{"Show.Name.S01-S03.COMPLETE.1080p.BluRay.x265-GRP",
+			"kind=Season title=Show Name ep=S01-S03 res=1080p src=BluRay codec=x265 group=GRP complete=true"},
+		{"Show.Name.S02.COMPLETE.1080p.WEB.x264-GRP",
+			"kind=Season title=Show Name ep=S02 res=1080p src=WEB-DL codec=x264 group=GRP complete=true"},
+		{"Show Name Season 4 Complete",
+			"kind=Season title=Show Name ep=S04 complete=true"},
+		{"Show Name Seasons 1-5 720p",
+			"kind=Season title=Show Name ep=S01-S05 res=720p"},
+		{"Show Name Season 2 Episode 7 480p",
+			"kind=Episode title=Show Name ep=S02E07 res=480p"},
+		{"Show.Name.S04E07.REPACK.1080p.WEB.H264-GRP",
+			"kind=Episode title=Show Name ep=S04E07 res=1080p src=WEB-DL codec=x264 group=GRP repack=true"},
+		{"Show.Name.S04E07.PROPER.720p.HDTV.x264-GRP",
+			"kind=Episode title=Show Name ep=S04E07 res=720p src=HDTV codec=x264 group=GRP proper=true"},
+		{"Show.Name.S04E07.iNTERNAL.1080p.WEB.H264-GRP",
+			"kind=Episode title=Show Name ep=S04E07 res=1080p src=WEB-DL codec=x264 group=GRP tags=INTERNAL"},
+		{"Cam Girl 2020 1080p WEB-DL",
+			"kind=Movie title=Cam Girl year=2020 res=1080p src=WEB-DL"},
+		{"Movie.2019.CAM.XviD-GRP",
+			"kind=Movie title=Movie year=2019 src=CAM codec=XviD group=GRP"},
+		{"Movie.Title.2019.HDTS.x264-GRP",
+			"kind=Movie title=Movie Title year=2019 src=TS codec=x264 group=GRP"},
+		{"Movie.Title.2019.TS.x264",
+			"kind=Movie title=Movie Title year=2019 src=TS codec=x264"},
+		{"Movie.Title.2019.DVDSCR.XviD-GRP",
+			"kind=Movie title=Movie Title year=2019 src=SCR codec=XviD group=GRP"},
+		{"Movie.Title.2019.HDTC.x264-GRP",
+			"kind=Movie title=Movie Title year=2019 src=TC codec=x264 group=GRP"},
+		{"Movie.Title.2019.PROPER.REPACK.1080p.WEB.H264-GRP",
+			"kind=Movie title=Movie Title year=2019 res=1080p src=WEB-DL codec=x264 group=GRP proper=true repack=true"},
+		{"Movie.Title.2019.REAL.1080p.WEB.H264-GRP",
+			"kind=Movie title=Movie Title year=2019 res=1080p src=WEB-DL codec=x264 group=GRP proper=true"},
+		{"Movie.2019.EXTENDED.Directors.Cut.1080p.BluRay.DTS.x264",
+			"kind=Movie title=Movie year=2019 res=1080p src=BluRay codec=x264 audio=DTS tags=EXTENDED,DC"},
+		{"Movie.Title.2010.UNRATED.720p.BRRip.x264.AAC-GRP",
+			"kind=Movie title=Movie Title year=2010 res=720p src=BDRip codec=x264 audio=AAC group=GRP tags=UNRATED"},
+		{"Movie.Title.2015.IMAX.2160p.WEB-DL.DDP5.1.Atmos.DV.HDR10.H.265-GRP",
+			"kind=Movie title=Movie Title year=2015 res=2160p src=WEB-DL codec=x265 hdr=DV,HDR10 audio=DDP5.1,Atmos group=GRP tags=IMAX"},
+		{"Spider-Man.No.Way.Home.2021.2160p.WEB-DL.DV.HDR10+.DDP5.1.Atmos-GRP",
+			"kind=Movie title=Spider-Man No Way Home year=2021 res=2160p src=WEB-DL hdr=DV,HDR10+ audio=DDP5.1,Atmos group=GRP"},
+		{"Movie.Title.2022.2160p.WEBRip.x265.10bit.HDR10Plus.AAC5.1-GRP",
+			"kind=Movie title=Movie Title year=2022 res=2160p src=WEBRip codec=x265 hdr=HDR10+ audio=AAC5.1 group=GRP tags=10bit"},
+		{"Movie.Title.2018.1080p.BluRay.REMUX.AVC.DTS-HD.MA.7.1-GRP",
+			"kind=Movie title=Movie Title year=2018 res=1080p src=BluRay remux=true codec=x264 audio=DTS-HD MA 7.1 group=GRP"},
+		{"Movie Title (1995) [1080p] [BluRay] [5.1] [YTS.MX]",
+			"kind=Movie title=Movie Title year=1995 res=1080p src=BluRay"},
+		{"Movie.Title.1995.1080p.BluRay.x264.YIFY",
+			"kind=Movie title=Movie Title year=1995 res=1080p src=BluRay codec=x264"},
+		{"Movie.Title.2003.MULTi.1080p.BluRay.x264-GRP",
+			"kind=Movie title=Movie Title year=2003 res=1080p src=BluRay codec=x264 group=GRP tags=MULTI"},
+		{"Movie.Title.2003.DVDRip.XviD.AC3-GRP",
+			"kind=Movie title=Movie Title year=2003 src=DVDRip codec=XviD audio=DD group=GRP"},
+		{"Movie.Title.2003.PAL.DVDR-GRP",
+			"kind=Movie title=Movie Title year=2003 src=DVD group=GRP"},
+		{"Movie.Title.2021.HLG.2160p.WEB.AV1.Opus-GRP",
+			"kind=Movie title=Movie Title year=2021 res=2160p src=WEB-DL codec=AV1 hdr=HLG audio=Opus group=GRP"},
+		{"Movie.Title.1080i.HDTV.MPEG2.DD5.1-GRP",
+			"kind=Unknown title=Movie Title res=1080i src=HDTV audio=DD5.1 group=GRP"},
+		{"Movie Title 2020 576p WEB-DL AAC2.0 H264",
+			"kind=Movie title=Movie Title year=2020 res=576p src=WEB-DL codec=x264 audio=AAC2.0"},
+		{"Some.Movie.2020.1080p.x264",
+			"kind=Movie title=Some Movie year=2020 res=1080p codec=x264"},
+		{"Movie 1920x1080",
+			"kind=Unknown title=Movie res=1080p"},
+		{"Charlottes Web 2006 DVDRip XviD",
+			"kind=Movie title=Charlottes Web year=2006 src=DVDRip codec=XviD"},
+		{"ubuntu-24.04-desktop-amd64.iso",
+			"kind=Unknown title=ubuntu-24 04-desktop-amd64 iso"},
+		{"home-video.mkv",
+			"kind=Unknown title=home-video"},
+		{"Real Steel 2011 720p BluRay",
+			"kind=Movie title=Real Steel year=2011 res=720p src=BluRay"},
+		{"The.Office.US.S05E14.720p.WEB-DL.DD5.1.H.264-GRP",
+			"kind=Episode title=The Office US ep=S05E14 res=720p src=WEB-DL codec=x264 audio=DD5.1 group=GRP"},
+		{"Doctor.Who.2005.S13E01.1080p.HDTV.x265-GRP",
+			"kind=Episode title=Doctor Who year=2005 ep=S13E01 res=1080p src=HDTV codec=x265 group=GRP"},
+		{"Movie.Title.2019.1080p.WEB-DL.DD+5.1.H.264-GRP",
+			"kind=Movie title=Movie Title year=2019 res=1080p src=WEB-DL codec=x264 audio=DDP5.1 group=GRP"},
+		{"Movie.Title.2019.1080p.BluRay.x264-GRP.mkv",
+			"kind=Movie title=Movie Title year=2019 res=1080p src=BluRay codec=x264 group=GRP"},
+		{"Movie.Title.2019.1080p.WEB.x264-GRP[TGx]",
+			"kind=Movie title=Movie Title year=2019 res=1080p src=WEB-DL codec=x264 group=GRP"},
+	}
+	for _, tt := range tests {
+		if got := describe(Parse(tt.name)); got != tt.want {
+			t.Errorf("Parse(%q)\n got: %s\nwant: %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumbering(t *testing.T) {
+	i := Parse("Show.Name.S02E01-E03.1080p.WEB-DL-GRP")
+	if i.Season() != 2 || i.Episode() != 1 || len(i.Episodes) != 3 || i.Episodes[2] != 3 {
+		t.Errorf("multi-episode: seasons %v episodes %v", i.Seasons, i.Episodes)
+	}
+
+	i = Parse("Show.S01-S03.1080p")
+	if len(i.Seasons) != 3 || i.Seasons[2] != 3 || len(i.Episodes) != 0 {
+		t.Errorf("multi-season: seasons %v episodes %v", i.Seasons, i.Episodes)
+	}
+
+	i = Parse("The.Daily.Show.2024.03.05.720p.WEB")
+	if !i.AirDate.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) || i.Year != 0 {
+		t.Errorf("air date %v, year %d", i.AirDate, i.Year)
+	}
+
+	// Not a date: no 13th month
+	if i := Parse("Show 2024 13 05 720p"); !i.AirDate.IsZero() {
+		t.Errorf("2024 13 05 read as air date %v", i.AirDate)
+	}
+
+	// A reversed or absurd range keeps the first number only
+	if i := Parse("Show.S01E05-E02.720p"); len(i.Episodes) != 1 {
+		t.Errorf("reversed range: %v", i.Episodes)
+	}
+	if i := Parse("Show.S01E01-E900.720p"); len(i.Episodes) != 1 {
+		t.Errorf("long range: %d episodes", len(i.Episodes))
+	}
+}
+
+func TestParseConfidence(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(Confidence) bool
+	}{
+		{"Show.Name.S01E02.1080p.WEB-DL-GRP", func(c Confidence) bool {
+			return c.Title >= 0.9 && c.Episode >= 0.9 && c.Quality >= 0.9 && c.Group >= 0.9
+		}},
+		{"Show Name 2x05", func(c Confidence) bool { return c.Episode > 0.8 && c.Episode < 0.95 }},
+		{"[Group] Title 1071 [1080p]", func(c Confidence) bool { return c.Episode > 0 && c.Episode < 0.8 }},
+		{"Movie.Title.2019.1080p", func(c Confidence) bool { return c.Year >= 0.9 && c.Quality < 0.9 }},
+		{"Blade Runner 2049", func(c Confidence) bool { return c.Year < 0.9 }},
+		{"Movie.Title.1080p.x264", func(c Confidence) bool { return c.Title < 0.9 && c.Year == 0 }},
+		{"just some words", func(c Confidence) bool { return c.Title < 0.5 && c.Quality == 0 && c.Group == 0 }},
+	}
+	for _, tt := range tests {
+		if c := Parse(tt.name).Confidence; !tt.check(c) {
+			t.Errorf("Parse(%q).Confidence = %+v", tt.name, c)
+		}
+	}
+}
+
+func TestQuality(t *testing.T) {
+	i := Parse("Movie.2018.1080p.BluRay.REMUX.AVC.DTS-HD.MA.7.1-GRP")
+	if got := i.Quality(); got != "1080p Remux x264" {
+		t.Errorf("Quality() = %q", got)
+	}
+	if got := Parse("Movie.2018").Quality(); got != "" {
+		t.Errorf("no quality: %q", got)
+	}
+}
+
+func TestResolution(t *testing.T) {
+	tests := map[string]string{
+		"4K": "2160p", "2160": "2160p", "uhd": "2160p", "1080P": "1080p",
+		"720": "720p", "1080i": "1080i", "hdtv": "", "": "",
+	}
+	for in, want := range tests {
+		if got := Resolution(in); got != want {
+			t.Errorf("Resolution(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
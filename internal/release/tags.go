@@ -0,0 +1,212 @@
+package release
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// tagKind is which part of Info a tag sets
+type tagKind int
+
+const (
+	tagResolution tagKind = iota
+	tagSource
+	tagRemux
+	tagCodec
+	tagHDR
+	tagAudio
+	tagChannels
+	tagProper
+	tagRepack
+	tagComplete
+	tagOther
+)
+
+// tag is a recognised token of a release name
+type tag struct {
+	kind  tagKind
+	value string // Canonical spelling
+	weak  bool   // Also an ordinary word, e.g. CAM
+}
+
+// tags maps lower-cased tokens to the tag they are. Audio codecs and
+// channel layouts are matched by audioRe and channelsRe instead.
+var tags = map[string]tag{
+	"2160p": {tagResolution, "2160p", false}, "4k": {tagResolution, "2160p", false},
+	"uhd": {tagResolution, "2160p", false}, "3840x2160": {tagResolution, "2160p", false},
+	"1080p": {tagResolution, "1080p", false}, "1920x1080": {tagResolution, "1080p", false},
+	"1080i": {tagResolution, "1080i", false},
+	"720p":  {tagResolution, "720p", false}, "1280x720": {tagResolution, "720p", false},
+	"576p": {tagResolution, "576p", false}, "576i": {tagResolution, "576p", false},
+	"480p": {tagResolution, "480p", false}, "480i": {tagResolution, "480p", false},
+
+	"bluray": {tagSource, "BluRay", false}, "blu-ray": {tagSource, "BluRay", false},
+	"bd25": {tagSource, "BluRay", false}, "bd50": {tagSource, "BluRay", false},
+	"bd":    {tagSource, "BluRay", true},
+	"bdrip": {tagSource, "BDRip", false}, "brrip": {tagSource, "BDRip", false},
+	"web-dl": {tagSource, "WEB-DL", false}, "webdl": {tagSource, "WEB-DL", false},
+	"web":    {tagSource, "WEB-DL", true},
+	"webrip": {tagSource, "WEBRip", false}, "web-rip": {tagSource, "WEBRip", false},
+	"hdtv": {tagSource, "HDTV", false}, "hdtvrip": {tagSource, "HDTV", false},
+	"pdtv": {tagSource, "SDTV", false}, "sdtv": {tagSource, "SDTV", false},
+	"dsr": {tagSource, "SDTV", false}, "tvrip": {tagSource, "SDTV", false},
+	"dvdrip": {tagSource, "DVDRip", false},
+	"dvd":    {tagSource, "DVD", true}, "dvdr": {tagSource, "DVD", false},
+	"dvd5": {tagSource, "DVD", false}, "dvd9": {tagSource, "DVD", false},
+	"hdrip": {tagSource, "HDRip", false},
+	"scr":   {tagSource, "SCR", true}, "screener": {tagSource, "SCR", true},
+	"dvdscr": {tagSource, "SCR", false}, "bdscr": {tagSource, "SCR", false},
+	"tc": {tagSource, "TC", true}, "hdtc": {tagSource, "TC", false},
+	"telecine": {tagSource, "TC", false},
+	"ts":       {tagSource, "TS", true}, "hdts": {tagSource, "TS", false},
+	"telesync": {tagSource, "TS", false}, "pdvd": {tagSource, "TS", false},
+	"cam": {tagSource, "CAM", true}, "camrip": {tagSource, "CAM", false},
+	"hdcam": {tagSource, "CAM", false},
+	"remux": {tagRemux, "Remux", false},
+
+	"x264": {tagCodec, "x264", false}, "h264": {tagCodec, "x264", false},
+	"avc":  {tagCodec, "x264", true},
+	"x265": {tagCodec, "x265", false}, "h265": {tagCodec, "x265", false},
+	"hevc": {tagCodec, "x265", false},
+	"av1":  {tagCodec, "AV1", false},
+	"vp9":  {tagCodec, "VP9", false},
+	"xvid": {tagCodec, "XviD", false}, "divx": {tagCodec, "XviD", false},
+
+	"hdr":    {tagHDR, "HDR", false},
+	"hdr10":  {tagHDR, "HDR10", false},
+	"hdr10+": {tagHDR, "HDR10+", false}, "hdr10plus": {tagHDR, "HDR10+", false},
+	"dv": {tagHDR, "DV", true}, "dovi": {tagHDR, "DV", false},
+	"hlg":      {tagHDR, "HLG", false},
+	"atmos":    {tagAudio, "Atmos", false},
+	"ma":       {tagAudio, "MA", true}, // Only after DTS-HD
+	"proper":   {tagProper, "PROPER", false},
+	"real":     {tagProper, "REAL", true},
+	"repack":   {tagRepack, "REPACK", false},
+	"rerip":    {tagRepack, "RERIP", false},
+	"complete": {tagComplete, "COMPLETE", true},
+
+	"extended": {tagOther, "EXTENDED", true}, "unrated": {tagOther, "UNRATED", true},
+	"remastered": {tagOther, "REMASTERED", true}, "uncut": {tagOther, "UNCUT", true},
+	"imax": {tagOther, "IMAX", true}, "hybrid": {tagOther, "HYBRID", true},
+	"dc": {tagOther, "DC", true}, "criterion": {tagOther, "CRITERION", true},
+	"10bit": {tagOther, "10bit", false}, "hi10p": {tagOther, "10bit", false},
+	"internal": {tagOther, "INTERNAL", true}, "limited": {tagOther, "LIMITED", true},
+	"multi": {tagOther, "MULTI", true}, "dual": {tagOther, "DUAL", true},
+	"dubbed": {tagOther, "DUBBED", true}, "subbed": {tagOther, "SUBBED", true},
+}
+
+var (
+	audioRe    = regexp.MustCompile(`^(ddp|dd\+|eac3|e-ac-3|ac3|dd|aac|dts-hd|dts-x|dts-es|dts|truehd|flac|opus|mp3|lpcm|pcm)(\d\.\d)?$`)
+	channelsRe = regexp.MustCompile(`^[1-9]\.[01]$`)
+)
+
+// audioCodecs maps audioRe's codec spellings to their canonical name
+var audioCodecs = map[string]string{
+	"ddp": "DDP", "dd+": "DDP", "eac3": "DDP", "e-ac-3": "DDP",
+	"dd": "DD", "ac3": "DD",
+	"aac":    "AAC",
+	"dts-hd": "DTS-HD", "dts-x": "DTS-X", "dts-es": "DTS-ES", "dts": "DTS",
+	"truehd": "TrueHD",
+	"flac":   "FLAC",
+	"opus":   "Opus",
+	"mp3":    "MP3",
+	"lpcm":   "LPCM", "pcm": "LPCM",
+}
+
+// lookupTag returns the tag a token is, if any
+func lookupTag(token string) (tag, bool) {
+	lower := strings.ToLower(token)
+	if t, ok := tags[lower]; ok {
+		return t, true
+	}
+	if m := audioRe.FindStringSubmatch(lower); m != nil {
+		return tag{kind: tagAudio, value: audioCodecs[m[1]] + m[2], weak: m[1] == "opus"}, true
+	}
+	if channelsRe.MatchString(lower) {
+		return tag{kind: tagChannels, value: lower}, true
+	}
+	return tag{}, false
+}
+
+// apply records the tag in info. The first resolution, source and codec
+// win; HDR formats, audio and other tags add up.
+func (t tag) apply(info *Info) {
+	switch t.kind {
+	case tagResolution:
+		if info.Resolution == "" {
+			info.Resolution = t.value
+		}
+	case tagSource:
+		if info.Source == "" {
+			info.Source = t.value
+		}
+	case tagRemux:
+		info.Remux = true
+	case tagCodec:
+		if info.Codec == "" {
+			info.Codec = t.value
+		}
+	case tagHDR:
+		info.HDR = appendNew(info.HDR, t.value)
+	case tagAudio:
+		if t.value == "MA" && len(info.Audio) > 0 {
+			info.Audio[len(info.Audio)-1] += " MA"
+			return
+		}
+		info.Audio = appendNew(info.Audio, t.value)
+	case tagChannels:
+		// A channel layout belongs to the codec before it: "DDP 5.1"
+		if n := len(info.Audio); n > 0 && !channelsSuffixRe.MatchString(info.Audio[n-1]) {
+			sep := ""
+			if strings.Contains(info.Audio[n-1], " ") {
+				sep = " "
+			}
+			info.Audio[n-1] += sep + t.value
+		}
+	case tagProper:
+		info.Proper = true
+	case tagRepack:
+		info.Repack = true
+	case tagComplete:
+		info.Complete = true
+	case tagOther:
+		info.Tags = appendNew(info.Tags, t.value)
+	}
+}
+
+var channelsSuffixRe = regexp.MustCompile(`\d\.\d$`)
+
+// appendNew appends s unless list has it already
+func appendNew(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// Resolution returns the canonical spelling of a resolution ("4K" and
+// "2160" are "2160p"), or "" if s isn't one
+func Resolution(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if t, ok := tags[s]; ok && t.kind == tagResolution {
+		return t.value
+	}
+	if t, ok := tags[s+"p"]; ok && t.kind == tagResolution {
+		return t.value
+	}
+	return ""
+}
+
+// Canonical returns the canonical spelling of a release tag ("h265" and
+// "HEVC" are "x265"), and whether s is one
+func Canonical(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if res := Resolution(s); res != "" {
+		return res, true
+	}
+	if t, ok := lookupTag(s); ok && t.kind != tagChannels {
+		return t.value, true
+	}
+	return s, false
+}
@@ -54,6 +54,9 @@ func (s *ExampleScraper) Search(ctx context.Context, query string) ([]Torrent, e
 	//         Leechers: 10,
 	//         Magnet:   "magnet:?xt=urn:btih:...",
 	//         Source:   s.name,
+	//         // Optional metadata (SizeBytes is derived from Size)
+	//         PublishedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	//         Category:    "Software",
 	//     },
 	// }, nil
 
@@ -14,11 +14,13 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-// GenericScraper attempts to scrape any torrent site using heuristics
+// GenericScraper attempts to scrape any torrent site using heuristics,
+// or follows the site's Profile when it has one
 type GenericScraper struct {
 	name      string
 	baseURL   string
 	searchURL string // Discovered or configured search URL pattern
+	profile   *Profile
 	client    *http.Client
 }
 
@@ -49,6 +51,12 @@ func (s *GenericScraper) Name() string {
 	return s.name
 }
 
+// SetProfile makes the scraper follow a profile instead of its heuristics
+// (nil goes back to the heuristics)
+func (s *GenericScraper) SetProfile(p *Profile) {
+	s.profile = p
+}
+
 // setBrowserHeaders sets headers to mimic a real browser
 func setBrowserHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0")
@@ -67,6 +75,10 @@ func setBrowserHeaders(req *http.Request) {
 
 // Search queries the site for torrents
 func (s *GenericScraper) Search(ctx context.Context, query string) ([]Torrent, error) {
+	if s.profile != nil {
+		return s.searchProfile(ctx, query)
+	}
+
 	// Try common search URL patterns
 	// Order matters: more specific patterns first, generic patterns last
 	searchPatterns := []string{
@@ -93,6 +105,9 @@ func (s *GenericScraper) Search(ctx context.Context, query string) ([]Torrent, e
 			lastErr = err
 			continue
 		}
+		for i := range results {
+			results[i].fillMetadata()
+		}
 		if len(results) > 0 {
 			// Remember this pattern worked
 			s.searchURL = strings.Replace(searchURL, url.PathEscape(query), "%s", 1)
@@ -106,8 +121,43 @@ func (s *GenericScraper) Search(ctx context.Context, query string) ([]Torrent, e
 	return nil, fmt.Errorf("no results found with any search pattern")
 }
 
+// searchProfile fetches the result pages the profile describes. A failing
+// page after the first ends the search with the results so far.
+func (s *GenericScraper) searchProfile(ctx context.Context, query string) ([]Torrent, error) {
+	var results []Torrent
+	pageURL := s.profile.URL(query, 0)
+	for page := 0; page < s.profile.pages() && pageURL != ""; page++ {
+		doc, err := s.fetchPage(ctx, pageURL)
+		if err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			break
+		}
+		rows := s.profile.Parse(doc, pageURL, s.name)
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			rows[i].fillMetadata()
+		}
+		results = append(results, rows...)
+		pageURL = s.profile.nextURL(doc, pageURL, query, page)
+	}
+	return results, nil
+}
+
 func (s *GenericScraper) trySearch(ctx context.Context, searchURL string) ([]Torrent, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	doc, err := s.fetchPage(ctx, searchURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.extractTorrents(doc), nil
+}
+
+// fetchPage downloads and parses an HTML page
+func (s *GenericScraper) fetchPage(ctx context.Context, pageURL string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -123,12 +173,7 @@ func (s *GenericScraper) trySearch(ctx context.Context, searchURL string) ([]Tor
 		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.extractTorrents(doc), nil
+	return goquery.NewDocumentFromReader(resp.Body)
 }
 
 // extractTorrents uses heuristics to find torrent info in any page
@@ -292,6 +337,7 @@ func (s *GenericScraper) extractInfoFromContext(link *goquery.Selection, t *Torr
 // extractFromTables looks for torrent data in HTML tables
 func (s *GenericScraper) extractFromTables(doc *goquery.Document) []Torrent {
 	var results []Torrent
+	now := time.Now()
 
 	doc.Find("table").Each(func(i int, table *goquery.Selection) {
 		table.Find("tr").Each(func(j int, row *goquery.Selection) {
@@ -342,15 +388,22 @@ func (s *GenericScraper) extractFromTables(doc *goquery.Document) []Torrent {
 				cellText := strings.TrimSpace(cell.Text())
 				cellText = strings.ReplaceAll(cellText, ",", "") // Remove commas from numbers
 
-				if strings.Contains(class, "seed") {
+				switch kind := cellKind(class); {
+				case kind == "seed":
 					if num := parseNumber(cellText); num > 0 {
 						t.Seeders = num
 					}
-				} else if strings.Contains(class, "leech") {
+				case kind == "leech":
 					if num := parseNumber(cellText); num > 0 {
 						t.Leechers = num
 					}
-				} else if t.Size == "" {
+				case kind == "date":
+					t.PublishedAt = ParseDate(cell.Text(), now)
+				case kind == "category":
+					t.Category = strings.Join(strings.Fields(cell.Text()), " ")
+				case kind == "uploader":
+					t.Uploader = strings.TrimSpace(cell.Text())
+				case t.Size == "":
 					if size := extractSize(cellText); size != "" {
 						t.Size = size
 					}
@@ -376,6 +429,26 @@ func (s *GenericScraper) extractFromTables(doc *goquery.Document) []Torrent {
 	return results
 }
 
+// cellKind guesses what a table cell holds from its class: "seed",
+// "leech", "date", "category", "uploader" or "" if unknown
+func cellKind(class string) string {
+	for _, c := range strings.Fields(strings.ToLower(class)) {
+		switch {
+		case strings.Contains(c, "seed"):
+			return "seed"
+		case strings.Contains(c, "leech"):
+			return "leech"
+		case strings.Contains(c, "date"), strings.Contains(c, "added"), strings.Contains(c, "uploaded"), c == "age", c == "time":
+			return "date"
+		case strings.Contains(c, "categ"), c == "cat":
+			return "category"
+		case strings.Contains(c, "uploader"), c == "user":
+			return "uploader"
+		}
+	}
+	return ""
+}
+
 // parseNumber extracts a number from text
 func parseNumber(text string) int {
 	text = strings.TrimSpace(text)
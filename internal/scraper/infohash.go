@@ -0,0 +1,111 @@
+package scraper
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// btihRegex matches the btih (BitTorrent InfoHash) in a magnet link, in
+// either hex or base32 form
+var btihRegex = regexp.MustCompile(`(?i)btih:([a-f0-9]{40}|[a-z2-7]{32})`)
+
+// NormalizeInfohash converts a v1 infohash to lowercase hex. Base32 hashes
+// (32 characters, as used by some magnet links) are decoded. Anything else
+// gives "".
+func NormalizeInfohash(hash string) string {
+	hash = strings.TrimSpace(hash)
+	switch len(hash) {
+	case 40:
+		if _, err := hex.DecodeString(hash); err == nil {
+			return strings.ToLower(hash)
+		}
+	case 32:
+		raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		if err == nil && len(raw) == 20 {
+			return hex.EncodeToString(raw)
+		}
+	}
+	return ""
+}
+
+// InfohashFromMagnet returns the normalized infohash of a magnet link, or ""
+func InfohashFromMagnet(magnet string) string {
+	m := btihRegex.FindStringSubmatch(magnet)
+	if m == nil {
+		return ""
+	}
+	return NormalizeInfohash(m[1])
+}
+
+// listings returns every source's listing of the torrent
+func (t Torrent) listings() []Torrent {
+	if len(t.Listings) == 0 {
+		return []Torrent{t}
+	}
+	return t.Listings
+}
+
+// Sources returns the names of all sources listing the torrent, the
+// primary source first
+func (t Torrent) Sources() []string {
+	var sources []string
+	for _, l := range t.listings() {
+		if !slices.Contains(sources, l.Source) {
+			sources = append(sources, l.Source)
+		}
+	}
+	return sources
+}
+
+// InfoURLs returns the detail page URLs of every listing of the torrent
+func (t Torrent) InfoURLs() []string {
+	var urls []string
+	for _, l := range t.listings() {
+		if l.InfoURL != "" && !slices.Contains(urls, l.InfoURL) {
+			urls = append(urls, l.InfoURL)
+		}
+	}
+	return urls
+}
+
+// Dedupe merges results with the same infohash. The first listing stays
+// the primary one, reporting the highest seeder and leecher counts seen
+// and the earliest upload date, and every source's own listing is kept in
+// Listings. Results without an infohash are never merged. Order is
+// otherwise preserved, and merging already deduplicated results again is
+// safe.
+func Dedupe(torrents []Torrent) []Torrent {
+	out := make([]Torrent, 0, len(torrents))
+	index := make(map[string]int)
+	for _, t := range torrents {
+		if t.InfoHash == "" {
+			t.InfoHash = InfohashFromMagnet(t.Magnet)
+		}
+		i, seen := index[t.InfoHash]
+		if t.InfoHash == "" || !seen {
+			if t.InfoHash != "" {
+				index[t.InfoHash] = len(out)
+			}
+			out = append(out, t)
+			continue
+		}
+
+		primary := &out[i]
+		primary.Listings = append(slices.Clone(primary.listings()), t.listings()...)
+		primary.Seeders = max(primary.Seeders, t.Seeders)
+		primary.Leechers = max(primary.Leechers, t.Leechers)
+		if primary.Size == "" {
+			primary.Size, primary.SizeBytes = t.Size, t.SizeBytes
+		}
+		if primary.PublishedAt.IsZero() || (!t.PublishedAt.IsZero() && t.PublishedAt.Before(primary.PublishedAt)) {
+			primary.PublishedAt = t.PublishedAt // First upload
+		}
+		if primary.Category == "" {
+			primary.Category = t.Category
+		}
+	}
+	return out
+}
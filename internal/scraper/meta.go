@@ -0,0 +1,126 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sizeUnits maps size units, upper-cased, to their multiplier. Sites mean
+// binary units whether or not they write the "i".
+var sizeUnits = map[string]int64{
+	"B":  1,
+	"KB": 1 << 10, "KIB": 1 << 10,
+	"MB": 1 << 20, "MIB": 1 << 20,
+	"GB": 1 << 30, "GIB": 1 << 30,
+	"TB": 1 << 40, "TIB": 1 << 40,
+}
+
+var sizeValueRegex = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*([KMGT]I?B|B)\b`)
+
+// ParseSize converts a displayed size such as "1.4 GB", "700MiB" or
+// "1,024.5 MB" to bytes. It returns 0 if no size is found.
+func ParseSize(s string) int64 {
+	m := sizeValueRegex.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	num := m[1]
+	// "1,024.5" and "1,024" use thousands separators, "1,5" a decimal comma
+	if i := strings.LastIndex(num, ","); i >= 0 && !strings.Contains(num, ".") && len(num)-i-1 != 3 {
+		num = num[:i] + "." + num[i+1:]
+	}
+	num = strings.ReplaceAll(num, ",", "")
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(value * float64(sizeUnits[strings.ToUpper(m[2])]))
+}
+
+// dateLayouts are the absolute date formats ParseDate understands
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02-01-2006",
+	"Jan 2, 2006",
+	"Jan. 2, 2006",
+	"2 Jan 2006",
+	"Jan 2 2006",
+}
+
+var relativeDateRegex = regexp.MustCompile(`(?i)^(\d+|an?)\s*(s|secs?|seconds?|m|mins?|minutes?|h|hrs?|hours?|d|days?|w|wks?|weeks?|mo|mos|months?|y|yrs?|years?)\s*(ago)?$`)
+
+// ParseDate reads an upload date as sites display it: an absolute date
+// ("2024-03-01", "Mon, 02 Jan 2006 15:04:05 -0700") or a relative one
+// ("3 days ago", "5h", "yesterday"). It returns the zero time if the text
+// isn't a date.
+func ParseDate(s string, now time.Time) time.Time {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	lower := strings.ToLower(s)
+	switch lower {
+	case "now", "just now", "today":
+		return now
+	case "yesterday":
+		return now.AddDate(0, 0, -1)
+	}
+
+	m := relativeDateRegex.FindStringSubmatch(lower)
+	if m == nil {
+		return time.Time{}
+	}
+	n := 1
+	if m[1] != "a" && m[1] != "an" {
+		n, _ = strconv.Atoi(m[1])
+	}
+	switch unit := m[2]; {
+	case unit == "mo" || strings.HasPrefix(unit, "month") || unit == "mos":
+		return now.AddDate(0, -n, 0)
+	case strings.HasPrefix(unit, "y"):
+		return now.AddDate(-n, 0, 0)
+	case strings.HasPrefix(unit, "w"):
+		return now.AddDate(0, 0, -7*n)
+	case strings.HasPrefix(unit, "d"):
+		return now.AddDate(0, 0, -n)
+	case strings.HasPrefix(unit, "h"):
+		return now.Add(-time.Duration(n) * time.Hour)
+	case strings.HasPrefix(unit, "m"):
+		return now.Add(-time.Duration(n) * time.Minute)
+	default:
+		return now.Add(-time.Duration(n) * time.Second)
+	}
+}
+
+// Age returns how long ago the torrent was published, 0 if unknown
+func (t Torrent) Age(now time.Time) time.Duration {
+	if t.PublishedAt.IsZero() {
+		return 0
+	}
+	return max(now.Sub(t.PublishedAt), 0)
+}
+
+// fillMetadata derives the structured fields a scraper left out from the
+// ones it set, so every source can be sorted and filtered alike
+func (t *Torrent) fillMetadata() {
+	if t.InfoHash == "" {
+		t.InfoHash = InfohashFromMagnet(t.Magnet)
+	}
+	if t.SizeBytes == 0 && t.Size != "" {
+		t.SizeBytes = ParseSize(t.Size)
+	}
+	if t.Size == "" && t.SizeBytes > 0 {
+		t.Size = formatBytes(t.SizeBytes)
+	}
+}
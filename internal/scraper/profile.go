@@ -0,0 +1,232 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+)
+
+// MaxProfilePages bounds how many result pages a profile may fetch
+const MaxProfilePages = 10
+
+// Profile is a compiled config.ScrapeProfile. It tells GenericScraper
+// where a site's search page is and how to read its rows, so nothing is
+// guessed.
+type Profile struct {
+	config.ScrapeProfile
+	name, size, seeders, leechers, magnet, link field
+	published, category, uploader               field
+}
+
+// field is a compiled config.FieldSelector
+type field struct {
+	config.FieldSelector
+	regex *regexp.Regexp
+}
+
+// NewProfile checks a profile's search URL, selectors and regexes
+func NewProfile(cfg config.ScrapeProfile) (*Profile, error) {
+	if !strings.Contains(cfg.SearchURL, "{query}") {
+		return nil, fmt.Errorf("search_url needs a {query} placeholder")
+	}
+	parsed, err := url.Parse(cfg.SearchURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("search_url must be an http(s) URL")
+	}
+	if cfg.Row == "" {
+		return nil, fmt.Errorf("row selector is required")
+	}
+	if _, err := cascadia.Compile(cfg.Row); err != nil {
+		return nil, fmt.Errorf("row: %w", err)
+	}
+	if cfg.NextPage != "" {
+		if _, err := cascadia.Compile(cfg.NextPage); err != nil {
+			return nil, fmt.Errorf("next_page: %w", err)
+		}
+	}
+
+	p := &Profile{ScrapeProfile: cfg}
+	fields := []struct {
+		name string
+		sel  config.FieldSelector
+		dst  *field
+	}{
+		{"name", cfg.Name, &p.name},
+		{"size", cfg.Size, &p.size},
+		{"seeders", cfg.Seeders, &p.seeders},
+		{"leechers", cfg.Leechers, &p.leechers},
+		{"magnet", cfg.Magnet, &p.magnet},
+		{"link", cfg.Link, &p.link},
+		{"published", cfg.Published, &p.published},
+		{"category", cfg.Category, &p.category},
+		{"uploader", cfg.Uploader, &p.uploader},
+	}
+	for _, f := range fields {
+		*f.dst = field{FieldSelector: f.sel}
+		if f.sel.Selector != "" {
+			if _, err := cascadia.Compile(f.sel.Selector); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+		if f.sel.Regex != "" {
+			if f.dst.regex, err = regexp.Compile(f.sel.Regex); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// pages returns how many result pages to fetch
+func (p *Profile) pages() int {
+	return min(max(p.Pages, 1), MaxProfilePages)
+}
+
+// URL returns the search URL for a query and a result page (from 0). The
+// query is escaped for the part of the URL it appears in.
+func (p *Profile) URL(query string, page int) string {
+	escaped := url.PathEscape(query)
+	if i := strings.Index(p.SearchURL, "?"); i >= 0 && i < strings.Index(p.SearchURL, "{query}") {
+		escaped = url.QueryEscape(query)
+	}
+	r := strings.NewReplacer(
+		"{query}", escaped,
+		"{page}", strconv.Itoa(page+1),
+		"{page0}", strconv.Itoa(page),
+	)
+	return r.Replace(p.SearchURL)
+}
+
+// nextURL returns the URL of the result page after page, or "" if there is
+// none. A next_page selector takes precedence over a {page} placeholder.
+func (p *Profile) nextURL(doc *goquery.Document, pageURL, query string, page int) string {
+	if p.NextPage != "" {
+		href, _ := doc.Find(p.NextPage).First().Attr("href")
+		if href == "" {
+			return ""
+		}
+		return resolveURL(pageURL, href)
+	}
+	if strings.Contains(p.SearchURL, "{page}") || strings.Contains(p.SearchURL, "{page0}") {
+		return p.URL(query, page+1)
+	}
+	return ""
+}
+
+// Parse reads the result rows of a page. Relative links are resolved
+// against pageURL. Rows without a name, or without a magnet and a detail
+// link, are skipped, which drops header rows.
+func (p *Profile) Parse(doc *goquery.Document, pageURL, source string) []Torrent {
+	var results []Torrent
+	now := time.Now()
+	doc.Find(p.Row).Each(func(i int, row *goquery.Selection) {
+		t := Torrent{Source: source}
+
+		t.Magnet = p.magnet.extract(row)
+		if t.Magnet == "" && !p.magnet.set() {
+			t.Magnet, _ = row.Find("a[href^='magnet:']").First().Attr("href")
+		}
+		if t.Magnet != "" && !strings.HasPrefix(t.Magnet, "magnet:") {
+			t.Magnet = resolveURL(pageURL, t.Magnet) // A .torrent link
+		}
+
+		t.Name = p.name.extract(row)
+		if t.Name == "" {
+			t.Name = extractMagnetName(t.Magnet)
+		}
+
+		if p.size.set() {
+			size := p.size.extract(row)
+			if t.Size = extractSize(size); t.Size == "" {
+				t.Size = size
+			}
+		} else {
+			t.Size = extractSize(row.Text())
+		}
+
+		t.Seeders = parseNumber(p.seeders.extract(row))
+		t.Leechers = parseNumber(p.leechers.extract(row))
+		if link := p.link.extract(row); link != "" {
+			t.InfoURL = resolveURL(pageURL, link)
+		}
+		t.PublishedAt = ParseDate(p.published.extract(row), now)
+		t.Category = p.category.extract(row)
+		t.Uploader = p.uploader.extract(row)
+
+		if t.Name != "" && (t.Magnet != "" || t.InfoURL != "") {
+			results = append(results, t)
+		}
+	})
+	return results
+}
+
+// ParseFile reads the result rows of a saved search page, e.g. to test a
+// profile without hitting the site. Links resolve against the search URL.
+func (p *Profile) ParseFile(r io.Reader, source string) ([]Torrent, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(doc, p.URL("", 0), source), nil
+}
+
+// set reports whether the field is configured
+func (f field) set() bool {
+	return f.Selector != "" || f.Attr != "" || f.Regex != ""
+}
+
+// extract returns the field's trimmed value in a row, "" if not found
+func (f field) extract(row *goquery.Selection) string {
+	if !f.set() {
+		return ""
+	}
+	sel := row
+	if f.Selector != "" {
+		sel = row.Find(f.Selector).First()
+		if sel.Length() == 0 {
+			return ""
+		}
+	}
+
+	var value string
+	if f.Attr != "" {
+		value, _ = sel.Attr(f.Attr)
+	} else {
+		value = sel.Text()
+	}
+	value = strings.Join(strings.Fields(value), " ")
+
+	if f.regex != nil {
+		m := f.regex.FindStringSubmatch(value)
+		switch {
+		case m == nil:
+			return ""
+		case len(m) > 1:
+			value = m[1]
+		default:
+			value = m[0]
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// resolveURL makes href absolute relative to base
+func resolveURL(base, href string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return b.ResolveReference(ref).String()
+}
@@ -0,0 +1,297 @@
+package scraper
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/release"
+)
+
+// Query is a search as typed: free text passed to the scrapers, and
+// filters applied to their results. For example
+//
+//	ubuntu size:>2GB seeds:>=10 -beta source:mysrc res:1080p age:<7d
+//
+// searches every source for "ubuntu" and keeps results over 2 GB with at
+// least 10 seeders, no "beta" in the name, from mysrc, in 1080p and
+// uploaded in the last week.
+type Query struct {
+	Text    string
+	Filters []Condition
+}
+
+// Condition is one filter of a query
+type Condition struct {
+	Key   string // Canonical key (Key* constant)
+	Op    string // >, >=, <, <= or = (always = for text keys)
+	Value string // As typed
+
+	num float64       // size in bytes, seeds, leech or health
+	age time.Duration // age
+}
+
+// Filter keys
+const (
+	KeySize     = "size"
+	KeySeeds    = "seeds"
+	KeyLeech    = "leech"
+	KeyHealth   = "health"
+	KeyAge      = "age"
+	KeySource   = "source"
+	KeyRes      = "res"
+	KeyCategory = "cat"
+	KeyExclude  = "-" // A word the name must not contain
+)
+
+// keyAliases maps the keys a query may use to their canonical key
+var keyAliases = map[string]string{
+	"size":  KeySize,
+	"seeds": KeySeeds, "seeders": KeySeeds, "se": KeySeeds,
+	"leech": KeyLeech, "leechers": KeyLeech, "le": KeyLeech, "peers": KeyLeech,
+	"health": KeyHealth,
+	"age":    KeyAge,
+	"source": KeySource, "src": KeySource, "site": KeySource,
+	"res": KeyRes, "resolution": KeyRes,
+	"cat": KeyCategory, "category": KeyCategory,
+}
+
+// textKeys are matched by value, and several conditions on one of them
+// mean "any of these"
+var textKeys = []string{KeySource, KeyRes, KeyCategory}
+
+// ParseQuery splits a query into its text and filters. Double quotes keep
+// spaces together ("the expanse", -"cam rip"). A term with an unknown key
+// stays part of the text; a known key with a bad value is an error.
+func ParseQuery(s string) (Query, error) {
+	var q Query
+	var text []string
+	for _, term := range splitQuery(s) {
+		cond, ok, err := parseCondition(term)
+		if err != nil {
+			return Query{}, err
+		}
+		if ok {
+			q.Filters = append(q.Filters, cond)
+		} else {
+			text = append(text, term)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// ParseCondition parses a single filter term such as "size:>2GB"
+func ParseCondition(term string) (Condition, error) {
+	cond, ok, err := parseCondition(term)
+	if err == nil && !ok {
+		err = fmt.Errorf("%q is not a filter", term)
+	}
+	return cond, err
+}
+
+// splitQuery splits on spaces outside double quotes, dropping the quotes
+func splitQuery(s string) []string {
+	var terms []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if cur.Len() > 0 {
+				terms = append(terms, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		terms = append(terms, cur.String())
+	}
+	return terms
+}
+
+// parseCondition parses a filter term; ok is false if the term is text
+func parseCondition(term string) (cond Condition, ok bool, err error) {
+	if len(term) > 1 && term[0] == '-' {
+		return Condition{Key: KeyExclude, Op: "=", Value: term[1:]}, true, nil
+	}
+
+	name, value, found := strings.Cut(term, ":")
+	key, known := keyAliases[strings.ToLower(name)]
+	if !found || !known {
+		return Condition{}, false, nil
+	}
+
+	cond = Condition{Key: key, Op: "="}
+	if !slices.Contains(textKeys, key) {
+		for _, op := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(value, op) {
+				cond.Op = op
+				value = value[len(op):]
+				break
+			}
+		}
+	}
+	cond.Value = value
+	if value == "" {
+		return Condition{}, false, fmt.Errorf("%s: missing value", term)
+	}
+
+	switch key {
+	case KeySize:
+		cond.num = float64(ParseSize(value))
+		if cond.num == 0 {
+			return Condition{}, false, fmt.Errorf("%s: expected a size like 700MB or 2GB", term)
+		}
+	case KeySeeds, KeyLeech, KeyHealth:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return Condition{}, false, fmt.Errorf("%s: expected a number", term)
+		}
+		cond.num = float64(n)
+	case KeyAge:
+		now := time.Now()
+		at := ParseDate(value, now)
+		if at.IsZero() || !at.Before(now) {
+			return Condition{}, false, fmt.Errorf("%s: expected an age like 12h, 7d, 2w, 3mo or 1y", term)
+		}
+		cond.age = now.Sub(at)
+		if cond.Op == "=" {
+			cond.Op = "<=" // age:7d means within a week
+		}
+	case KeyRes:
+		cond.Value = strings.ToLower(value)
+		if res := release.Resolution(value); res != "" {
+			cond.Value = res
+		}
+	}
+	return cond, true, nil
+}
+
+// String formats the condition the way it is typed
+func (c Condition) String() string {
+	if c.Key == KeyExclude {
+		return "-" + quoteTerm(c.Value)
+	}
+	op := c.Op
+	if op == "=" {
+		op = ""
+	}
+	return c.Key + ":" + op + quoteTerm(c.Value)
+}
+
+// quoteTerm quotes a value containing spaces
+func quoteTerm(s string) string {
+	if strings.Contains(s, " ") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// compare applies the condition's operator to a value
+func (c Condition) compare(v float64) bool {
+	switch c.Op {
+	case ">":
+		return v > c.num
+	case ">=":
+		return v >= c.num
+	case "<":
+		return v < c.num
+	case "<=":
+		return v <= c.num
+	default:
+		return v == c.num
+	}
+}
+
+// Match reports whether a result satisfies the condition. Results of
+// unknown size or age never satisfy a condition on it.
+func (c Condition) Match(t Torrent, now time.Time) bool {
+	switch c.Key {
+	case KeySize:
+		return t.SizeBytes > 0 && c.compare(float64(t.SizeBytes))
+	case KeySeeds:
+		return c.compare(float64(t.Seeders))
+	case KeyLeech:
+		return c.compare(float64(t.Leechers))
+	case KeyHealth:
+		return c.compare(float64(t.HealthAt(now)))
+	case KeyAge:
+		if t.PublishedAt.IsZero() {
+			return false
+		}
+		// Ages compare like sizes: age:<7d is newer than a week
+		age := t.Age(now)
+		switch c.Op {
+		case ">":
+			return age > c.age
+		case ">=":
+			return age >= c.age
+		case "<":
+			return age < c.age
+		default:
+			return age <= c.age
+		}
+	case KeySource:
+		for _, src := range t.Sources() {
+			if strings.EqualFold(src, c.Value) {
+				return true
+			}
+		}
+		return false
+	case KeyRes:
+		return nameHasResolution(t.Name, c.Value)
+	case KeyCategory:
+		return strings.Contains(strings.ToLower(t.Category), strings.ToLower(c.Value))
+	case KeyExclude:
+		return !strings.Contains(strings.ToLower(t.Name), strings.ToLower(c.Value))
+	}
+	return true
+}
+
+// nameHasResolution reports whether a release name is in a resolution:
+// the one parsed from it, or for an unknown spelling, a word of the name
+func nameHasResolution(name, res string) bool {
+	if release.Resolution(res) != "" {
+		return release.Parse(name).Resolution == res
+	}
+	return strings.Contains(strings.ToLower(name), res)
+}
+
+// MatchAll reports whether a result satisfies every condition. Several
+// conditions on the same text key (source, res, cat) are alternatives.
+func MatchAll(conds []Condition, t Torrent, now time.Time) bool {
+	anyOf := make(map[string]bool) // Text key -> some condition matched
+	for _, c := range conds {
+		if slices.Contains(textKeys, c.Key) {
+			anyOf[c.Key] = anyOf[c.Key] || c.Match(t, now)
+			continue
+		}
+		if !c.Match(t, now) {
+			return false
+		}
+	}
+	for _, matched := range anyOf {
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the results satisfying every condition, in order
+func Apply(conds []Condition, torrents []Torrent, now time.Time) []Torrent {
+	var out []Torrent
+	for _, t := range torrents {
+		if MatchAll(conds, t, now) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
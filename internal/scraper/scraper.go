@@ -4,18 +4,36 @@
 // which works with any site that provides magnet links.
 package scraper
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
 
 // Torrent represents a search result
 type Torrent struct {
 	Name     string
-	Size     string
+	Size     string // As displayed ("1.4 GB")
 	Seeders  int
 	Leechers int
 	Magnet   string
 	InfoURL  string
 	Source   string
+	InfoHash string // Lowercase hex btih ("" if unknown)
 	Files    []FileInfo
+
+	// Structured metadata, zero if the source doesn't give it
+	SizeBytes   int64
+	PublishedAt time.Time
+	Category    string
+	Uploader    string
+	Extra       map[string]string // Other attributes the source reports
+
+	// Listings holds each source's own listing when results from several
+	// sources were merged by Dedupe (nil for a single listing)
+	Listings []Torrent
 }
 
 // FileInfo represents a file within a torrent
@@ -24,18 +42,33 @@ type FileInfo struct {
 	Size string
 }
 
-// Health returns a health score 0-100 based on seeders/leechers ratio
+// Ages between which Health discounts a listing: peer counts of old
+// listings are often stale, so they lose up to half their score
+const (
+	healthFreshAge = 30 * 24 * time.Hour
+	healthStaleAge = 2 * 365 * 24 * time.Hour
+)
+
+// Health returns a health score 0-100 based on seeders/leechers ratio and
+// the listing's age
 func (t Torrent) Health() int {
+	return t.HealthAt(time.Now())
+}
+
+// HealthAt is Health at the given time
+func (t Torrent) HealthAt(now time.Time) int {
 	if t.Seeders == 0 {
 		return 0
 	}
-	if t.Leechers == 0 {
-		return 100
+
+	ratio := 100.0
+	if t.Leechers > 0 {
+		ratio = min(float64(t.Seeders)/float64(t.Seeders+t.Leechers)*100, 100)
 	}
 
-	ratio := float64(t.Seeders) / float64(t.Seeders+t.Leechers) * 100
-	if ratio > 100 {
-		ratio = 100
+	if age := t.Age(now); age > healthFreshAge {
+		stale := min(float64(age-healthFreshAge)/float64(healthStaleAge-healthFreshAge), 1)
+		ratio *= 1 - stale/2
 	}
 	return int(ratio)
 }
@@ -52,9 +85,45 @@ type Scraper interface {
 	GetFiles(ctx context.Context, t *Torrent) error
 }
 
-// MultiScraper aggregates results from multiple sources
+// DefaultSourceTimeout bounds how long MultiScraper waits for one source
+const DefaultSourceTimeout = 20 * time.Second
+
+// MultiScraper aggregates results from multiple sources, querying them
+// concurrently
 type MultiScraper struct {
 	scrapers []Scraper
+	Timeout  time.Duration // Per-source timeout (0 = DefaultSourceTimeout)
+}
+
+// SourceStatus reports how one source did in a search
+type SourceStatus struct {
+	Source  string
+	Count   int
+	Latency time.Duration
+	Err     error
+}
+
+// TimedOut reports whether the source hit its timeout or the search deadline
+func (s SourceStatus) TimedOut() bool {
+	return errors.Is(s.Err, context.DeadlineExceeded)
+}
+
+// String formats the status for display: "src-a: 42 (0.8s)", "src-b: timeout"
+func (s SourceStatus) String() string {
+	switch {
+	case s.TimedOut():
+		return s.Source + ": timeout"
+	case s.Err != nil:
+		return s.Source + ": error"
+	}
+	return fmt.Sprintf("%s: %d (%.1fs)", s.Source, s.Count, s.Latency.Seconds())
+}
+
+// SourceResult is what one source returned
+type SourceResult struct {
+	SourceStatus
+	Results []Torrent
+	index   int // Position of the source in the MultiScraper
 }
 
 // NewMultiScraper creates a scraper that queries multiple sources
@@ -62,17 +131,97 @@ func NewMultiScraper(scrapers ...Scraper) *MultiScraper {
 	return &MultiScraper{scrapers: scrapers}
 }
 
-// Search queries all scrapers and merges results
-func (m *MultiScraper) Search(ctx context.Context, query string) ([]Torrent, error) {
-	var results []Torrent
+// Stream queries all scrapers concurrently and sends each source's results
+// as soon as it finishes. The channel is closed once every source has
+// reported. Each source gets its own timeout within ctx's deadline; a
+// source that ignores cancellation is reported as timed out regardless.
+func (m *MultiScraper) Stream(ctx context.Context, query string) <-chan SourceResult {
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSourceTimeout
+	}
+
+	// Buffered so sources never block, even if the reader gives up
+	out := make(chan SourceResult, len(m.scrapers))
+	var wg sync.WaitGroup
+	for i, s := range m.scrapers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := searchSource(ctx, s, query, timeout)
+			res.index = i
+			out <- res
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
 
-	for _, s := range m.scrapers {
+// searchSource runs one source's search under its timeout
+func searchSource(ctx context.Context, s Scraper, query string, timeout time.Duration) SourceResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type searchResult struct {
+		torrents []Torrent
+		err      error
+	}
+	start := time.Now()
+	done := make(chan searchResult, 1)
+	go func() {
 		torrents, err := s.Search(ctx, query)
-		if err != nil {
-			continue // Skip failed sources
+		done <- searchResult{torrents, err}
+	}()
+
+	res := SourceResult{SourceStatus: SourceStatus{Source: s.Name()}}
+	select {
+	case r := <-done:
+		res.Results, res.Err = r.torrents, r.err
+		// Scrapers wrap errors inconsistently; trust the context
+		if r.err != nil && ctx.Err() != nil {
+			res.Err = ctx.Err()
+		}
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+	}
+	res.Latency = time.Since(start)
+	res.Count = len(res.Results)
+	for i := range res.Results {
+		res.Results[i].fillMetadata()
+	}
+	return res
+}
+
+// Search queries all scrapers concurrently and merges their results,
+// combining listings of the same torrent (see Dedupe). Statuses are in the
+// order the scrapers were given. An error is only returned if every source
+// failed.
+func (m *MultiScraper) Search(ctx context.Context, query string) ([]Torrent, []SourceStatus, error) {
+	var results []Torrent
+	statuses := make([]SourceStatus, len(m.scrapers))
+	byIndex := make([][]Torrent, len(m.scrapers))
+	var lastErr error
+	for r := range m.Stream(ctx, query) {
+		statuses[r.index] = r.SourceStatus
+		byIndex[r.index] = r.Results
+		if r.Err != nil {
+			lastErr = fmt.Errorf("%s: %w", r.Source, r.Err)
 		}
+	}
+	// Keep results in source order so the merge is deterministic
+	failed := 0
+	for i, torrents := range byIndex {
 		results = append(results, torrents...)
+		if statuses[i].Err != nil {
+			failed++
+		}
 	}
 
-	return results, nil
+	if failed > 0 && failed == len(m.scrapers) {
+		return nil, statuses, lastErr
+	}
+	return Dedupe(results), statuses, nil
 }
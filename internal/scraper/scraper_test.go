@@ -1,7 +1,592 @@
 package scraper
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+)
 
 func TestPackageCompiles(t *testing.T) {
 	// placeholder
 }
+
+// newTorznabFixture serves the testdata fixtures like a Torznab indexer
+// that requires the API key "secret"
+func newTorznabFixture(t *testing.T) (*httptest.Server, *[]url.Values) {
+	t.Helper()
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, query)
+		if query.Get("apikey") != "secret" {
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><error code="100" description="Incorrect user credentials"/>`))
+			return
+		}
+		var fixture string
+		switch query.Get("t") {
+		case "caps":
+			fixture = "testdata/torznab_caps.xml"
+		case "search":
+			fixture = "testdata/torznab_search.xml"
+		default:
+			http.Error(w, "unknown function", http.StatusBadRequest)
+			return
+		}
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestTorznabCaps(t *testing.T) {
+	srv, _ := newTorznabFixture(t)
+	s := NewTorznabScraper("fixture", srv.URL+"/api?apikey=secret", "", nil)
+
+	caps, err := s.Caps(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Server != "Fixture Indexer" {
+		t.Errorf("server = %q", caps.Server)
+	}
+	if !caps.Searching["search"].Available || caps.Searching["movie-search"].Available {
+		t.Errorf("searching = %+v", caps.Searching)
+	}
+	if got := caps.Searching["tv-search"].SupportedParams; len(got) != 3 || got[2] != "ep" {
+		t.Errorf("tv-search params = %v", got)
+	}
+	if len(caps.Categories) != 2 || caps.Categories[0].Subcats[0].ID != 2040 {
+		t.Errorf("categories = %+v", caps.Categories)
+	}
+}
+
+func TestTorznabSearch(t *testing.T) {
+	srv, requests := newTorznabFixture(t)
+	s := NewTorznabScraper("fixture", srv.URL+"/api", "secret", []int{2000, 5000})
+
+	results, err := s.Search(context.Background(), "linux iso")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (item without a link skipped): %+v", len(results), results)
+	}
+
+	debian := results[0]
+	if debian.Name != "Debian 12.5 amd64 DVD" || debian.Seeders != 120 || debian.Leechers != 15 {
+		t.Errorf("debian = %+v", debian)
+	}
+	if debian.Size != "3.7 GB" {
+		t.Errorf("size = %q, want 3.7 GB", debian.Size)
+	}
+	if !strings.HasPrefix(debian.Magnet, "magnet:?xt=urn:btih:abcdef0123456789abcdef0123456789abcdef01") {
+		t.Errorf("magnet from infohash = %q", debian.Magnet)
+	}
+	if debian.InfoURL != "https://indexer.local/details/1" || debian.Source != "fixture" {
+		t.Errorf("info URL/source = %q/%q", debian.InfoURL, debian.Source)
+	}
+	published := time.Date(2024, 3, 2, 10, 15, 0, 0, time.UTC)
+	if debian.SizeBytes != 3992977408 || !debian.PublishedAt.Equal(published) || debian.Category != "4000" {
+		t.Errorf("metadata: size %d, published %v, category %q", debian.SizeBytes, debian.PublishedAt, debian.Category)
+	}
+	if debian.Extra["grabs"] != "512" || debian.Extra["seeders"] != "" {
+		t.Errorf("extra = %v", debian.Extra)
+	}
+
+	fedora := results[1]
+	if fedora.Magnet != "magnet:?xt=urn:btih:1111111111111111111111111111111111111111&dn=Fedora" {
+		t.Errorf("magneturl not used: %q", fedora.Magnet)
+	}
+	if fedora.Size != "2.0 GB" || fedora.Leechers != 2 || fedora.InfoURL != "" {
+		t.Errorf("fedora = %+v", fedora)
+	}
+
+	last := (*requests)[len(*requests)-1]
+	if last.Get("t") != "search" || last.Get("q") != "linux iso" || last.Get("cat") != "2000,5000" {
+		t.Errorf("search request = %v", last)
+	}
+}
+
+func TestTorznabReportsAPIErrors(t *testing.T) {
+	srv, _ := newTorznabFixture(t)
+	s := NewTorznabScraper("fixture", srv.URL+"/api", "wrong", nil)
+
+	_, err := s.Search(context.Background(), "anything")
+	if err == nil || !strings.Contains(err.Error(), "Incorrect user credentials") {
+		t.Errorf("err = %v, want credentials error", err)
+	}
+}
+
+func TestSplitTorznabURL(t *testing.T) {
+	endpoint, key := SplitTorznabURL("http://jackett:9117/api/v2.0/indexers/all/results/torznab/api?apikey=abc&t=caps")
+	if endpoint != "http://jackett:9117/api/v2.0/indexers/all/results/torznab/api" || key != "abc" {
+		t.Errorf("SplitTorznabURL = %q, %q", endpoint, key)
+	}
+}
+
+// fakeScraper returns fixed results after a delay. With ignoreCtx it keeps
+// "working" past cancellation, like a scraper stuck in a read.
+type fakeScraper struct {
+	name      string
+	delay     time.Duration
+	results   []Torrent
+	err       error
+	ignoreCtx bool
+}
+
+func (s *fakeScraper) Name() string { return s.name }
+
+func (s *fakeScraper) Search(ctx context.Context, query string) ([]Torrent, error) {
+	if s.ignoreCtx {
+		time.Sleep(s.delay)
+		return s.results, s.err
+	}
+	select {
+	case <-time.After(s.delay):
+		return s.results, s.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *fakeScraper) GetFiles(ctx context.Context, t *Torrent) error { return nil }
+
+func TestMultiScraperSearch(t *testing.T) {
+	m := NewMultiScraper(
+		&fakeScraper{name: "slow", delay: 30 * time.Millisecond, results: []Torrent{{Name: "b"}}},
+		&fakeScraper{name: "fast", results: []Torrent{{Name: "a1"}, {Name: "a2"}}},
+		&fakeScraper{name: "broken", err: errors.New("boom")},
+		&fakeScraper{name: "stuck", delay: time.Second, ignoreCtx: true},
+	)
+	m.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	results, statuses, err := m.Search(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("search took %v; a stuck source should not hold it up", elapsed)
+	}
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "b,a1,a2" {
+		t.Errorf("results = %v, want source order [b a1 a2]", names)
+	}
+
+	want := []string{"slow", "fast", "broken", "stuck"}
+	for i, s := range statuses {
+		if s.Source != want[i] {
+			t.Errorf("status %d is for %q, want %q", i, s.Source, want[i])
+		}
+	}
+	if statuses[1].Count != 2 || statuses[1].Err != nil {
+		t.Errorf("fast status = %+v", statuses[1])
+	}
+	if statuses[2].String() != "broken: error" || statuses[3].String() != "stuck: timeout" {
+		t.Errorf("statuses = %v, %v", statuses[2], statuses[3])
+	}
+}
+
+func TestMultiScraperStreamsAsSourcesFinish(t *testing.T) {
+	m := NewMultiScraper(
+		&fakeScraper{name: "slow", delay: 50 * time.Millisecond},
+		&fakeScraper{name: "fast"},
+	)
+	var order []string
+	for r := range m.Stream(context.Background(), "q") {
+		order = append(order, r.Source)
+	}
+	if strings.Join(order, ",") != "fast,slow" {
+		t.Errorf("stream order = %v, want [fast slow]", order)
+	}
+}
+
+func TestMultiScraperAllFailed(t *testing.T) {
+	m := NewMultiScraper(&fakeScraper{name: "a", err: errors.New("boom")})
+	if _, _, err := m.Search(context.Background(), "q"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("err = %v, want the source error", err)
+	}
+}
+
+func TestNormalizeInfohash(t *testing.T) {
+	const hexHash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	tests := []struct {
+		in, want string
+	}{
+		{"C12FE1C06BBA254A9DC9F519B335AA7C1367A88A", hexHash},
+		{"YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK", hexHash}, // Same hash in base32
+		{"yex6dqdlxisuvhoj6um3gnnkpqjwpkek", hexHash},
+		{"not-a-hash", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeInfohash(tt.in); got != tt.want {
+			t.Errorf("NormalizeInfohash(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	magnet := "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK&dn=x"
+	if got := InfohashFromMagnet(magnet); got != hexHash {
+		t.Errorf("InfohashFromMagnet = %q, want %q", got, hexHash)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	const hash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	results := []Torrent{
+		{Name: "Linux ISO", Source: "a", Seeders: 10, Leechers: 1, InfoURL: "https://a/1",
+			Magnet: "magnet:?xt=urn:btih:" + strings.ToUpper(hash)},
+		{Name: "no hash", Source: "a", InfoURL: "https://a/2"},
+		{Name: "no hash", Source: "b", InfoURL: "https://b/2"},
+		{Name: "linux.iso", Source: "b", Seeders: 25, Leechers: 0, InfoURL: "https://b/1",
+			Magnet: "magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK"},
+	}
+
+	merged := Dedupe(results)
+	if len(merged) != 3 {
+		t.Fatalf("got %d results, want 3: %+v", len(merged), merged)
+	}
+	m := merged[0]
+	if m.Name != "Linux ISO" || m.Seeders != 25 || m.Leechers != 1 || m.InfoHash != hash {
+		t.Errorf("merged = %+v", m)
+	}
+	if got := strings.Join(m.Sources(), ","); got != "a,b" {
+		t.Errorf("sources = %s, want a,b", got)
+	}
+	if got := strings.Join(m.InfoURLs(), ","); got != "https://a/1,https://b/1" {
+		t.Errorf("info URLs = %s", got)
+	}
+	if len(m.Listings) != 2 || m.Listings[0].Seeders != 10 || m.Listings[1].Seeders != 25 {
+		t.Errorf("listings = %+v", m.Listings)
+	}
+
+	// Merging again with another listing keeps the listings flat
+	again := Dedupe(append(merged, Torrent{Source: "c", Seeders: 3, Magnet: "magnet:?xt=urn:btih:" + hash}))
+	if len(again) != 3 || len(again[0].Listings) != 3 || strings.Join(again[0].Sources(), ",") != "a,b,c" {
+		t.Errorf("re-merge = %+v", again[0])
+	}
+	if len(merged[0].Listings) != 2 {
+		t.Error("re-merge modified the earlier result")
+	}
+}
+
+func testProfile(t *testing.T, searchURL string) *Profile {
+	t.Helper()
+	p, err := NewProfile(config.ScrapeProfile{
+		SearchURL: searchURL,
+		Row:       "table.results tbody tr",
+		Name:      config.FieldSelector{Selector: "td.name a"},
+		Size:      config.FieldSelector{Selector: "td.size"},
+		Seeders:   config.FieldSelector{Selector: "td.seeds"},
+		Leechers:  config.FieldSelector{Selector: "td.leeches"},
+		Magnet:    config.FieldSelector{Selector: "a[href^='magnet:'], a[href$='.torrent']", Attr: "href"},
+		Link:      config.FieldSelector{Selector: "td.name a", Attr: "href"},
+		Published: config.FieldSelector{Selector: "td.added"},
+		NextPage:  "a.next",
+		Pages:     3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProfileParseFile(t *testing.T) {
+	f, err := os.Open("testdata/profile_search.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	p := testProfile(t, "https://site.local/search?q={query}")
+	got, err := p.ParseFile(f, "site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 rows (header and ad skipped), got %d: %+v", len(got), got)
+	}
+
+	debian := got[0]
+	if debian.Name != "Debian 12.5 amd64 DVD" || debian.Seeders != 1204 || debian.Leechers != 35 {
+		t.Errorf("debian row: %+v", debian)
+	}
+	if debian.Size != "3.7 GiB" || debian.InfoURL != "https://site.local/details/1" || !strings.HasPrefix(debian.Magnet, "magnet:") {
+		t.Errorf("debian row: %+v", debian)
+	}
+	if got[1].Magnet != "https://site.local/download/2.torrent" || got[1].Size != "2.1 GB" {
+		t.Errorf("relative .torrent link not resolved: %+v", got[1])
+	}
+	if !debian.PublishedAt.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || got[1].PublishedAt.IsZero() {
+		t.Errorf("published = %v, %v", debian.PublishedAt, got[1].PublishedAt)
+	}
+}
+
+func TestProfileFieldRegex(t *testing.T) {
+	p, err := NewProfile(config.ScrapeProfile{
+		SearchURL: "https://site.local/s/{query}",
+		Row:       "li",
+		Name:      config.FieldSelector{Selector: "a", Attr: "title", Regex: `^Download (.+)$`},
+		Seeders:   config.FieldSelector{Regex: `S: (\d+)`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := `<ul><li><a title="Download Some Release" href="magnet:?xt=urn:btih:x">get</a> S: 17 L: 3</li></ul>`
+	got, err := p.ParseFile(strings.NewReader(html), "site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "Some Release" || got[0].Seeders != 17 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestProfileURL(t *testing.T) {
+	tests := []struct {
+		template string
+		page     int
+		want     string
+	}{
+		{"https://site.local/search?q={query}", 0, "https://site.local/search?q=big+buck"},
+		{"https://site.local/search/{query}/{page}/", 1, "https://site.local/search/big%20buck/2/"},
+		{"https://site.local/search?q={query}&offset={page0}", 2, "https://site.local/search?q=big+buck&offset=2"},
+	}
+	for _, tt := range tests {
+		p := &Profile{ScrapeProfile: config.ScrapeProfile{SearchURL: tt.template}}
+		if got := p.URL("big buck", tt.page); got != tt.want {
+			t.Errorf("URL(%q, %d) = %q, want %q", tt.template, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestNewProfileRejectsBadConfig(t *testing.T) {
+	tests := []config.ScrapeProfile{
+		{SearchURL: "https://site.local/search", Row: "tr"},   // No {query}
+		{SearchURL: "site.local/{query}", Row: "tr"},          // Not http(s)
+		{SearchURL: "https://site.local/{query}"},             // No row
+		{SearchURL: "https://site.local/{query}", Row: "tr["}, // Bad selector
+		{SearchURL: "https://site.local/{query}", Row: "tr", NextPage: "a["},
+		{SearchURL: "https://site.local/{query}", Row: "tr", Size: config.FieldSelector{Regex: "("}},
+	}
+	for _, cfg := range tests {
+		if _, err := NewProfile(cfg); err == nil {
+			t.Errorf("NewProfile(%+v) accepted", cfg)
+		}
+	}
+}
+
+func TestGenericScraperFollowsProfile(t *testing.T) {
+	page, err := os.ReadFile("testdata/profile_search.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		if r.URL.Query().Get("p") == "2" {
+			// Last page: no rows
+			_, _ = w.Write([]byte(`<table class="results"><tbody></tbody></table>`))
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	s := NewGenericScraper("site", srv.URL)
+	s.SetProfile(testProfile(t, srv.URL+"/search?q={query}"))
+	got, err := s.Search(context.Background(), "linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected the 2 rows of the first page, got %d", len(got))
+	}
+	want := []string{"/search?q=linux", "/search?q=linux&p=2"}
+	if strings.Join(requests, " ") != strings.Join(want, " ") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1.5 GB", 3 << 29},
+		{"700MiB", 700 << 20},
+		{"2.5 GiB", 5 << 29},
+		{"1,024 MB", 1 << 30},
+		{"1,024.5 KB", 1049088},
+		{"1,5 GB", 3 << 29},
+		{"Size: 2 TB, 3 files", 2 << 40},
+		{"512 B", 512},
+		{"", 0},
+		{"unknown", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseSize(tt.in); got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-01 08:30", time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)},
+		{"Sat, 02 Mar 2024 10:15:00 +0000", time.Date(2024, 3, 2, 10, 15, 0, 0, time.UTC)},
+		{"Mar. 2, 2024", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"3 days ago", now.AddDate(0, 0, -3)},
+		{"5h", now.Add(-5 * time.Hour)},
+		{"an hour ago", now.Add(-time.Hour)},
+		{"2 mos ago", now.AddDate(0, -2, 0)},
+		{"1 year ago", now.AddDate(-1, 0, 0)},
+		{"yesterday", now.AddDate(0, 0, -1)},
+		{"Today", now},
+		{"", time.Time{}},
+		{"1080p", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := ParseDate(tt.in, now); !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHealthFactorsInAge(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	tor := Torrent{Seeders: 90, Leechers: 10}
+	if got := tor.HealthAt(now); got != 90 {
+		t.Errorf("unknown age: health %d, want 90", got)
+	}
+	tor.PublishedAt = now.AddDate(0, 0, -7)
+	if got := tor.HealthAt(now); got != 90 {
+		t.Errorf("fresh: health %d, want 90", got)
+	}
+	tor.PublishedAt = now.AddDate(-5, 0, 0)
+	if got := tor.HealthAt(now); got != 45 {
+		t.Errorf("stale: health %d, want 45", got)
+	}
+	tor.PublishedAt = now.AddDate(-1, 0, 0)
+	if got := tor.HealthAt(now); got <= 45 || got >= 90 {
+		t.Errorf("a year old: health %d, want between 45 and 90", got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(`ubuntu size:>2GB seeds:>=10 -beta source:mysrc res:1080P age:7d "desktop iso" foo:bar`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Text != "ubuntu desktop iso foo:bar" {
+		t.Errorf("text = %q", q.Text)
+	}
+	var terms []string
+	for _, c := range q.Filters {
+		terms = append(terms, c.String())
+	}
+	want := "size:>2GB seeds:>=10 -beta source:mysrc res:1080p age:<=7d"
+	if got := strings.Join(terms, " "); got != want {
+		t.Errorf("filters = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"x size:>huge", "x seeds:>=many", "x age:<soon", "x res:"} {
+		if _, err := ParseQuery(bad); err == nil {
+			t.Errorf("ParseQuery(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	now := time.Now()
+	results := []Torrent{
+		{Name: "Ubuntu 24.04 1080p", Source: "a", SizeBytes: 500 << 20, Seeders: 50, PublishedAt: now.AddDate(0, 0, -1)},
+		{Name: "Ubuntu 24.10 Beta 4K", Source: "b", SizeBytes: 8 << 30, Seeders: 5, PublishedAt: now.AddDate(-1, 0, 0)},
+		{Name: "Ubuntu unknown", Source: "c"},
+	}
+	names := func(ts []Torrent) string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"x", "Ubuntu 24.04 1080p,Ubuntu 24.10 Beta 4K,Ubuntu unknown"},
+		{"x size:>=1GB", "Ubuntu 24.10 Beta 4K"},
+		{"x size:<1GB", "Ubuntu 24.04 1080p"},
+		{"x age:<7d", "Ubuntu 24.04 1080p"},
+		{"x size:>=1GB age:<7d", ""},
+		{"x seeds:>10", "Ubuntu 24.04 1080p"},
+		{"x -beta", "Ubuntu 24.04 1080p,Ubuntu unknown"},
+		{"x res:2160p", "Ubuntu 24.10 Beta 4K"},
+		{"x source:a source:C", "Ubuntu 24.04 1080p,Ubuntu unknown"},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		if got := names(Apply(q.Filters, results, now)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGenericTableMetadata(t *testing.T) {
+	html := `<table>
+		<tr><th>Name</th></tr>
+		<tr>
+			<td><a href="/torrent/1">Some Release 1080p</a></td>
+			<td class="cat">Movies</td>
+			<td>1.4 GB</td>
+			<td class="seeds">12</td>
+			<td class="leeches">3</td>
+			<td class="date">2024-02-10</td>
+			<td class="uploader">someone</td>
+		</tr>
+	</table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGenericScraper("site", "https://site.local")
+	got := s.extractFromTables(doc)
+	if len(got) != 1 {
+		t.Fatalf("got %+v", got)
+	}
+	r := got[0]
+	r.fillMetadata()
+	if r.SizeBytes != ParseSize("1.4 GB") || r.Category != "Movies" || r.Uploader != "someone" {
+		t.Errorf("metadata = %+v", r)
+	}
+	if !r.PublishedAt.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)) || r.Seeders != 12 || r.Leechers != 3 {
+		t.Errorf("date/peers = %+v", r)
+	}
+}
@@ -0,0 +1,384 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TorznabScraper searches a Torznab (or Newznab) compatible indexer API,
+// such as Jackett, Prowlarr or a self-hosted aggregator. Unlike
+// GenericScraper there is nothing to guess: the API reports what it
+// supports through t=caps and returns structured RSS results.
+type TorznabScraper struct {
+	name       string
+	apiURL     string // Endpoint, e.g. http://localhost:9117/api/v2.0/indexers/all/results/torznab/api
+	apiKey     string
+	categories []int // Category IDs to search (empty = all)
+	client     *http.Client
+
+	mu   sync.Mutex
+	caps *TorznabCaps // Cached after the first successful t=caps
+}
+
+// TorznabCaps describes what a Torznab indexer supports
+type TorznabCaps struct {
+	Server     string                   // Server title, if reported
+	Searching  map[string]TorznabSearch // Keyed by mode: search, tv-search, movie-search, ...
+	Categories []TorznabCategory
+}
+
+// TorznabSearch describes one search mode of an indexer
+type TorznabSearch struct {
+	Available       bool
+	SupportedParams []string
+}
+
+// TorznabCategory is an indexer category with its subcategories
+type TorznabCategory struct {
+	ID      int
+	Name    string
+	Subcats []TorznabCategory
+}
+
+// NewTorznabScraper creates a scraper for a Torznab API endpoint. An apikey
+// query parameter in apiURL is used when apiKey is empty.
+func NewTorznabScraper(name, apiURL, apiKey string, categories []int) *TorznabScraper {
+	apiURL, urlKey := SplitTorznabURL(apiURL)
+	if apiKey == "" {
+		apiKey = urlKey
+	}
+	return &TorznabScraper{
+		name:       name,
+		apiURL:     apiURL,
+		apiKey:     apiKey,
+		categories: categories,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SplitTorznabURL separates the apikey query parameter from an endpoint URL,
+// so the key can be stored apart from the URL shown in the Sources tab
+func SplitTorznabURL(rawURL string) (endpoint, apiKey string) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return strings.TrimSpace(rawURL), ""
+	}
+	query := parsed.Query()
+	apiKey = query.Get("apikey")
+	query.Del("apikey")
+	query.Del("t")
+	parsed.RawQuery = query.Encode()
+	return strings.TrimSuffix(parsed.String(), "?"), apiKey
+}
+
+// Name returns the source name
+func (s *TorznabScraper) Name() string {
+	return s.name
+}
+
+// Caps fetches (and caches) the indexer's capabilities
+func (s *TorznabScraper) Caps(ctx context.Context) (*TorznabCaps, error) {
+	s.mu.Lock()
+	cached := s.caps
+	s.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var doc torznabCapsDoc
+	if err := s.get(ctx, url.Values{"t": {"caps"}}, &doc); err != nil {
+		return nil, err
+	}
+	caps := doc.caps()
+
+	s.mu.Lock()
+	s.caps = caps
+	s.mu.Unlock()
+	return caps, nil
+}
+
+// Search queries the indexer. If the indexer doesn't list the plain search
+// mode as available, an error is returned instead of an empty result.
+func (s *TorznabScraper) Search(ctx context.Context, query string) ([]Torrent, error) {
+	// Caps are advisory; some indexers don't implement t=caps properly
+	if caps, err := s.Caps(ctx); err == nil {
+		if mode, ok := caps.Searching["search"]; ok && !mode.Available {
+			return nil, fmt.Errorf("%s: search not supported by indexer", s.name)
+		}
+	}
+
+	params := url.Values{"t": {"search"}, "q": {query}}
+	if len(s.categories) > 0 {
+		cats := make([]string, len(s.categories))
+		for i, c := range s.categories {
+			cats[i] = strconv.Itoa(c)
+		}
+		params.Set("cat", strings.Join(cats, ","))
+	}
+
+	var feed torznabFeed
+	if err := s.get(ctx, params, &feed); err != nil {
+		return nil, err
+	}
+
+	results := make([]Torrent, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		if t, ok := item.torrent(s.name); ok {
+			results = append(results, t)
+		}
+	}
+	return results, nil
+}
+
+// GetFiles is a no-op: Torznab results carry no file lists, and the
+// download link is already part of the search result
+func (s *TorznabScraper) GetFiles(ctx context.Context, t *Torrent) error {
+	return nil
+}
+
+// get performs an API request and decodes the XML response into out.
+// Newznab-style <error> responses are returned as errors.
+func (s *TorznabScraper) get(ctx context.Context, params url.Values, out any) error {
+	if s.apiKey != "" {
+		params.Set("apikey", s.apiKey)
+	}
+	reqURL := s.apiURL
+	if strings.Contains(reqURL, "?") {
+		reqURL += "&" + params.Encode()
+	} else {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/xml, text/xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return err
+	}
+
+	var apiErr torznabError
+	if xml.Unmarshal(body, &apiErr) == nil && apiErr.XMLName.Local == "error" {
+		return fmt.Errorf("%s: %s (code %s)", s.name, apiErr.Description, apiErr.Code)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", s.name, resp.StatusCode)
+	}
+	if err := xml.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", s.name, err)
+	}
+	return nil
+}
+
+// torznabError is the <error code="..." description="..."/> response
+type torznabError struct {
+	XMLName     xml.Name
+	Code        string `xml:"code,attr"`
+	Description string `xml:"description,attr"`
+}
+
+// torznabCapsDoc is the t=caps response
+type torznabCapsDoc struct {
+	Server struct {
+		Title string `xml:"title,attr"`
+	} `xml:"server"`
+	Searching struct {
+		Modes []struct {
+			XMLName         xml.Name
+			Available       string `xml:"available,attr"`
+			SupportedParams string `xml:"supportedParams,attr"`
+		} `xml:",any"`
+	} `xml:"searching"`
+	Categories []torznabCategoryXML `xml:"categories>category"`
+}
+
+type torznabCategoryXML struct {
+	ID      int                  `xml:"id,attr"`
+	Name    string               `xml:"name,attr"`
+	Subcats []torznabCategoryXML `xml:"subcat"`
+}
+
+func (c torznabCategoryXML) category() TorznabCategory {
+	cat := TorznabCategory{ID: c.ID, Name: c.Name}
+	for _, sub := range c.Subcats {
+		cat.Subcats = append(cat.Subcats, sub.category())
+	}
+	return cat
+}
+
+func (d torznabCapsDoc) caps() *TorznabCaps {
+	caps := &TorznabCaps{
+		Server:    d.Server.Title,
+		Searching: make(map[string]TorznabSearch),
+	}
+	for _, mode := range d.Searching.Modes {
+		var params []string
+		for _, p := range strings.Split(mode.SupportedParams, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				params = append(params, p)
+			}
+		}
+		caps.Searching[mode.XMLName.Local] = TorznabSearch{
+			Available:       mode.Available == "yes",
+			SupportedParams: params,
+		}
+	}
+	for _, c := range d.Categories {
+		caps.Categories = append(caps.Categories, c.category())
+	}
+	return caps
+}
+
+// torznabFeed is the RSS response of t=search
+type torznabFeed struct {
+	Items []torznabItem `xml:"channel>item"`
+}
+
+type torznabItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	Link      string `xml:"link"`
+	Comments  string `xml:"comments"`
+	PubDate   string `xml:"pubDate"`
+	Category  string `xml:"category"`
+	Size      int64  `xml:"size"`
+	Enclosure struct {
+		URL    string `xml:"url,attr"`
+		Length int64  `xml:"length,attr"`
+	} `xml:"enclosure"`
+	// torznab:attr or newznab:attr, matched in any namespace
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+// attr returns the value of a torznab:attr element
+func (item torznabItem) attr(name string) string {
+	for _, a := range item.Attrs {
+		if strings.EqualFold(a.Name, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// torrent converts an RSS item into a search result. Items without any
+// usable download link are skipped.
+func (item torznabItem) torrent(source string) (Torrent, bool) {
+	t := Torrent{
+		Name:     strings.TrimSpace(item.Title),
+		Source:   source,
+		InfoHash: NormalizeInfohash(item.attr("infohash")),
+	}
+
+	// Download link: magnet attr, then infohash, then the .torrent URL
+	switch {
+	case strings.HasPrefix(item.attr("magneturl"), "magnet:"):
+		t.Magnet = item.attr("magneturl")
+	case t.InfoHash != "":
+		t.Magnet = "magnet:?xt=urn:btih:" + t.InfoHash + "&dn=" + url.QueryEscape(t.Name)
+	case strings.HasPrefix(item.Link, "magnet:") || strings.HasPrefix(item.Link, "http"):
+		t.Magnet = item.Link
+	case item.Enclosure.URL != "":
+		t.Magnet = item.Enclosure.URL
+	default:
+		return t, false
+	}
+
+	if strings.HasPrefix(item.Comments, "http") {
+		t.InfoURL = item.Comments
+	} else if strings.HasPrefix(item.GUID, "http") {
+		t.InfoURL = item.GUID
+	}
+
+	size := item.Size
+	if n, err := strconv.ParseInt(item.attr("size"), 10, 64); err == nil {
+		size = n
+	}
+	if size <= 0 {
+		size = item.Enclosure.Length
+	}
+	if size > 0 {
+		t.Size = formatBytes(size)
+		t.SizeBytes = size
+	}
+
+	if pub := item.attr("usenetdate"); pub != "" {
+		t.PublishedAt = ParseDate(pub, time.Now())
+	}
+	if t.PublishedAt.IsZero() {
+		t.PublishedAt = ParseDate(item.PubDate, time.Now())
+	}
+	t.Category = strings.TrimSpace(item.Category)
+	if t.Category == "" {
+		t.Category = item.attr("category")
+	}
+	t.Uploader = item.attr("poster")
+	t.Extra = item.extra()
+
+	// peers counts seeders and leechers together
+	t.Seeders, _ = strconv.Atoi(item.attr("seeders"))
+	if peers, err := strconv.Atoi(item.attr("peers")); err == nil && peers >= t.Seeders {
+		t.Leechers = peers - t.Seeders
+	} else {
+		t.Leechers, _ = strconv.Atoi(item.attr("leechers"))
+	}
+	return t, true
+}
+
+// torznabKnownAttrs are the attributes torrent() maps to Torrent fields
+var torznabKnownAttrs = map[string]bool{
+	"infohash": true, "magneturl": true, "size": true, "seeders": true, "peers": true,
+	"leechers": true, "category": true, "poster": true, "usenetdate": true,
+}
+
+// extra collects the attributes without a Torrent field, e.g. imdb, grabs
+// or downloadvolumefactor. Repeated attributes keep their first value.
+func (item torznabItem) extra() map[string]string {
+	var extra map[string]string
+	for _, a := range item.Attrs {
+		name := strings.ToLower(a.Name)
+		if torznabKnownAttrs[name] || a.Value == "" {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]string)
+		}
+		if _, ok := extra[name]; !ok {
+			extra[name] = a.Value
+		}
+	}
+	return extra
+}
+
+// formatBytes formats a byte count the way sites display sizes ("1.4 GB")
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,217 @@
+// Package searches keeps the search history and the state of saved
+// searches, which are re-run in the background. Each run's results are
+// remembered so the next one can point out what is new.
+package searches
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+)
+
+// MaxHistory is how many past queries are kept
+const MaxHistory = 500
+
+// DefaultInterval is how often a saved search without an interval is re-run
+const DefaultInterval = time.Hour
+
+// MinInterval keeps saved searches from hammering the sources
+const MinInterval = 10 * time.Minute
+
+// Interval returns how often a saved search should be re-run
+func Interval(s config.SavedSearch) time.Duration {
+	if s.IntervalMinutes <= 0 {
+		return DefaultInterval
+	}
+	return max(time.Duration(s.IntervalMinutes)*time.Minute, MinInterval)
+}
+
+// Run is what a saved search found
+type Run struct {
+	At   time.Time `json:"at"`   // When it last ran
+	Seen []string  `json:"seen"` // Result keys of the last run
+	New  []string  `json:"new"`  // Keys new since an earlier run, not yet looked at
+}
+
+// Store is the search history and saved search state, kept in a JSON
+// file. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	History []string        `json:"history"` // Queries, oldest first
+	Saved   map[string]*Run `json:"saved"`   // Saved search name -> runs
+}
+
+// StorePath returns the path of the store file, next to the config file
+func StorePath() string {
+	return filepath.Join(filepath.Dir(config.ConfigPath()), "searches.json")
+}
+
+// Load reads the store file at path. A missing file gives an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, Saved: make(map[string]*Run)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, err
+	}
+	if s.Saved == nil {
+		s.Saved = make(map[string]*Run)
+	}
+	return s, nil
+}
+
+// Add records a query in the history. Running a query again moves it to
+// the end rather than repeating it.
+func (s *Store) Add(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.History = slices.DeleteFunc(s.History, func(q string) bool { return q == query })
+	s.History = append(s.History, query)
+	if n := len(s.History); n > MaxHistory {
+		s.History = slices.Delete(s.History, 0, n-MaxHistory)
+	}
+}
+
+// Queries returns the history, oldest first
+func (s *Store) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.History)
+}
+
+// Find returns the past queries fuzzily matching pattern, newest first
+func (s *Store) Find(pattern string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matches []string
+	for _, q := range slices.Backward(s.History) {
+		if Fuzzy(pattern, q) {
+			matches = append(matches, q)
+		}
+	}
+	return matches
+}
+
+// Fuzzy reports whether the characters of pattern appear in s in order,
+// ignoring case: "expns" matches "the expanse"
+func Fuzzy(pattern, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// Record stores the result keys of a saved search run and returns those
+// the previous run didn't have. The first run only sets the baseline. A
+// partial run, where some sources failed, also keeps the previous run's
+// keys, so those sources' results don't come back as new next time.
+func (s *Store) Record(name string, keys []string, partial bool, at time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.Saved[name]
+	if !ok {
+		s.Saved[name] = &Run{At: at, Seen: keys}
+		return nil
+	}
+	var fresh []string
+	for _, k := range keys {
+		if !slices.Contains(run.Seen, k) {
+			fresh = append(fresh, k)
+			if !slices.Contains(run.New, k) {
+				run.New = append(run.New, k)
+			}
+		}
+	}
+	if partial {
+		keys = append(slices.Clone(run.Seen), fresh...)
+	}
+	run.At, run.Seen = at, keys
+	return fresh
+}
+
+// LastRun returns when a saved search last ran, zero if never
+func (s *Store) LastRun(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run, ok := s.Saved[name]; ok {
+		return run.At
+	}
+	return time.Time{}
+}
+
+// NewCount returns how many new results of a saved search haven't been
+// looked at
+func (s *Store) NewCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run, ok := s.Saved[name]; ok {
+		return len(run.New)
+	}
+	return 0
+}
+
+// TotalNew returns how many new results of every saved search haven't
+// been looked at
+func (s *Store) TotalNew() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, run := range s.Saved {
+		n += len(run.New)
+	}
+	return n
+}
+
+// MarkViewed clears the new results of a saved search
+func (s *Store) MarkViewed(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run, ok := s.Saved[name]; ok {
+		run.New = nil
+	}
+}
+
+// Forget drops the runs of a saved search, e.g. when it is deleted
+func (s *Store) Forget(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Saved, name)
+}
+
+// Save writes the store back to its file
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if s.path == "" {
+		return nil
+	}
+	return config.WriteFileAtomic(s.path, data)
+}
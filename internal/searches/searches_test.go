@@ -0,0 +1,115 @@
+package searches
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+)
+
+func TestHistory(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "searches.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"ubuntu", "the expanse", " ", "debian", "ubuntu"} {
+		s.Add(q)
+	}
+	if got := s.Queries(); !slices.Equal(got, []string{"the expanse", "debian", "ubuntu"}) {
+		t.Errorf("Queries = %q", got)
+	}
+	if got := s.Find("exps"); !slices.Equal(got, []string{"the expanse"}) {
+		t.Errorf("Find(exps) = %q", got)
+	}
+	if got := s.Find("n"); !slices.Equal(got, []string{"ubuntu", "debian", "the expanse"}) {
+		t.Errorf("Find(n) = %q, want newest first", got)
+	}
+
+	for i := range MaxHistory + 10 {
+		s.Add(time.Duration(i).String())
+	}
+	if got := s.Queries(); len(got) != MaxHistory || got[len(got)-1] != time.Duration(MaxHistory+9).String() {
+		t.Errorf("history not capped: %d entries", len(got))
+	}
+}
+
+func TestFuzzy(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"EXP", "the expanse", true},
+		{"tex", "the expanse", true},
+		{"xet", "the expanse", false},
+		{"é", "café", true},
+	}
+	for _, tt := range tests {
+		if got := Fuzzy(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("Fuzzy(%q, %q) = %v", tt.pattern, tt.s, got)
+		}
+	}
+}
+
+func TestRecordAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "searches.json")
+	s, _ := Load(path)
+	now := time.Now()
+
+	if fresh := s.Record("show", []string{"a", "b"}, false, now); fresh != nil {
+		t.Errorf("first run reported %q as new", fresh)
+	}
+	if fresh := s.Record("show", []string{"b", "c"}, false, now.Add(time.Hour)); !slices.Equal(fresh, []string{"c"}) {
+		t.Errorf("second run: new %q, want [c]", fresh)
+	}
+	// "a" is back after missing a run: new again
+	if fresh := s.Record("show", []string{"a", "c"}, false, now.Add(2*time.Hour)); !slices.Equal(fresh, []string{"a"}) {
+		t.Errorf("third run: new %q, want [a]", fresh)
+	}
+	// A source failing doesn't make its results new once it's back
+	s.Record("show", []string{"d"}, true, now.Add(3*time.Hour))
+	if fresh := s.Record("show", []string{"a", "c", "d"}, false, now.Add(4*time.Hour)); fresh != nil {
+		t.Errorf("after a partial run: new %q", fresh)
+	}
+	s.Add("show")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := loaded.NewCount("show"); n != 3 || loaded.TotalNew() != 3 {
+		t.Errorf("after reload: %d new, want 3", n)
+	}
+	if !loaded.LastRun("show").Equal(now.Add(4 * time.Hour)) {
+		t.Errorf("LastRun = %v", loaded.LastRun("show"))
+	}
+	if got := loaded.Queries(); !slices.Equal(got, []string{"show"}) {
+		t.Errorf("history after reload = %q", got)
+	}
+
+	loaded.MarkViewed("show")
+	if loaded.TotalNew() != 0 {
+		t.Error("MarkViewed kept new results")
+	}
+	loaded.Forget("show")
+	if !loaded.LastRun("show").IsZero() {
+		t.Error("Forget kept the runs")
+	}
+}
+
+func TestInterval(t *testing.T) {
+	if got := Interval(config.SavedSearch{}); got != DefaultInterval {
+		t.Errorf("default interval = %v", got)
+	}
+	if got := Interval(config.SavedSearch{IntervalMinutes: 1}); got != MinInterval {
+		t.Errorf("short interval = %v, want %v", got, MinInterval)
+	}
+	if got := Interval(config.SavedSearch{IntervalMinutes: 90}); got != 90*time.Minute {
+		t.Errorf("interval = %v", got)
+	}
+}
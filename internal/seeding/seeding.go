@@ -0,0 +1,213 @@
+// Package seeding evaluates share ratio and seeding time goals for torrents.
+// Goals qBittorrent can enforce itself are pushed to it as share limits;
+// the rest are checked by the app against every torrent list refresh.
+package seeding
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// Find returns the most specific rule for a torrent: one for its hash, then
+// one for its category, then a catch-all rule
+func Find(rules []config.SeedingRule, t qbit.TorrentInfo) (config.SeedingRule, bool) {
+	best, bestScore := config.SeedingRule{}, 0
+	for _, r := range rules {
+		score := 0
+		switch {
+		case r.Hash != "":
+			if strings.EqualFold(r.Hash, t.Hash) {
+				score = 3
+			}
+		case r.Category != "":
+			if r.Category == t.Category {
+				score = 2
+			}
+		default:
+			score = 1
+		}
+		if score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// Action returns what the rule does once its goal is reached
+func Action(r config.SeedingRule) string {
+	switch r.Action {
+	case config.SeedingActionRemove, config.SeedingActionRemoveFiles:
+		return r.Action
+	}
+	return config.SeedingActionStop
+}
+
+// HasGoal reports whether the rule sets a ratio or seeding time goal
+func HasGoal(r config.SeedingRule) bool {
+	return r.Ratio > 0 || r.SeedingDays > 0
+}
+
+// Native reports whether qBittorrent can enforce the rule with
+// /torrents/setShareLimits. It stops torrents when either limit is hit and
+// knows nothing about Plex, so only plain "stop at either goal" rules qualify.
+// They are still enforced by the app for torrents on a qBittorrent that
+// can't be told to stop them (see NativeFor).
+func Native(r config.SeedingRule) bool {
+	return HasGoal(r) && Action(r) == config.SeedingActionStop && !r.NotInPlex &&
+		(!r.RequireAll || r.Ratio <= 0 || r.SeedingDays <= 0)
+}
+
+// NativeFor reports whether qBittorrent enforces the rule for a torrent.
+// Before 5.1 it can't be told what to do at the limits and uses its global
+// share limit action, which may be to remove the torrent, so the app
+// stops those torrents itself.
+func NativeFor(r config.SeedingRule, t qbit.TorrentInfo) bool {
+	return Native(r) && t.LimitAction != ""
+}
+
+// ShareLimits returns the ratio and seeding time (minutes) limits for a
+// native rule, using qbit.ShareLimitNone for goals it doesn't set
+func ShareLimits(r config.SeedingRule) (float64, int64) {
+	ratio, minutes := float64(qbit.ShareLimitNone), int64(qbit.ShareLimitNone)
+	if r.Ratio > 0 {
+		ratio = r.Ratio
+	}
+	if r.SeedingDays > 0 {
+		minutes = int64(r.SeedingDays * 24 * 60)
+	}
+	return ratio, minutes
+}
+
+// SeedingTime returns how long a torrent has been seeding. Older
+// qBittorrent versions don't report it, so the time since completion is
+// used instead.
+func SeedingTime(t qbit.TorrentInfo, now time.Time) time.Duration {
+	if t.SeedingTime > 0 {
+		return time.Duration(t.SeedingTime) * time.Second
+	}
+	if t.CompletionOn > 0 && t.Progress >= 1 {
+		return max(now.Sub(time.Unix(t.CompletionOn, 0)), 0)
+	}
+	return 0
+}
+
+// Progress returns how far a torrent is toward the rule's goal, from 0 to 1.
+// With both goals set it follows the nearer goal, or the further one if
+// the rule requires both.
+func Progress(r config.SeedingRule, t qbit.TorrentInfo, now time.Time) float64 {
+	var parts []float64
+	if r.Ratio > 0 {
+		parts = append(parts, t.Ratio/r.Ratio)
+	}
+	if r.SeedingDays > 0 {
+		days := SeedingTime(t, now).Hours() / 24
+		parts = append(parts, days/r.SeedingDays)
+	}
+	if len(parts) == 0 {
+		return 0
+	}
+
+	p := parts[0]
+	for _, part := range parts[1:] {
+		if r.RequireAll {
+			p = min(p, part)
+		} else {
+			p = max(p, part)
+		}
+	}
+	return min(p, 1)
+}
+
+// Limit is a share limit to push to a group of torrents
+type Limit struct {
+	Hashes         []string
+	Ratio          float64
+	SeedingMinutes int64
+}
+
+// Decision is an action the app takes on a torrent that reached its goal
+type Decision struct {
+	Hash   string
+	Name   string
+	Action string // config.SeedingAction*
+}
+
+// Plan works out what to do for the torrents: share limits (which stop the
+// torrents) for native rules that qBittorrent doesn't have yet, and actions for app-enforced
+// rules whose goal has been reached. libraries are the Plex library paths
+// checked by NotInPlex rules.
+func Plan(rules []config.SeedingRule, torrents []qbit.TorrentInfo, libraries []string, now time.Time) ([]Limit, []Decision) {
+	type limitKey struct {
+		ratio   float64
+		minutes int64
+	}
+	groups := make(map[limitKey][]string)
+	var decisions []Decision
+
+	for _, t := range torrents {
+		r, ok := Find(rules, t)
+		if !ok || !HasGoal(r) {
+			continue
+		}
+
+		if NativeFor(r, t) {
+			ratio, minutes := ShareLimits(r)
+			if t.RatioLimit != ratio || t.SeedingLimit != minutes || t.LimitAction != qbit.LimitActionStop {
+				key := limitKey{ratio, minutes}
+				groups[key] = append(groups[key], t.Hash)
+			}
+			continue
+		}
+
+		if t.Progress < 1 || Progress(r, t, now) < 1 {
+			continue
+		}
+		if r.NotInPlex && InLibrary(t.ContentPath, libraries) {
+			continue
+		}
+		action := Action(r)
+		if action == config.SeedingActionStop && IsStopped(t) {
+			continue
+		}
+		decisions = append(decisions, Decision{Hash: t.Hash, Name: t.Name, Action: action})
+	}
+
+	limits := make([]Limit, 0, len(groups))
+	for key, hashes := range groups {
+		limits = append(limits, Limit{Hashes: hashes, Ratio: key.ratio, SeedingMinutes: key.minutes})
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		if limits[i].Ratio != limits[j].Ratio {
+			return limits[i].Ratio < limits[j].Ratio
+		}
+		return limits[i].SeedingMinutes < limits[j].SeedingMinutes
+	})
+	return limits, decisions
+}
+
+// IsStopped reports whether a torrent is paused (stopped in qBittorrent 5)
+func IsStopped(t qbit.TorrentInfo) bool {
+	return strings.HasPrefix(t.State, "paused") || strings.HasPrefix(t.State, "stopped")
+}
+
+// InLibrary reports whether path is inside one of the library directories
+func InLibrary(path string, libraries []string) bool {
+	if path == "" {
+		return false
+	}
+	for _, lib := range libraries {
+		if lib == "" {
+			continue
+		}
+		rel, err := filepath.Rel(lib, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
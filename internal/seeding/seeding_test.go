@@ -0,0 +1,140 @@
+package seeding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+func TestFindPrefersMostSpecificRule(t *testing.T) {
+	rules := []config.SeedingRule{
+		{Ratio: 1},
+		{Category: "tv", Ratio: 2},
+		{Hash: "ABC", Ratio: 3},
+	}
+	tests := []struct {
+		torrent qbit.TorrentInfo
+		want    float64
+	}{
+		{qbit.TorrentInfo{Hash: "abc", Category: "tv"}, 3},
+		{qbit.TorrentInfo{Hash: "def", Category: "tv"}, 2},
+		{qbit.TorrentInfo{Hash: "def", Category: "movies"}, 1},
+	}
+	for _, tt := range tests {
+		r, ok := Find(rules, tt.torrent)
+		if !ok || r.Ratio != tt.want {
+			t.Errorf("Find(%+v) = %+v, %v, want ratio %v", tt.torrent, r, ok, tt.want)
+		}
+	}
+
+	if _, ok := Find(rules[1:2], qbit.TorrentInfo{Category: "movies"}); ok {
+		t.Error("category rule matched another category")
+	}
+}
+
+func TestProgress(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	torrent := qbit.TorrentInfo{Ratio: 1, SeedingTime: 7 * 24 * 3600, Progress: 1}
+
+	tests := []struct {
+		rule config.SeedingRule
+		want float64
+	}{
+		{config.SeedingRule{Ratio: 2}, 0.5},
+		{config.SeedingRule{SeedingDays: 14}, 0.5},
+		{config.SeedingRule{Ratio: 4, SeedingDays: 14}, 0.5},                    // Either: nearer goal
+		{config.SeedingRule{Ratio: 4, SeedingDays: 14, RequireAll: true}, 0.25}, // All: further goal
+		{config.SeedingRule{Ratio: 0.5}, 1},                                     // Capped
+		{config.SeedingRule{}, 0},
+	}
+	for _, tt := range tests {
+		if got := Progress(tt.rule, torrent, now); got != tt.want {
+			t.Errorf("Progress(%+v) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+
+	// Without seeding_time, fall back to time since completion
+	old := qbit.TorrentInfo{Progress: 1, CompletionOn: now.Add(-48 * time.Hour).Unix()}
+	if got := Progress(config.SeedingRule{SeedingDays: 4}, old, now); got != 0.5 {
+		t.Errorf("completion fallback progress = %v, want 0.5", got)
+	}
+}
+
+func TestNative(t *testing.T) {
+	tests := []struct {
+		rule config.SeedingRule
+		want bool
+	}{
+		{config.SeedingRule{Ratio: 2, SeedingDays: 14}, true},
+		{config.SeedingRule{Ratio: 2, RequireAll: true}, true},
+		{config.SeedingRule{Ratio: 2, SeedingDays: 14, RequireAll: true}, false},
+		{config.SeedingRule{Ratio: 1, Action: config.SeedingActionRemove}, false},
+		{config.SeedingRule{Ratio: 1, NotInPlex: true}, false},
+		{config.SeedingRule{}, false},
+	}
+	for _, tt := range tests {
+		if got := Native(tt.rule); got != tt.want {
+			t.Errorf("Native(%+v) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Now()
+	rules := []config.SeedingRule{
+		{Category: "tv", Ratio: 2, SeedingDays: 14},
+		{Category: "movies", Ratio: 1, Action: config.SeedingActionRemove, NotInPlex: true},
+		{Category: "linux", Ratio: 1, SeedingDays: 1, RequireAll: true},
+	}
+	torrents := []qbit.TorrentInfo{
+		// Native rule, limits not set yet
+		{Hash: "tv1", Category: "tv", RatioLimit: qbit.ShareLimitGlobal, SeedingLimit: qbit.ShareLimitGlobal, LimitAction: "Default"},
+		// Native rule, limits already in place
+		{Hash: "tv2", Category: "tv", RatioLimit: 2, SeedingLimit: 14 * 24 * 60, LimitAction: qbit.LimitActionStop},
+		// Native rule, but qBittorrent can't be told to stop it: goal reached
+		{Hash: "tv3", Name: "Old", Category: "tv", Progress: 1, Ratio: 2.5, State: "uploading"},
+		// Goal reached, content outside Plex: remove
+		{Hash: "mov1", Name: "Movie", Category: "movies", Progress: 1, Ratio: 1.2, ContentPath: "/dl/Movie"},
+		// Goal reached but content lives in the library
+		{Hash: "mov2", Category: "movies", Progress: 1, Ratio: 1.2, ContentPath: "/plex/Movies/Movie"},
+		// Goal not reached
+		{Hash: "mov3", Category: "movies", Progress: 1, Ratio: 0.5, ContentPath: "/dl/Other"},
+		// Both goals reached, already stopped
+		{Hash: "iso1", Category: "linux", Progress: 1, Ratio: 3, SeedingTime: 2 * 86400, State: "pausedUP"},
+		// Both goals reached: stop
+		{Hash: "iso2", Name: "ISO", Category: "linux", Progress: 1, Ratio: 3, SeedingTime: 2 * 86400, State: "uploading"},
+	}
+
+	limits, decisions := Plan(rules, torrents, []string{"/plex/Movies", ""}, now)
+
+	if len(limits) != 1 || len(limits[0].Hashes) != 1 || limits[0].Hashes[0] != "tv1" ||
+		limits[0].Ratio != 2 || limits[0].SeedingMinutes != 14*24*60 {
+		t.Errorf("limits = %+v", limits)
+	}
+
+	want := []Decision{
+		{Hash: "tv3", Name: "Old", Action: config.SeedingActionStop},
+		{Hash: "mov1", Name: "Movie", Action: config.SeedingActionRemove},
+		{Hash: "iso2", Name: "ISO", Action: config.SeedingActionStop},
+	}
+	if len(decisions) != len(want) {
+		t.Fatalf("decisions = %+v, want %+v", decisions, want)
+	}
+	for i := range want {
+		if decisions[i] != want[i] {
+			t.Errorf("decision %d = %+v, want %+v", i, decisions[i], want[i])
+		}
+	}
+}
+
+func TestInLibrary(t *testing.T) {
+	libs := []string{"/plex/TV Shows"}
+	if !InLibrary("/plex/TV Shows/Show/Season 01", libs) {
+		t.Error("path inside library not detected")
+	}
+	if InLibrary("/plex/TV Shows Extra/x", libs) || InLibrary("/dl/x", libs) || InLibrary("", libs) {
+		t.Error("path outside library reported as inside")
+	}
+}
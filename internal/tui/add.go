@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// addInputKind classifies what the user typed into the Add prompt
+type addInputKind int
+
+const (
+	addInputInvalid addInputKind = iota
+	addInputMagnet
+	addInputURL
+	addInputFile
+)
+
+// classifyAddInput works out whether the input is a magnet link, a remote
+// .torrent URL or a local file path. Paths are returned with ~ expanded.
+func classifyAddInput(input string) (addInputKind, string) {
+	input = strings.TrimSpace(input)
+	lower := strings.ToLower(input)
+
+	switch {
+	case input == "":
+		return addInputInvalid, ""
+	case strings.HasPrefix(lower, "magnet:"):
+		return addInputMagnet, input
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://"):
+		return addInputURL, input
+	}
+
+	path := input
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return addInputFile, path
+}
+
+// openAddModal shows the Add prompt with an empty input
+func (m Model) openAddModal() (tea.Model, tea.Cmd) {
+	m.showAddModal = true
+	m.addPickFiles = false
+	m.addInput = textinput.New()
+	m.addInput.Placeholder = "magnet:?xt=..., https://.../file.torrent or ~/file.torrent"
+	m.addInput.CharLimit = 2048
+	m.addInput.Width = 66
+	m.addInput.Focus()
+	m.searchInput.Blur()
+	return m, textinput.Blink
+}
+
+// handleAddModalKey handles keyboard input for the Add prompt
+func (m Model) handleAddModalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showAddModal = false
+		m.addInput.Blur()
+		return m, handled()
+
+	case "ctrl+o":
+		m.addPickFiles = !m.addPickFiles
+		return m, handled()
+
+	case "tab":
+		// Only local paths can be completed
+		if kind, _ := classifyAddInput(m.addInput.Value()); kind == addInputFile {
+			completePath(&m.addInput)
+		}
+		return m, handled()
+
+	case "enter":
+		if m.cfg.VPN.Required && !m.vpnStatus.Connected {
+			m.statusMsg = "VPN required! Press V to connect"
+			return m, handled()
+		}
+		cmd := m.addFromInput(m.addInput.Value())
+		if cmd == nil {
+			return m, handled()
+		}
+		m.showAddModal = false
+		m.addInput.Blur()
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.addInput, cmd = m.addInput.Update(msg)
+	if cmd == nil {
+		cmd = handled()
+	}
+	return m, cmd
+}
+
+// addFromInput validates the Add prompt input and returns a command that
+// sends it to qBittorrent. Returns nil (with a status message) if invalid.
+func (m *Model) addFromInput(input string) tea.Cmd {
+	kind, value := classifyAddInput(input)
+	client := m.qbitClient
+	opts := qbit.AddTorrentOptions{SavePath: m.cfg.Downloads.Path}
+
+	switch kind {
+	case addInputMagnet, addInputURL:
+		name := value
+		if kind == addInputMagnet {
+			if dn := magnetDisplayName(value); dn != "" {
+				name = dn
+			}
+		}
+		if m.addPickFiles && !m.beginPick(name, &opts) {
+			return nil
+		}
+		return func() tea.Msg {
+			err := client.AddURLs(context.Background(), []string{value}, opts)
+			return torrentAddedMsg{name: name, infohash: ExtractInfohash(value), err: err}
+		}
+
+	case addInputFile:
+		info, err := os.Stat(value)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Not found: %s", TruncateString(value, 50))
+			return nil
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(value), ".torrent") {
+			m.statusMsg = "Expected a .torrent file"
+			return nil
+		}
+		if m.addPickFiles && !m.beginPick(filepath.Base(value), &opts) {
+			return nil
+		}
+		return func() tea.Msg {
+			err := client.AddTorrentFile(context.Background(), value, opts)
+			return torrentAddedMsg{name: filepath.Base(value), err: err}
+		}
+	}
+
+	m.statusMsg = "Enter a magnet link, .torrent URL or file path"
+	return nil
+}
+
+// magnetDisplayName returns the dn= parameter of a magnet link, if present
+func magnetDisplayName(magnet string) string {
+	idx := strings.Index(magnet, "?")
+	if idx == -1 {
+		return ""
+	}
+	for _, param := range strings.Split(magnet[idx+1:], "&") {
+		if value, ok := strings.CutPrefix(param, "dn="); ok {
+			if decoded, err := url.QueryUnescape(value); err == nil {
+				return decoded
+			}
+			return strings.ReplaceAll(value, "+", " ")
+		}
+	}
+	return ""
+}
+
+// renderAddModal renders the Add torrent prompt
+func (m Model) renderAddModal() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(80)
+
+	var content strings.Builder
+	content.WriteString(styles.Title.Render("Add Torrent"))
+	content.WriteString("\n\n")
+	content.WriteString(styles.Muted.Render("  Magnet link, http(s) .torrent URL, or local .torrent path"))
+	content.WriteString("\n\n  ")
+	content.WriteString(m.addInput.View())
+	content.WriteString("\n\n")
+
+	var kindLabel string
+	switch kind, _ := classifyAddInput(m.addInput.Value()); kind {
+	case addInputMagnet:
+		kindLabel = "Magnet link"
+	case addInputURL:
+		kindLabel = "Remote .torrent URL"
+	case addInputFile:
+		kindLabel = "Local file"
+	}
+	if kindLabel != "" {
+		content.WriteString(fmt.Sprintf("  Type:        %s\n", styles.VPNConnected.Render(kindLabel)))
+	}
+	content.WriteString(fmt.Sprintf("  Save to:     %s\n",
+		styles.Muted.Render(TruncateString(m.cfg.Downloads.Path, 58))))
+	content.WriteString(fmt.Sprintf("  Pick files:  %s\n\n", styles.Muted.Render(yesNo(m.addPickFiles))))
+
+	content.WriteString(styles.Muted.Render("  [tab]Complete path [ctrl+o]Pick files [enter]Add [esc]Cancel"))
+
+	return modalStyle.Render(content.String())
+}
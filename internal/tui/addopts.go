@@ -0,0 +1,379 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// Fields of the add-with-options modal, in display order
+const (
+	addOptSavePath = iota
+	addOptCategory
+	addOptTags
+	addOptRename
+	addOptContentLayout
+	addOptPaused
+	addOptSkipChecking
+	addOptSequential
+	addOptFirstLast
+	addOptPickFiles
+	addOptDLLimit
+	addOptULLimit
+	addOptRatioLimit
+	addOptSeedingTime
+	addOptFieldCount
+)
+
+// addOptLabels are the display labels for each add option field
+var addOptLabels = [addOptFieldCount]string{
+	"Save Path",
+	"Category",
+	"Tags (comma sep)",
+	"Rename",
+	"Content Layout",
+	"Start Paused",
+	"Skip Checking",
+	"Sequential",
+	"First/Last Piece",
+	"Pick Files",
+	"DL Limit (KiB/s)",
+	"UL Limit (KiB/s)",
+	"Ratio Limit",
+	"Seed Time (min)",
+}
+
+// contentLayouts are cycled through with space on the Content Layout field
+var contentLayouts = []string{"", qbit.ContentLayoutOriginal, qbit.ContentLayoutSubfolder, qbit.ContentLayoutNoSubfolder}
+
+// isAddOptToggle returns true for fields that are toggled rather than typed
+func isAddOptToggle(field int) bool {
+	switch field {
+	case addOptContentLayout, addOptPaused, addOptSkipChecking, addOptSequential, addOptFirstLast, addOptPickFiles:
+		return true
+	}
+	return false
+}
+
+// yesNo formats a bool the way the settings modal does
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// isYes parses a yes/no settings value
+func isYes(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s == "yes" || s == "true" || s == "1"
+}
+
+// sourceDefaults returns the add defaults configured for a source name
+func (m Model) sourceDefaults(name string) config.AddDefaults {
+	for _, src := range m.sources {
+		if src.Name == name {
+			return src.Defaults
+		}
+	}
+	return config.AddDefaults{}
+}
+
+// openAddOptionsModal opens the add-with-options modal for the selected
+// search result, prefilled from its source's configured defaults
+func (m Model) openAddOptionsModal() (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.results) {
+		return m, handled()
+	}
+	t := m.results[m.cursor]
+	d := m.sourceDefaults(t.Source)
+
+	savePath := d.SavePath
+	if savePath == "" {
+		savePath = m.cfg.Downloads.Path
+	}
+
+	values := [addOptFieldCount]string{
+		addOptSavePath:      savePath,
+		addOptCategory:      d.Category,
+		addOptTags:          strings.Join(d.Tags, ", "),
+		addOptContentLayout: d.ContentLayout,
+		addOptPaused:        yesNo(d.Paused),
+		addOptSkipChecking:  yesNo(d.SkipChecking),
+		addOptSequential:    yesNo(d.Sequential),
+		addOptFirstLast:     yesNo(d.FirstLastPiecePrio),
+		addOptPickFiles:     yesNo(false), // Per add, never saved as a default
+	}
+	if d.DownloadLimitKiB > 0 {
+		values[addOptDLLimit] = strconv.FormatInt(d.DownloadLimitKiB, 10)
+	}
+	if d.UploadLimitKiB > 0 {
+		values[addOptULLimit] = strconv.FormatInt(d.UploadLimitKiB, 10)
+	}
+	if d.RatioLimit != 0 {
+		values[addOptRatioLimit] = strconv.FormatFloat(d.RatioLimit, 'f', -1, 64)
+	}
+	if d.SeedingTimeLimit != 0 {
+		values[addOptSeedingTime] = strconv.Itoa(d.SeedingTimeLimit)
+	}
+
+	m.addOptsInputs = make([]textinput.Model, addOptFieldCount)
+	for i := range m.addOptsInputs {
+		m.addOptsInputs[i] = textinput.New()
+		m.addOptsInputs[i].CharLimit = 256
+		m.addOptsInputs[i].Width = 50
+		m.addOptsInputs[i].SetValue(values[i])
+	}
+	m.addOptsInputs[addOptRename].Placeholder = t.Name
+
+	m.showAddOptions = true
+	m.addOptsField = 0
+	m.addOptsEditing = false
+	m.addOptsTorrent = t
+	m.searchInput.Blur()
+	return m, handled()
+}
+
+// addOptsDefaults converts the modal fields back into config defaults
+func (m Model) addOptsDefaults() (config.AddDefaults, error) {
+	v := func(field int) string {
+		return strings.TrimSpace(m.addOptsInputs[field].Value())
+	}
+
+	d := config.AddDefaults{
+		Category:           v(addOptCategory),
+		Paused:             isYes(v(addOptPaused)),
+		SkipChecking:       isYes(v(addOptSkipChecking)),
+		Sequential:         isYes(v(addOptSequential)),
+		FirstLastPiecePrio: isYes(v(addOptFirstLast)),
+		ContentLayout:      v(addOptContentLayout),
+	}
+	if v(addOptSavePath) != m.cfg.Downloads.Path {
+		d.SavePath = v(addOptSavePath)
+	}
+	for _, tag := range strings.Split(v(addOptTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			d.Tags = append(d.Tags, tag)
+		}
+	}
+
+	var err error
+	if s := v(addOptDLLimit); s != "" {
+		if d.DownloadLimitKiB, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return d, fmt.Errorf("invalid download limit %q", s)
+		}
+	}
+	if s := v(addOptULLimit); s != "" {
+		if d.UploadLimitKiB, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return d, fmt.Errorf("invalid upload limit %q", s)
+		}
+	}
+	if s := v(addOptRatioLimit); s != "" {
+		if d.RatioLimit, err = strconv.ParseFloat(s, 64); err != nil {
+			return d, fmt.Errorf("invalid ratio limit %q", s)
+		}
+	}
+	if s := v(addOptSeedingTime); s != "" {
+		if d.SeedingTimeLimit, err = strconv.Atoi(s); err != nil {
+			return d, fmt.Errorf("invalid seeding time %q", s)
+		}
+	}
+	return d, nil
+}
+
+// addOptionsFromDefaults builds qBittorrent add options from source defaults
+func addOptionsFromDefaults(d config.AddDefaults, fallbackSavePath string) qbit.AddTorrentOptions {
+	opts := qbit.AddTorrentOptions{
+		SavePath:           d.SavePath,
+		Category:           d.Category,
+		Tags:               d.Tags,
+		Paused:             d.Paused,
+		SkipChecking:       d.SkipChecking,
+		SequentialDownload: d.Sequential,
+		FirstLastPiecePrio: d.FirstLastPiecePrio,
+		ContentLayout:      d.ContentLayout,
+		DlLimit:            d.DownloadLimitKiB * 1024,
+		UpLimit:            d.UploadLimitKiB * 1024,
+		RatioLimit:         d.RatioLimit,
+		SeedingTimeLimit:   d.SeedingTimeLimit,
+	}
+	if opts.SavePath == "" {
+		opts.SavePath = fallbackSavePath
+	}
+	return opts
+}
+
+// handleAddOptionsKey handles keyboard input for the add-with-options modal
+func (m Model) handleAddOptionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// If editing a field, handle text input
+	if m.addOptsEditing {
+		switch key {
+		case "esc", "enter":
+			m.addOptsEditing = false
+			m.addOptsInputs[m.addOptsField].Blur()
+			return m, handled()
+		case "tab":
+			if m.addOptsField == addOptSavePath {
+				completePath(&m.addOptsInputs[addOptSavePath])
+			}
+			return m, handled()
+		}
+		var cmd tea.Cmd
+		m.addOptsInputs[m.addOptsField], cmd = m.addOptsInputs[m.addOptsField].Update(msg)
+		if cmd == nil {
+			cmd = handled()
+		}
+		return m, cmd
+	}
+
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showAddOptions = false
+		return m, handled()
+
+	case "up", "k":
+		if m.addOptsField > 0 {
+			m.addOptsField--
+		}
+		return m, handled()
+
+	case "down", "j":
+		if m.addOptsField < addOptFieldCount-1 {
+			m.addOptsField++
+		}
+		return m, handled()
+
+	case "i", " ", "space":
+		field := m.addOptsField
+		if !isAddOptToggle(field) {
+			m.addOptsEditing = true
+			m.addOptsInputs[field].Focus()
+			return m, textinput.Blink
+		}
+		if field == addOptContentLayout {
+			cur := m.addOptsInputs[field].Value()
+			next := 0
+			for i, layout := range contentLayouts {
+				if layout == cur {
+					next = (i + 1) % len(contentLayouts)
+				}
+			}
+			m.addOptsInputs[field].SetValue(contentLayouts[next])
+		} else {
+			m.addOptsInputs[field].SetValue(yesNo(!isYes(m.addOptsInputs[field].Value())))
+		}
+		return m, handled()
+
+	case "D":
+		// Save the current values as this source's defaults
+		d, err := m.addOptsDefaults()
+		if err != nil {
+			m.statusMsg = err.Error()
+			return m, handled()
+		}
+		for i := range m.sources {
+			if m.sources[i].Name == m.addOptsTorrent.Source {
+				m.sources[i].Defaults = d
+				m.saveSources()
+				m.statusMsg = fmt.Sprintf("Saved add defaults for %s", TruncateString(m.addOptsTorrent.Source, 30))
+				return m, handled()
+			}
+		}
+		m.statusMsg = "Source not found - defaults not saved"
+		return m, handled()
+
+	case "enter":
+		d, err := m.addOptsDefaults()
+		if err != nil {
+			m.statusMsg = err.Error()
+			return m, handled()
+		}
+		opts := addOptionsFromDefaults(d, m.cfg.Downloads.Path)
+		opts.Rename = strings.TrimSpace(m.addOptsInputs[addOptRename].Value())
+		// The result the modal was opened for, even if the results changed since
+		t := m.addOptsTorrent
+		if isYes(m.addOptsInputs[addOptPickFiles].Value()) {
+			if !m.beginPick(t.Name, &opts) {
+				return m, handled()
+			}
+		}
+		m.showAddOptions = false
+		return m, m.downloadResult(t, opts)
+	}
+
+	return m, handled()
+}
+
+// renderAddOptionsModal renders the add-with-options modal
+func (m Model) renderAddOptionsModal() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(80)
+
+	var content strings.Builder
+	content.WriteString(styles.Title.Render("Add with Options"))
+	content.WriteString("\n\n")
+	if m.cursor < len(m.results) {
+		content.WriteString(styles.Muted.Render("  " + TruncateString(m.results[m.cursor].Name, 70)))
+		content.WriteString("\n\n")
+	}
+
+	for field := 0; field < addOptFieldCount; field++ {
+		label := addOptLabels[field]
+		isSelected := field == m.addOptsField
+		isEditing := isSelected && m.addOptsEditing
+
+		var labelStr string
+		if isSelected {
+			labelStr = styles.Title.Render(PadRight("› "+label+":", 20))
+		} else {
+			labelStr = styles.Muted.Render(PadRight("  "+label+":", 20))
+		}
+
+		var valueStr string
+		if isEditing {
+			valueStr = m.addOptsInputs[field].View()
+		} else {
+			val := m.addOptsInputs[field].Value()
+			if val == "" {
+				val = "(default)"
+			}
+			if isSelected {
+				selectedStyle := lipgloss.NewStyle().
+					Foreground(lipgloss.Color(theme.CurrentPalette.Accent)).
+					Bold(true)
+				valueStr = selectedStyle.Render(TruncateString(val, 52))
+			} else {
+				valueStr = styles.Muted.Render(TruncateString(val, 52))
+			}
+		}
+
+		content.WriteString(labelStr + " " + valueStr + "\n")
+	}
+
+	content.WriteString("\n")
+	if m.addOptsEditing {
+		content.WriteString(styles.Muted.Render("  [esc/enter] Done editing"))
+	} else {
+		content.WriteString(styles.Muted.Render("  [↑↓]Field [space]Edit [D]Save defaults [enter]Add [esc]Cancel"))
+	}
+
+	return modalStyle.Render(content.String())
+}
@@ -6,9 +6,11 @@ package tui
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -18,9 +20,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/feeds"
+	"github.com/litescript/ls-torrent-tui/internal/pipeline"
 	"github.com/litescript/ls-torrent-tui/internal/plex"
 	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/quality"
+	"github.com/litescript/ls-torrent-tui/internal/release"
 	"github.com/litescript/ls-torrent-tui/internal/scraper"
+	"github.com/litescript/ls-torrent-tui/internal/searches"
 	"github.com/litescript/ls-torrent-tui/internal/theme"
 	"github.com/litescript/ls-torrent-tui/internal/version"
 	"github.com/litescript/ls-torrent-tui/internal/vpn"
@@ -44,16 +51,28 @@ const (
 	tabDownloads
 	tabCompleted
 	tabSources
+	tabFeeds
 )
 
 // SearchSource represents a configured torrent search site
 type SearchSource struct {
 	Name    string
 	URL     string
+	Type    string // config.SourceType*
 	Enabled bool
 	Scraper scraper.Scraper
 	Builtin bool   // true for built-in sources, false for user-added
 	Warning string // non-empty if source has issues (e.g., "search may not work")
+
+	// Torznab settings
+	APIKey     string
+	Categories []int
+
+	Defaults config.AddDefaults // Prefilled values for the add-with-options modal
+
+	// Scraping profile of a generic source (nil = heuristics)
+	Profile    *config.ScrapeProfile
+	ProfileErr error // Why the profile can't be used (not saved)
 }
 
 // Model is the main application state
@@ -66,50 +85,107 @@ type Model struct {
 	spinner     spinner.Model
 
 	// State
-	mode          viewMode
-	activeTab     tabType
-	results       []scraper.Torrent
-	cursor        int
-	dlCursor      int // cursor for downloads tab
-	searching     bool
-	err           error
-	statusMsg     string
-	vpnStatus     vpn.Status
-	vpnChecked    bool // Have we done initial VPN check?
-	vpnConnecting bool // Are we currently connecting to VPN?
+	mode           viewMode
+	activeTab      tabType
+	results        []scraper.Torrent
+	cursor         int
+	dlCursor       int // cursor for downloads tab
+	searching      bool
+	err            error
+	statusMsg      string
+	vpnStatus      vpn.Status
+	vpnChecked     bool // Have we done initial VPN check?
+	vpnConnecting  bool // Are we currently connecting to VPN?
 	qbitOnline     bool
 	isFetching     bool      // Guard against overlapping torrent fetches
 	fetchStartedAt time.Time // When current fetch started (for stale detection)
 
-	// Torrent lists from qBittorrent
+	// Torrent lists from qBittorrent (downloading/completed are filtered views)
 	downloading []qbit.TorrentInfo
 	completed   []qbit.TorrentInfo
-
-	// Sorting (downloads tab): 0=name, 1=size, 2=done, 3=dl, 4=ul, 5=seed, 6=leech, 7=eta
+	dlAll       []qbit.TorrentInfo       // Unfiltered downloads
+	compAll     []qbit.TorrentInfo       // Unfiltered completed
+	categories  map[string]qbit.Category // Known categories by name
+	tags        []string                 // Known tags, sorted
+	serverState qbit.ServerState         // Global transfer state from /sync/maindata
+	filter      torrentFilter            // Sidebar category/tag filter
+	showSidebar bool                     // Show the filter sidebar in Downloads/Completed
+
+	// Multi-select (Downloads/Completed)
+	marked       map[string]bool // Marked torrents by hash
+	visualAnchor string          // Hash where range selection started ("" = off)
+
+	// Torrent detail pane (Downloads/Completed)
+	detailHash       string         // Torrent shown in the pane ("" = closed)
+	detailSection    int            // detailGeneral, detailFiles, ...
+	detailScroll     int            // First visible line of the section
+	detailFileCursor int            // Selected row in the Files section
+	detail           torrentDetail  // Latest fetched details
+	detailErr        error          // Last fetch error
+	detailPeers      *qbit.PeerSync // Incremental peer list for detailHash
+	detailFetching   bool           // Guard against overlapping detail fetches
+
+	// Pick files before download starts
+	pick filePick
+
+	// Sorting (downloads tab): 0=name, 1=size, 2=done, 3=dl, 4=ul, 5=seed, 6=leech, 7=eta, 8=queue
 	dlSortCol     int
 	dlSortAsc     bool
 	followingHash string // Hash of torrent to follow (keeps cursor on it during re-sorts)
+	cursorHash    string // Put the cursor on this torrent after the next refresh
 
 	// Sorting (completed tab): 0=name, 1=size, 2=ratio, 3=uploaded
 	compSortCol int
 	compSortAsc bool
 
-	// Search results sorting: 0=name, 1=size, 2=seeds, 3=leech, 4=health
+	// Search results sorting: 0=name, 1=size, 2=seeds, 3=leech, 4=health, 5=age, 6=score
 	searchSortCol int
 	searchSortAsc bool
 
+	// Quality profile results are scored by (index into cfg.Quality, -1 = none)
+	qualityIdx int
+
+	// Running search: results stream in one source at a time
+	searchID       int                    // Increments per search; stale messages are dropped
+	searchSources  []string               // Sources being searched, in display order
+	searchStatuses []scraper.SourceStatus // Sources that have reported so far
+	searchFresh    bool                   // No results yet for this search
+
+	// Show each source's listing under the selected (merged) result
+	resultsExpanded bool
+
+	// Every result of the search; results holds those passing the filters
+	searchAll []scraper.Torrent
+
+	// Results view filters, kept for the session
+	searchFilters []resultFilter
+	filterFocus   bool // Is the filter bar focused?
+	filterCursor  int
+
 	// Track which results have been sent to download (by name, since indices change with sort)
 	downloaded map[string]bool
 
 	// Search sources
 	sources        []SearchSource
 	srcCursor      int
-	addingURL      bool // Are we adding a URL?
-	validatingURL  bool // Are we validating a URL?
-	validationDot  int  // Animation state for validation dots (0-2)
+	addingURL      bool   // Are we adding a URL?
+	addingType     string // Source type being added (config.SourceType*)
+	validatingURL  bool   // Are we validating a URL?
+	validationDot  int    // Animation state for validation dots (0-2)
 	urlInput       textinput.Model
 	confirmingQuit bool // Are we showing the quit confirmation modal?
 
+	// Delete confirmation, for deletes of several torrents or with files
+	confirmingDelete bool               // Are we asking before deleting?
+	deleteFiles      bool               // Delete the files too
+	deleteTargets    []qbit.TorrentInfo // Torrents to delete, as targeted when asked
+
+	// Scraping profile test (Sources tab)
+	testingProfile  bool            // Are we prompting for a query or file?
+	profileInput    textinput.Model // Query or saved HTML page
+	showProfileTest bool            // Are we showing the parsed rows?
+	profileTest     profileTestMsg  // Last test result
+
 	// Settings modal state
 	showSettings    bool              // Are we showing the settings modal?
 	settingsSection int               // 0=qBit, 1=Downloads, 2=VPN, 3=Plex
@@ -117,32 +193,103 @@ type Model struct {
 	settingsEditing bool              // Are we editing a field?
 	settingsInputs  []textinput.Model // Text inputs for settings fields
 
+	// Add torrent prompt state
+	showAddModal bool            // Are we showing the add prompt?
+	addInput     textinput.Model // Magnet, .torrent URL or local path
+	addPickFiles bool            // Choose files before the download starts
+
+	// Add-with-options modal state (search results)
+	showAddOptions bool              // Are we showing the add options modal?
+	addOptsField   int               // Selected field (addOpt* constant)
+	addOptsEditing bool              // Is a text field being edited?
+	addOptsInputs  []textinput.Model // One input per field, bools hold yes/no
+	addOptsTorrent scraper.Torrent   // Search result being added, its source gives the defaults
+
+	// Category/tag picker state
+	showLabelPicker bool            // Are we showing the category/tag picker?
+	labelMode       labelPickerMode // Picking a category or toggling tags
+	labelItems      []string        // Category or tag names ("" = no category)
+	labelCursor     int             // Selected item
+	labelChecked    map[string]bool // Tags currently on the target (tag mode)
+	labelHashes     []string        // Torrents being labelled
+	labelTarget     string          // Display name of the target
+	labelCreating   bool            // Is a new category/tag being named?
+	labelInput      textinput.Model // Name of the new category/tag
+
+	// Speed limit modal state
+	showLimits   bool              // Are we showing the speed limit modal?
+	limitsGlobal bool              // Global limits rather than per torrent
+	limitsHashes []string          // Torrents being limited
+	limitsTarget string            // Display name of the target
+	limitsInputs []textinput.Model // Download and upload limit (KiB/s)
+	limitsField  int               // Focused input
+
+	// Seeding rules: hashes already acted on this session
+	seedingActed map[string]bool
+	seedingBusy  bool // Is the engine acting on torrents?
+
+	// Post-completion pipeline
+	pipeline   *pipeline.State // Torrents moved or waiting for review
+	autoMoving bool            // Is a torrent being moved by the pipeline?
+
+	// Search history and saved searches
+	searchStore   *searches.Store      // History and saved search runs
+	histPos       int                  // History entry recalled with up/down (0 = none, 1 = newest)
+	histDraft     string               // What was typed before recalling history
+	histSearching bool                 // Reverse history search (ctrl+r)
+	histPattern   string               // Reverse history search pattern
+	histMatch     int                  // Reverse history search match (0 = newest)
+	lastQuery     string               // Last query searched, for saving it
+	showSaved     bool                 // Are we showing the saved search list?
+	savedCursor   int                  // Selected saved search
+	savedNaming   bool                 // Is the last search being named to save it?
+	savedInput    textinput.Model      // Name of the search being saved
+	savedRunning  map[string]bool      // Saved searches running in the background
+	savedTried    map[string]time.Time // When each saved search was last started
+
+	// Feeds tab
+	feedCursor     int                    // Selected row (feed or rule)
+	feedState      map[string]*feedStatus // Poll state by feed URL
+	feedHistory    *feeds.History         // Items and episodes already added
+	addingFeed     bool                   // Are we adding a feed URL?
+	feedInput      textinput.Model        // Feed URL
+	showRuleEditor bool                   // Are we showing the rule editor?
+	ruleInputs     []textinput.Model      // One input per field, bools hold yes/no
+	ruleField      int                    // Focused field (ruleField* constant)
+	ruleFeed       int                    // Feed the edited rule belongs to
+	ruleIndex      int                    // Rule being edited (-1 = new)
+
 	// Move to Plex modal state
-	showMoveModal   bool                 // Are we showing the move modal?
-	moveDetection   plex.DetectionResult // Auto-detected media info
-	moveMediaType   plex.MediaType       // Current selection (togglable)
-	moveSourcePath  string               // Full source path of selected torrent
-	moveDestPreview string               // Generated destination path preview
-	moveSubtitles   []string             // Found subtitle files
-	moveCleanup     bool                 // Whether to delete source after move
-	moveEditing     bool                 // Is user editing the title?
-	moveTitleInput  textinput.Model      // Editable title field
-	moveProgress    float64              // Transfer progress (0.0-1.0) - overall
-	moveInProgress  bool                 // Is a move operation running?
-	moveError       string               // Error message if move failed
-	moveTotalBytes  int64                // Total bytes to transfer
-	moveCopiedBytes int64                // Bytes copied so far
-	moveRate        string               // Transfer rate (e.g., "10.5MB/s")
-	moveETA         string               // Estimated time remaining (e.g., "0:01:23")
-	moveEpisodeCount   int               // Number of episodes (TV only, 0 for movies)
-	moveCurrentEpisode int               // Current episode being transferred (1-indexed)
-	moveCurrentFile    string            // Name of current file being transferred
-	moveEpisodeProgress float64          // Progress of current episode (0.0-1.0)
-	moveShimmerPos     int                  // Shimmer animation position (-1 = inactive)
-	moveComplete       bool                 // Move finished successfully
-	moveShowCleanup    bool                 // Showing cleanup confirmation?
-	moveRemainingFiles []string             // Leftover files after move
-	moveSourceDir      string               // Source directory for cleanup
+	showMoveModal       bool                 // Are we showing the move modal?
+	moveDetection       plex.DetectionResult // Auto-detected media info
+	moveMediaType       plex.MediaType       // Current selection (togglable)
+	moveSourcePath      string               // Full source path of selected torrent
+	moveHash            string               // Hash of the torrent being moved
+	moveName            string               // Name of the torrent being moved
+	moveDestPreview     string               // Generated destination path preview
+	moveSubtitles       []string             // Found subtitle files
+	moveCleanup         bool                 // Whether to delete source after move
+	moveRelocate        bool                 // Have qBittorrent relocate the data instead of copying it
+	moveFiles           []plex.TorrentFile   // qBittorrent's file list for the relocation preview (nil until listed)
+	moveEditing         bool                 // Is user editing the title?
+	moveTitleInput      textinput.Model      // Editable title field
+	moveProgress        float64              // Transfer progress (0.0-1.0) - overall
+	moveInProgress      bool                 // Is a move operation running?
+	moveError           string               // Error message if move failed
+	moveTotalBytes      int64                // Total bytes to transfer
+	moveCopiedBytes     int64                // Bytes copied so far
+	moveRate            string               // Transfer rate (e.g., "10.5MB/s")
+	moveETA             string               // Estimated time remaining (e.g., "0:01:23")
+	moveEpisodeCount    int                  // Number of episodes (TV only, 0 for movies)
+	moveCurrentEpisode  int                  // Current episode being transferred (1-indexed)
+	moveCurrentFile     string               // Name of current file being transferred
+	moveEpisodeProgress float64              // Progress of current episode (0.0-1.0)
+	moveShimmerPos      int                  // Shimmer animation position (-1 = inactive)
+	moveComplete        bool                 // Move finished successfully
+	moveShowCleanup     bool                 // Showing cleanup confirmation?
+	moveCleanupLinked   bool                 // Was the library linked to the source? Cleanup stops seeding then
+	moveRemainingFiles  []string             // Leftover files after move
+	moveSourceDir       string               // Source directory for cleanup
 
 	// Dimensions
 	width  int
@@ -150,13 +297,22 @@ type Model struct {
 
 	// Services
 	qbitClient *qbit.Client
+	qbitSync   *qbit.Sync // Incremental torrent store fed by /sync/maindata
 	vpnChecker *vpn.Checker
 }
 
 // Messages
-type searchResultMsg struct {
-	results []scraper.Torrent
-	err     error
+
+// searchSourceMsg delivers one source's results while a search runs
+type searchSourceMsg struct {
+	id     int
+	result scraper.SourceResult
+	next   <-chan scraper.SourceResult
+}
+
+// searchDoneMsg is sent once every source of a search has reported
+type searchDoneMsg struct {
+	id int
 }
 
 type vpnStatusMsg struct {
@@ -189,6 +345,8 @@ type vpnConnectMsg struct {
 type urlValidateMsg struct {
 	url     string
 	name    string
+	typ     string
+	apiKey  string
 	scraper scraper.Scraper
 	err     error
 	warning string
@@ -197,6 +355,9 @@ type urlValidateMsg struct {
 type torrentListMsg struct {
 	downloading []qbit.TorrentInfo
 	completed   []qbit.TorrentInfo
+	categories  map[string]qbit.Category
+	tags        []string
+	server      qbit.ServerState
 	err         error
 }
 
@@ -216,7 +377,7 @@ type plexMoveMsg struct {
 // NewModel creates the initial model
 func NewModel(cfg config.Config) Model {
 	ti := textinput.New()
-	ti.Placeholder = "Search torrents..."
+	ti.Placeholder = "Search torrents... (filters: size:>2GB seeds:>=10 -word res:1080p age:<7d)"
 	ti.Focus()
 	ti.CharLimit = 256
 	ti.Width = 50
@@ -231,6 +392,23 @@ func NewModel(cfg config.Config) Model {
 	urlIn.CharLimit = 512
 	urlIn.Width = 60
 
+	// URL input for adding feeds
+	feedIn := textinput.New()
+	feedIn.Placeholder = "RSS/Atom feed URL (Torznab RSS works too)..."
+	feedIn.CharLimit = 512
+	feedIn.Width = 60
+
+	// Query or file for testing a scraping profile
+	profileIn := textinput.New()
+	profileIn.Placeholder = "Search query, or path to a saved results page..."
+	profileIn.CharLimit = 512
+	profileIn.Width = 60
+
+	// A broken history file only means items may be offered again
+	feedHistory, _ := feeds.LoadHistory(feeds.HistoryPath())
+	searchStore, _ := searches.Load(searches.StorePath())
+	pipelineState, _ := pipeline.Load(pipeline.StatePath())
+
 	// Settings inputs (10 fields total)
 	// qBit: host, port, username, password (indices 0-3)
 	// Downloads: path (index 4)
@@ -268,13 +446,20 @@ func NewModel(cfg config.Config) Model {
 	// No built-in sources - users add their own via the Sources tab
 	var sources []SearchSource
 	for _, src := range cfg.Sources {
+		s, profileErr := newSourceScraper(src)
 		sources = append(sources, SearchSource{
-			Name:    src.Name,
-			URL:     src.URL,
-			Enabled: src.Enabled,
-			Scraper: scraper.NewGenericScraper(src.Name, src.URL),
-			Builtin: false,
-			Warning: src.Warning,
+			Name:       src.Name,
+			URL:        src.URL,
+			Type:       src.Type,
+			Enabled:    src.Enabled,
+			Scraper:    s,
+			Builtin:    false,
+			Warning:    src.Warning,
+			APIKey:     src.APIKey,
+			Categories: src.Categories,
+			Defaults:   src.Defaults,
+			Profile:    src.Profile,
+			ProfileErr: profileErr,
 		})
 	}
 
@@ -292,18 +477,29 @@ func NewModel(cfg config.Config) Model {
 		searchInput:    ti,
 		spinner:        sp,
 		urlInput:       urlIn,
+		feedInput:      feedIn,
+		profileInput:   profileIn,
+		feedState:      make(map[string]*feedStatus),
+		feedHistory:    feedHistory,
+		searchStore:    searchStore,
+		pipeline:       pipelineState,
+		savedRunning:   make(map[string]bool),
+		savedTried:     make(map[string]time.Time),
 		mode:           viewSearch,
 		sources:        sources,
 		qbitClient:     qbitClient,
+		qbitSync:       qbit.NewSync(qbitClient),
 		vpnChecker:     vpnChecker,
 		searchSortCol:  cfg.Sort.SearchCol,
 		searchSortAsc:  cfg.Sort.SearchAsc,
+		qualityIdx:     quality.Default(cfg.Quality),
 		dlSortCol:      cfg.Sort.DownloadsCol,
 		dlSortAsc:      cfg.Sort.DownloadsAsc,
 		compSortCol:    cfg.Sort.CompletedCol,
 		compSortAsc:    cfg.Sort.CompletedAsc,
 		downloaded:     make(map[string]bool),
 		settingsInputs: settingsInputs,
+		showSidebar:    true,
 	}
 }
 
@@ -355,24 +551,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case searchResultMsg:
-		m.searching = false
-		if msg.err != nil {
-			m.err = msg.err
-			m.statusMsg = fmt.Sprintf("Search failed: %v", msg.err)
-		} else if len(msg.results) == 0 {
-			m.statusMsg = "No results found"
-			m.results = nil
-		} else {
-			m.results = msg.results
-			// Apply current sort settings to new results
-			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc)
-			m.cursor = 0
-			m.mode = viewResults
-			m.statusMsg = fmt.Sprintf("Found %d results", len(m.results))
-			// Clear downloaded indicators for new search
-			m.downloaded = make(map[string]bool)
-		}
+	case searchSourceMsg:
+		cmds = append(cmds, m.handleSearchSource(msg))
+
+	case searchDoneMsg:
+		m.handleSearchDone(msg)
 
 	case vpnStatusMsg:
 		m.vpnStatus = msg.status
@@ -427,6 +610,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case torrentAddedMsg:
 		if msg.err != nil {
 			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+			// Nothing to pick files for
+			if m.pick.tag != "" && !m.pick.open {
+				m.pick = filePick{}
+			}
 		} else {
 			m.statusMsg = fmt.Sprintf("Added: %s", TruncateString(msg.name, 40))
 			// Mark as downloaded by both infohash AND name
@@ -457,10 +644,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sources = append(m.sources, SearchSource{
 				Name:    msg.name,
 				URL:     msg.url,
+				Type:    msg.typ,
 				Enabled: true,
 				Scraper: msg.scraper,
 				Builtin: false,
 				Warning: msg.warning,
+				APIKey:  msg.apiKey,
 			})
 			m.saveSources()
 			m.statusMsg = fmt.Sprintf("Added source: %s%s", msg.name, msg.warning)
@@ -502,24 +691,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Store source dir for potential cleanup
 			m.moveSourceDir = msg.result.SourceDir
 			m.moveRemainingFiles = msg.result.RemainingFiles
+			m.statusMsg = fmt.Sprintf("Moved to Plex: %s", TruncateString(msg.result.DestinationPath, 40))
+			m.recordManualMove(m.moveHash, m.moveName, msg.result.DestinationPath)
 
-			if m.moveCleanup && len(msg.result.RemainingFiles) > 0 {
+			// A linked library file leaves the torrent seeding from the
+			// source, so deleting it always asks first
+			linked := msg.result.Transfer.Linked()
+			if m.moveCleanup && (len(msg.result.RemainingFiles) > 0 || linked) {
 				// Show cleanup confirmation prompt
 				m.moveShowCleanup = true
-				m.moveError = fmt.Sprintf("✓ Moved to: %s", msg.result.DestinationPath)
-				m.statusMsg = fmt.Sprintf("Moved to Plex: %s", TruncateString(msg.result.DestinationPath, 40))
+				m.moveCleanupLinked = linked
+				m.moveError = fmt.Sprintf("✓ %s to: %s", msg.result.Transfer.Done(), msg.result.DestinationPath)
 			} else if m.moveCleanup && len(msg.result.RemainingFiles) == 0 {
 				// No remaining files - clean up the directory immediately
 				plex.CleanupSourceDir(msg.result.SourceDir)
 				m.moveError = fmt.Sprintf("✓ Moved and cleaned up: %s", msg.result.DestinationPath)
-				m.statusMsg = fmt.Sprintf("Moved to Plex: %s", TruncateString(msg.result.DestinationPath, 40))
 				m.moveComplete = true
 				m.moveShimmerPos = 0
 				return m, m.tickMoveShimmer()
 			} else {
 				// No cleanup requested - just show success
-				m.moveError = fmt.Sprintf("✓ Moved to: %s", msg.result.DestinationPath)
-				m.statusMsg = fmt.Sprintf("Moved to Plex: %s", TruncateString(msg.result.DestinationPath, 40))
+				m.moveError = fmt.Sprintf("✓ %s to: %s", msg.result.Transfer.Done(), msg.result.DestinationPath)
 				m.moveComplete = true
 				m.moveShimmerPos = 0
 				return m, m.tickMoveShimmer()
@@ -537,11 +729,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case torrentListMsg:
 		m.isFetching = false // Clear guard regardless of success/failure
 		if msg.err == nil {
-			m.downloading = msg.downloading
-			m.completed = msg.completed
-			// Apply current sort settings
-			sortTorrents(m.downloading, m.dlSortCol, m.dlSortAsc)
-			sortCompletedTorrents(m.completed, m.compSortCol, m.compSortAsc)
+			m.dlAll = msg.downloading
+			m.compAll = msg.completed
+			m.categories = msg.categories
+			m.tags = msg.tags
+			m.serverState = msg.server
+			// Apply the sidebar filter and current sort settings
+			m.applyTorrentFilter()
+			cmds = append(cmds, m.enforceSeeding(), m.runPipeline())
+			// Close the detail pane if its torrent was removed
+			if m.detailHash != "" {
+				if _, found := findTorrentInfo(m.allTorrents(), m.detailHash); !found {
+					m.closeDetail()
+				}
+			}
+			// Keep the cursor on a torrent that was just moved in the queue
+			if m.cursorHash != "" {
+				if idx, found := findTorrentByHash(m.visibleTorrents(), m.cursorHash); found {
+					m.dlCursor = idx
+				}
+				m.cursorHash = ""
+			}
 			// Update cursor to follow tracked torrent
 			if m.followingHash != "" {
 				if idx, found := findTorrentByHash(m.downloading, m.followingHash); found {
@@ -565,7 +773,79 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.fetchStartedAt = time.Now()
 			cmds = append(cmds, m.fetchTorrents())
 		}
-		cmds = append(cmds, tickCmd())
+		// Keep the detail pane live
+		if m.detailOpen() && !m.detailFetching {
+			m.detailFetching = true
+			cmds = append(cmds, m.fetchDetail())
+		}
+		cmds = append(cmds, m.pollPick(), m.pollDueFeeds(), m.runDueSearches(), tickCmd())
+
+	case profileTestMsg:
+		m.handleProfileTest(msg)
+
+	case savedSearchMsg:
+		m.handleSavedSearch(msg)
+
+	case autoMoveMsg:
+		cmds = append(cmds, m.handleAutoMove(msg))
+
+	case relocateMsg:
+		cmd := m.handleRelocate(msg)
+		return m, cmd
+
+	case relocateFilesMsg:
+		m.handleRelocateFiles(msg)
+
+	case feedAddedMsg:
+		m.handleFeedAdded(msg)
+
+	case feedPollMsg:
+		cmds = append(cmds, m.handleFeedPoll(msg))
+
+	case pickFilesMsg:
+		m.handlePickFiles(msg)
+
+	case torrentDetailMsg:
+		m.detailFetching = false
+		if msg.hash == m.detailHash {
+			if msg.err != nil {
+				m.detailErr = msg.err
+			} else {
+				m.detail = msg.detail
+				m.detailErr = nil
+			}
+		}
+
+	case altSpeedMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Alternative speed toggle failed: %v", msg.err)
+		} else if msg.enabled {
+			m.serverState.UseAltSpeedLimits = true
+			m.statusMsg = "Alternative speed limits on"
+		} else {
+			m.serverState.UseAltSpeedLimits = false
+			m.statusMsg = "Alternative speed limits off"
+		}
+
+	case seedingMsg:
+		m.seedingBusy = false
+		for _, key := range msg.acted {
+			m.seedingActed[key] = true
+		}
+		if s := msg.status(); s != "" {
+			m.statusMsg = s
+		}
+		if len(msg.done) > 0 {
+			cmds = append(cmds, m.fetchTorrents())
+		}
+
+	case bulkActionMsg:
+		m.statusMsg = msg.status()
+		for _, t := range msg.moved {
+			m.recordManualMove(t.hash, t.name, t.dest)
+		}
+		// Refresh torrent list after action
+		cmds = append(cmds, m.fetchTorrents())
 
 	case torrentActionMsg:
 		if msg.err != nil {
@@ -586,10 +866,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update text inputs (only when not in VPN connect mode)
 	if m.mode != viewVPNConnect {
-		if m.addingURL {
+		if m.showAddModal {
+			var cmd tea.Cmd
+			m.addInput, cmd = m.addInput.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showLimits {
+			var cmd tea.Cmd
+			m.limitsInputs[m.limitsField], cmd = m.limitsInputs[m.limitsField].Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showLabelPicker && m.labelCreating {
+			var cmd tea.Cmd
+			m.labelInput, cmd = m.labelInput.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showAddOptions && m.addOptsEditing {
+			var cmd tea.Cmd
+			m.addOptsInputs[m.addOptsField], cmd = m.addOptsInputs[m.addOptsField].Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showRuleEditor {
+			var cmd tea.Cmd
+			m.ruleInputs[m.ruleField], cmd = m.ruleInputs[m.ruleField].Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showSaved && m.savedNaming {
+			var cmd tea.Cmd
+			m.savedInput, cmd = m.savedInput.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.addingURL {
 			var cmd tea.Cmd
 			m.urlInput, cmd = m.urlInput.Update(msg)
 			cmds = append(cmds, cmd)
+		} else if m.addingFeed {
+			var cmd tea.Cmd
+			m.feedInput, cmd = m.feedInput.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.testingProfile {
+			var cmd tea.Cmd
+			m.profileInput, cmd = m.profileInput.Update(msg)
+			cmds = append(cmds, cmd)
 		} else {
 			var cmd tea.Cmd
 			m.searchInput, cmd = m.searchInput.Update(msg)
@@ -642,6 +954,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Handle delete confirmation
+	if m.confirmingDelete {
+		return m.handleDeleteConfirmKey(key)
+	}
+
 	// Handle move modal
 	if m.showMoveModal {
 		return m.handleMoveModalKey(key)
@@ -652,6 +969,62 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSettingsKey(key)
 	}
 
+	// Handle add torrent prompt
+	if m.showAddModal {
+		return m.handleAddModalKey(msg)
+	}
+
+	// Handle add with options modal
+	if m.showAddOptions {
+		return m.handleAddOptionsKey(msg)
+	}
+
+	// Handle category/tag picker
+	if m.showLabelPicker {
+		return m.handleLabelPickerKey(msg)
+	}
+
+	// Handle speed limit modal
+	if m.showLimits {
+		return m.handleLimitsKey(msg)
+	}
+
+	// Handle saved search list
+	if m.showSaved {
+		return m.handleSavedSearchesKey(msg)
+	}
+
+	// Handle pick-files modal
+	if m.pick.open {
+		return m.handleFilePickerKey(key)
+	}
+
+	// Handle feed rule editor
+	if m.showRuleEditor {
+		return m.handleRuleEditorKey(msg)
+	}
+
+	// When adding a feed in the feeds tab
+	if m.addingFeed && m.feedInput.Focused() {
+		return m.handleAddFeedKey(msg)
+	}
+
+	// Profile test results modal
+	if m.showProfileTest {
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc", "enter", "q":
+			m.showProfileTest = false
+		}
+		return m, handled()
+	}
+
+	// When entering what to test a profile against
+	if m.testingProfile && m.profileInput.Focused() {
+		return m.handleProfileTestKey(msg)
+	}
+
 	// When adding URL in sources tab
 	if m.addingURL && m.urlInput.Focused() {
 		switch key {
@@ -684,6 +1057,21 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.activeTab = tabSources
 			m.srcCursor = 0
 			return m, handled()
+		case "alt+5":
+			m.addingURL = false
+			m.urlInput.Blur()
+			m.activeTab = tabFeeds
+			return m, handled()
+		case "tab":
+			// Switch between scraped sites and Torznab indexers
+			if m.addingType == config.SourceTypeTorznab {
+				m.addingType = config.SourceTypeGeneric
+				m.urlInput.Placeholder = "Paste torrent site URL..."
+			} else {
+				m.addingType = config.SourceTypeTorznab
+				m.urlInput.Placeholder = "Torznab API URL, e.g. http://host:9117/api/...?apikey=..."
+			}
+			return m, handled()
 		case "enter":
 			if m.validatingURL {
 				return m, handled() // Already validating
@@ -692,6 +1080,9 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if rawURL != "" {
 				m.validatingURL = true
 				m.statusMsg = "Validating URL..."
+				if m.addingType == config.SourceTypeTorznab {
+					return m, tea.Batch(m.spinner.Tick, m.validateTorznab(rawURL))
+				}
 				return m, tea.Batch(m.spinner.Tick, m.validateURL(rawURL))
 			}
 			m.addingURL = false
@@ -702,16 +1093,32 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Reverse history search takes over the search input
+	if m.histSearching {
+		return m.handleHistorySearchKey(msg)
+	}
+
 	// When search input is focused (INPUT MODE), only handle specific keys
 	// Let everything else go to the text input
 	if m.searchInput.Focused() {
 		switch key {
 		case "ctrl+c":
 			return m, tea.Quit
+		case "up":
+			m.recallHistory(1)
+			return m, handled()
+		case "down":
+			m.recallHistory(-1)
+			return m, handled()
+		case "ctrl+r":
+			m.startHistorySearch()
+			return m, handled()
 		case "ctrl+u":
 			// Clear search input and results
+			m.histPos = 0
 			m.searchInput.SetValue("")
 			m.results = nil
+			m.searchAll = nil
 			m.cursor = 0
 			m.mode = viewSearch
 			m.statusMsg = ""
@@ -745,20 +1152,18 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.srcCursor = 0
 			m.addingURL = false
 			return m, handled()
+		case "alt+5":
+			m.searchInput.Blur()
+			m.activeTab = tabFeeds
+			m.addingURL = false
+			return m, handled()
 		case "esc":
 			m.searchInput.Blur()
 			return m, handled()
 		case "enter":
 			if m.searchInput.Value() != "" {
-				if m.cfg.VPN.Required && !m.vpnStatus.Connected {
-					m.statusMsg = "VPN required! Press V to connect"
-					return m, handled()
-				}
-				m.searching = true
-				m.err = nil
-				m.statusMsg = "Searching..."
-				m.searchInput.Blur()
-				return m, tea.Batch(m.spinner.Tick, m.doSearch())
+				cmd := m.startSearch(m.searchInput.Value())
+				return m, cmd
 			}
 			return m, handled()
 		}
@@ -793,6 +1198,28 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.srcCursor = 0
 		m.addingURL = false
 		return m, handled()
+	case "5", "alt+5":
+		m.activeTab = tabFeeds
+		m.addingURL = false
+		return m, handled()
+	}
+
+	// Filter bar navigation; other keys leave the bar and act as usual
+	if m.filterFocus {
+		switch key {
+		case "esc", "f", "left", "h", "right", "l", " ", "space", "enter", "x", "delete", "backspace":
+			return m.handleFilterBarKey(key)
+		}
+		m.filterFocus = false
+	}
+
+	// Detail pane navigation; other keys act on the shown torrent as usual
+	if m.detailOpen() {
+		switch key {
+		case "esc", "enter", "backspace", "tab", "shift+tab", "left", "h", "right", "l",
+			"up", "k", "down", "j", "pgup", "pgdown", " ", "space", "+", "=", "-":
+			return m.handleDetailKey(key)
+		}
 	}
 
 	// Search input NOT focused (CMD MODE) - handle navigation keys
@@ -805,6 +1232,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, handled()
 
 	case "esc":
+		if (m.activeTab == tabDownloads || m.activeTab == tabCompleted) && (len(m.marked) > 0 || m.visualAnchor != "") {
+			m.clearSelection()
+			return m, handled()
+		}
 		m.mode = viewSearch
 		return m, handled()
 
@@ -813,6 +1244,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.activeTab = tabSearch
 		m.searchInput.SetValue("")
 		m.results = nil
+		m.searchAll = nil
 		m.cursor = 0
 		m.mode = viewSearch
 		m.statusMsg = ""
@@ -837,7 +1269,13 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.statusMsg = "VPN required! Press V to connect"
 				return m, handled()
 			}
-			return m, m.downloadTorrent()
+			return m.openAddOptionsModal()
+		}
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			return m.openDetail()
+		}
+		if m.activeTab == tabFeeds {
+			return m.openRuleEditor(false)
 		}
 		return m, handled()
 
@@ -859,6 +1297,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.srcCursor > 0 {
 				m.srcCursor--
 			}
+		case tabFeeds:
+			if m.feedCursor > 0 {
+				m.feedCursor--
+			}
 		}
 		return m, handled()
 
@@ -880,6 +1322,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.srcCursor < len(m.sources)-1 {
 				m.srcCursor++
 			}
+		case tabFeeds:
+			if m.feedCursor < len(m.feedRows())-1 {
+				m.feedCursor++
+			}
 		}
 		return m, handled()
 
@@ -889,9 +1335,9 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.searchSortCol > 0 {
 				m.searchSortCol--
 			} else {
-				m.searchSortCol = 4 // Wrap to last column (5 columns)
+				m.searchSortCol = searchSortScore // Wrap to last column (7 columns)
 			}
-			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc)
+			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc, m.qualityProfile())
 			m.saveSortSettings()
 			return m, handled()
 		}
@@ -899,7 +1345,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.dlSortCol > 0 {
 				m.dlSortCol--
 			} else {
-				m.dlSortCol = 7 // Wrap to last column (8 columns)
+				m.dlSortCol = dlSortQueue // Wrap to last column (9 columns)
 			}
 			sortTorrents(m.downloading, m.dlSortCol, m.dlSortAsc)
 			m.saveSortSettings()
@@ -919,17 +1365,17 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "right", "l":
 		// Navigate sort columns
 		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
-			if m.searchSortCol < 4 {
+			if m.searchSortCol < searchSortScore {
 				m.searchSortCol++
 			} else {
 				m.searchSortCol = 0 // Wrap to first column
 			}
-			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc)
+			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc, m.qualityProfile())
 			m.saveSortSettings()
 			return m, handled()
 		}
 		if m.activeTab == tabDownloads {
-			if m.dlSortCol < 7 {
+			if m.dlSortCol < dlSortQueue {
 				m.dlSortCol++
 			} else {
 				m.dlSortCol = 0 // Wrap to first column
@@ -952,7 +1398,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "s": // Toggle sort direction
 		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
 			m.searchSortAsc = !m.searchSortAsc
-			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc)
+			sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc, m.qualityProfile())
 			m.saveSortSettings()
 			return m, handled()
 		}
@@ -969,7 +1415,12 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, handled()
 		}
 
-	case "space":
+	case " ", "space":
+		// Mark torrent for bulk actions
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			m.toggleMark()
+			return m, handled()
+		}
 		// Toggle source enabled/disabled
 		if m.activeTab == tabSources && len(m.sources) > 0 && m.srcCursor < len(m.sources) {
 			m.sources[m.srcCursor].Enabled = !m.sources[m.srcCursor].Enabled
@@ -981,14 +1432,79 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.saveSources()
 			return m, handled()
 		}
+		// Toggle feed or rule enabled/disabled
+		if m.activeTab == tabFeeds {
+			m.toggleFeedRow()
+			return m, handled()
+		}
 
-	case "a": // Add URL (sources tab)
+	case "a": // Add URL (sources tab) or feed (feeds tab), or cycle the age filter
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
+			m.cycleAgeFilter()
+			return m, handled()
+		}
 		if m.activeTab == tabSources {
 			m.addingURL = true
+			m.addingType = config.SourceTypeGeneric
+			m.urlInput.Placeholder = "Paste torrent site URL..."
 			m.urlInput.Focus()
 			m.urlInput.SetValue("")
 			return m, handled()
 		}
+		if m.activeTab == tabFeeds {
+			m.startAddFeed()
+			return m, handled()
+		}
+
+	case "z": // Cycle the search results size filter
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
+			m.cycleSizeFilter()
+		}
+		return m, handled()
+
+	case "w": // Save the last search
+		if m.activeTab == tabSearch {
+			return m.openSavedSearches(true)
+		}
+		return m, handled()
+
+	case "W": // Saved searches
+		if m.activeTab == tabSearch {
+			return m.openSavedSearches(false)
+		}
+		return m, handled()
+
+	case "P": // Cycle the quality profile results are scored by
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
+			m.cycleQualityProfile()
+		}
+		return m, handled()
+
+	case "g": // Grab the best result for the quality profile
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) && len(m.results) > 0 {
+			if m.cfg.VPN.Required && !m.vpnStatus.Connected {
+				m.statusMsg = "VPN required! Press V to connect"
+				return m, handled()
+			}
+			cmd := m.grabBest()
+			return m, cmd
+		}
+		return m, handled()
+
+	case "n": // New rule for the selected feed
+		if m.activeTab == tabFeeds {
+			return m.openRuleEditor(true)
+		}
+		return m, handled()
+
+	case "e": // Edit the selected feed rule, or expand a merged search result
+		if m.activeTab == tabFeeds {
+			return m.openRuleEditor(false)
+		}
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
+			m.resultsExpanded = !m.resultsExpanded
+		}
+		return m, handled()
 
 	case "d": // Details - load files for selected torrent
 		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) && len(m.results) > 0 {
@@ -1000,11 +1516,40 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "p": // Pause/Resume toggle
 		if m.activeTab == tabDownloads && len(m.downloading) > 0 {
-			return m, m.togglePauseTorrent()
+			return m, m.togglePauseTorrents()
 		}
 		return m, handled()
 
-	case "f": // Follow/unfollow torrent (keep cursor on it during re-sorts)
+	case "r": // Force recheck, or poll the selected feed
+		if m.activeTab == tabFeeds {
+			return m.pollSelectedFeed()
+		}
+		if len(m.visibleTorrents()) > 0 {
+			return m, m.recheckTorrents()
+		}
+		return m, handled()
+
+	case "R": // Force reannounce
+		if len(m.visibleTorrents()) > 0 {
+			return m, m.reannounceTorrents()
+		}
+		return m, handled()
+
+	case "ctrl+a": // Select all (filtered) torrents
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			m.toggleSelectAll()
+		}
+		return m, handled()
+
+	case "f": // Follow/unfollow torrent (keep cursor on it during re-sorts), or focus the filter bar
+		if m.activeTab == tabSearch && (m.mode == viewResults || m.mode == viewDetails) {
+			if len(m.searchFilters) == 0 {
+				m.statusMsg = "No filters (add them to the query, e.g. size:>2GB seeds:>=10)"
+			} else {
+				m.filterFocus = true
+			}
+			return m, handled()
+		}
 		if m.activeTab == tabDownloads && len(m.downloading) > 0 && m.dlCursor < len(m.downloading) {
 			t := m.downloading[m.dlCursor]
 			if m.followingHash == t.Hash {
@@ -1019,11 +1564,8 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, handled()
 
 	case "x", "delete": // Delete torrent or remove source
-		if m.activeTab == tabDownloads && len(m.downloading) > 0 {
-			return m, m.deleteTorrent(false)
-		}
-		if m.activeTab == tabCompleted && len(m.completed) > 0 {
-			return m, m.deleteTorrent(false)
+		if len(m.visibleTorrents()) > 0 {
+			return m.requestDelete(false)
 		}
 		if m.activeTab == tabSources && len(m.sources) > 0 && m.srcCursor < len(m.sources) {
 			src := m.sources[m.srcCursor]
@@ -1039,23 +1581,124 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Removed: %s", src.Name)
 			return m, handled()
 		}
+		if m.activeTab == tabFeeds {
+			m.deleteFeedRow()
+		}
 		return m, handled()
 
 	case "X": // Delete with files
-		if m.activeTab == tabDownloads && len(m.downloading) > 0 {
-			return m, m.deleteTorrent(true)
+		if len(m.visibleTorrents()) > 0 {
+			return m.requestDelete(true)
 		}
-		if m.activeTab == tabCompleted && len(m.completed) > 0 {
-			return m, m.deleteTorrent(true)
+		return m, handled()
+
+	case "t": // Test the selected source's scraping profile
+		if m.activeTab == tabSources {
+			m.startProfileTest()
+			if m.testingProfile {
+				return m, textinput.Blink
+			}
 		}
 		return m, handled()
 
 	case "m": // Move to Plex
 		if m.activeTab == tabCompleted && len(m.completed) > 0 {
+			if m.selectionCount() > 1 && !m.detailOpen() {
+				// Bulk moves skip the modal and trust auto-detection
+				if msg := m.checkPlexLibraries(); msg != "" {
+					m.statusMsg = msg
+					return m, handled()
+				}
+				m.statusMsg = fmt.Sprintf("Moving %d torrents to Plex...", m.selectionCount())
+				return m, m.moveTorrentsToPlex()
+			}
 			return m.openMoveModal()
 		}
 		return m, handled()
 
+	case "D": // Dismiss from the review queue
+		if m.activeTab == tabCompleted && len(m.completed) > 0 {
+			m.dismissReview()
+		}
+		return m, handled()
+
+	case "A": // Add torrent by magnet, URL or local .torrent file
+		return m.openAddModal()
+
+	case "[", "]": // Previous/next sidebar filter
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			if key == "[" {
+				m.cycleFilter(-1)
+			} else {
+				m.cycleFilter(1)
+			}
+		}
+		return m, handled()
+
+	case "b": // Show/hide the filter sidebar
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			m.showSidebar = !m.showSidebar
+		}
+		return m, handled()
+
+	case "C": // Set category on selected torrent
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			return m.openLabelPicker(pickCategory)
+		}
+		return m, handled()
+
+	case "L": // Speed limits for the selection (global outside torrent tabs)
+		return m.openLimitsModal()
+
+	case "S": // Toggle alternative speed limits
+		return m, m.toggleAltSpeeds()
+
+	case "+", "=": // Move up the queue
+		if len(m.visibleTorrents()) > 0 {
+			cmd := m.queueUp()
+			return m, cmd
+		}
+		return m, handled()
+
+	case "-": // Move down the queue
+		if len(m.visibleTorrents()) > 0 {
+			cmd := m.queueDown()
+			return m, cmd
+		}
+		return m, handled()
+
+	case "K": // Move to the top of the queue
+		if len(m.visibleTorrents()) > 0 {
+			cmd := m.queueTop()
+			return m, cmd
+		}
+		return m, handled()
+
+	case "J": // Move to the bottom of the queue
+		if len(m.visibleTorrents()) > 0 {
+			cmd := m.queueBottom()
+			return m, cmd
+		}
+		return m, handled()
+
+	case "F": // Force start toggle
+		if len(m.visibleTorrents()) > 0 {
+			return m, m.toggleForceStart()
+		}
+		return m, handled()
+
+	case "T": // Toggle tags on selected torrent
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			return m.openLabelPicker(pickTags)
+		}
+		return m, handled()
+
+	case "M": // Range select in torrent lists
+		if m.activeTab == tabDownloads || m.activeTab == tabCompleted {
+			m.toggleVisual()
+		}
+		return m, handled()
+
 	case "v":
 		return m, m.checkVPNStatus()
 
@@ -1120,46 +1763,136 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // Commands
-func (m Model) doSearch() tea.Cmd {
-	query := m.searchInput.Value()
-	sources := m.sources
+// doSearch starts a search of all enabled sources for the text of a query.
+// Results arrive one source at a time as searchSourceMsg, followed by
+// searchDoneMsg.
+func (m *Model) doSearch(query string) tea.Cmd {
+	var scrapers []scraper.Scraper
+	m.searchSources = nil
+	for _, src := range m.sources {
+		if src.Enabled && src.Scraper != nil {
+			scrapers = append(scrapers, src.Scraper)
+			m.searchSources = append(m.searchSources, src.Scraper.Name())
+		}
+	}
+	m.searchID++
+	m.searchStatuses = nil
+	m.searchFresh = true
 
+	id := m.searchID
+	multi := scraper.NewMultiScraper(scrapers...)
 	return func() tea.Msg {
-		var allResults []scraper.Torrent
-		var lastErr error
-
-		// Search all enabled sources
-		for _, src := range sources {
-			if !src.Enabled || src.Scraper == nil {
-				continue
-			}
+		return waitForSearch(id, multi.Stream(context.Background(), query))()
+	}
+}
 
-			results, err := src.Scraper.Search(context.Background(), query)
-			if err != nil {
-				lastErr = err
-				continue
-			}
-			allResults = append(allResults, results...)
+// waitForSearch waits for the next source of a running search to finish
+func waitForSearch(id int, ch <-chan scraper.SourceResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-ch
+		if !ok {
+			return searchDoneMsg{id: id}
 		}
+		return searchSourceMsg{id: id, result: result, next: ch}
+	}
+}
 
-		// Filter out obvious garbage (no seeds, no leechers, no size = sidebar/ad links)
-		filtered := make([]scraper.Torrent, 0, len(allResults))
-		for _, t := range allResults {
-			// Keep if has any activity or size info
-			if t.Seeders > 0 || t.Leechers > 0 || t.Size != "" {
-				filtered = append(filtered, t)
-			}
+// keepResult filters out obvious garbage (no seeds, no leechers, no size =
+// sidebar/ad links)
+func keepResult(t scraper.Torrent) bool {
+	return t.Seeders > 0 || t.Leechers > 0 || t.Size != ""
+}
+
+// handleSearchSource merges one source's results into the results list,
+// keeping the cursor on the selected result
+func (m *Model) handleSearchSource(msg searchSourceMsg) tea.Cmd {
+	if msg.id != m.searchID {
+		return nil // Stale search
+	}
+	m.searchStatuses = append(m.searchStatuses, msg.result.SourceStatus)
+
+	var fresh []scraper.Torrent
+	for _, t := range msg.result.Results {
+		if keepResult(t) {
+			fresh = append(fresh, t)
 		}
-		allResults = filtered
+	}
+	if len(fresh) > 0 {
+		if m.searchFresh {
+			// First results of this search replace the previous search
+			m.searchFresh = false
+			m.searchAll = nil
+			m.results = nil
+			m.cursor = 0
+			m.mode = viewResults
+			m.downloaded = make(map[string]bool)
+		}
+		// Merge listings of the same torrent from different sources
+		m.searchAll = scraper.Dedupe(append(m.searchAll, fresh...))
+		// Apply current filters and sort settings to new results
+		m.applyResultFilters()
+	}
+	m.statusMsg = fmt.Sprintf("Searching... %d results", len(m.searchAll))
+	return waitForSearch(msg.id, msg.next)
+}
 
-		// Sorting is applied in searchResultMsg handler using user's sort settings
+// handleSearchDone finishes a search once every source has reported
+func (m *Model) handleSearchDone(msg searchDoneMsg) {
+	if msg.id != m.searchID {
+		return
+	}
+	m.searching = false
+	if !m.searchFresh {
+		m.statusMsg = fmt.Sprintf("Found %d results", len(m.searchAll))
+		if hidden := len(m.searchAll) - len(m.results); hidden > 0 {
+			m.statusMsg += fmt.Sprintf(" (%d filtered out)", hidden)
+		}
+		return
+	}
 
-		if len(allResults) == 0 && lastErr != nil {
-			return searchResultMsg{err: lastErr}
+	// Nothing usable came back
+	m.results = nil
+	m.searchAll = nil
+	var failed error
+	for _, st := range m.searchStatuses {
+		if st.Err == nil {
+			failed = nil
+			break
 		}
+		failed = fmt.Errorf("%s: %w", st.Source, st.Err)
+	}
+	if failed != nil {
+		m.err = failed
+		m.statusMsg = fmt.Sprintf("Search failed: %v", failed)
+	} else {
+		m.statusMsg = "No results found"
+	}
+}
 
-		return searchResultMsg{results: allResults}
+// searchStatusLine formats the per-source status of the current search,
+// e.g. "src-a: 42 (0.8s)  src-b: timeout  src-c: ..."
+func (m Model) searchStatusLine() string {
+	styles := GetStyles()
+	reported := make(map[string]scraper.SourceStatus, len(m.searchStatuses))
+	for _, st := range m.searchStatuses {
+		reported[st.Source] = st
 	}
+
+	var parts []string
+	for _, name := range m.searchSources {
+		st, ok := reported[name]
+		switch {
+		case !ok:
+			parts = append(parts, styles.Muted.Render(name+": ..."))
+		case st.TimedOut():
+			parts = append(parts, styles.HealthMed.Render(st.String()))
+		case st.Err != nil:
+			parts = append(parts, styles.HealthBad.Render(st.String()))
+		default:
+			parts = append(parts, styles.Muted.Render(st.String()))
+		}
+	}
+	return strings.Join(parts, styles.Muted.Render("  "))
 }
 
 func (m Model) checkVPNStatus() tea.Cmd {
@@ -1210,6 +1943,41 @@ func (m Model) validateURL(rawURL string) tea.Cmd {
 		return urlValidateMsg{
 			url:     normalizedURL,
 			name:    normalizedURL,
+			typ:     config.SourceTypeGeneric,
+			scraper: s,
+			warning: warning,
+		}
+	}
+}
+
+// validateTorznab checks a Torznab endpoint by fetching its capabilities.
+// An apikey parameter in the URL is split off and stored separately.
+func (m Model) validateTorznab(rawURL string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		endpoint, apiKey := scraper.SplitTorznabURL(rawURL)
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return urlValidateMsg{url: rawURL, err: fmt.Errorf("expected an http(s) Torznab API URL")}
+		}
+
+		s := scraper.NewTorznabScraper(endpoint, endpoint, apiKey, nil)
+		caps, err := s.Caps(ctx)
+		if err != nil {
+			return urlValidateMsg{url: rawURL, err: err}
+		}
+
+		var warning string
+		if mode, ok := caps.Searching["search"]; ok && !mode.Available {
+			warning = " (indexer reports search unavailable)"
+		}
+		return urlValidateMsg{
+			url:     endpoint,
+			name:    endpoint,
+			typ:     config.SourceTypeTorznab,
+			apiKey:  apiKey,
 			scraper: s,
 			warning: warning,
 		}
@@ -1222,10 +1990,15 @@ func (m Model) saveSources() {
 	for _, src := range m.sources {
 		if !src.Builtin {
 			customSources = append(customSources, config.SourceConfig{
-				Name:    src.Name,
-				URL:     src.URL,
-				Enabled: src.Enabled,
-				Warning: src.Warning,
+				Name:       src.Name,
+				URL:        src.URL,
+				Type:       src.Type,
+				Enabled:    src.Enabled,
+				Warning:    src.Warning,
+				APIKey:     src.APIKey,
+				Categories: src.Categories,
+				Defaults:   src.Defaults,
+				Profile:    src.Profile,
 			})
 		}
 	}
@@ -1355,7 +2128,13 @@ func (m Model) handleSettingsKey(key string) (tea.Model, tea.Cmd) {
 // completePathInput performs tab completion on a path input field.
 // Returns true if completion was performed.
 func (m *Model) completePathInput(fieldIdx int) bool {
-	input := m.settingsInputs[fieldIdx].Value()
+	return completePath(&m.settingsInputs[fieldIdx])
+}
+
+// completePath performs shell-style tab completion on a text input holding a path.
+// Returns true if completion was performed.
+func completePath(ti *textinput.Model) bool {
+	input := ti.Value()
 	if input == "" {
 		return false
 	}
@@ -1366,8 +2145,8 @@ func (m *Model) completePathInput(fieldIdx int) bool {
 		input = filepath.Join(home, input[2:])
 	} else if input == "~" {
 		home, _ := os.UserHomeDir()
-		m.settingsInputs[fieldIdx].SetValue(home + "/")
-		m.settingsInputs[fieldIdx].SetCursor(len(home) + 1)
+		ti.SetValue(home + "/")
+		ti.SetCursor(len(home) + 1)
 		return true
 	}
 
@@ -1412,8 +2191,8 @@ func (m *Model) completePathInput(fieldIdx int) bool {
 		if strings.HasPrefix(result, home) {
 			result = "~" + result[len(home):]
 		}
-		m.settingsInputs[fieldIdx].SetValue(result)
-		m.settingsInputs[fieldIdx].SetCursor(len(result))
+		ti.SetValue(result)
+		ti.SetCursor(len(result))
 		return true
 	}
 
@@ -1437,8 +2216,8 @@ func (m *Model) completePathInput(fieldIdx int) bool {
 		if strings.HasPrefix(common, home) {
 			common = "~" + common[len(home):]
 		}
-		m.settingsInputs[fieldIdx].SetValue(common)
-		m.settingsInputs[fieldIdx].SetCursor(len(common))
+		ti.SetValue(common)
+		ti.SetCursor(len(common))
 		return true
 	}
 
@@ -1503,39 +2282,94 @@ func (m *Model) saveSettings() {
 		m.cfg.QBittorrent.Username,
 		m.cfg.QBittorrent.Password,
 	)
+	m.qbitSync = qbit.NewSync(m.qbitClient)
 	m.vpnChecker = vpn.NewChecker(m.cfg.VPN.StatusScript, m.cfg.VPN.ConnectScript)
 }
 
-// openMoveModal opens the move to Plex modal for the selected torrent
-func (m Model) openMoveModal() (tea.Model, tea.Cmd) {
+// checkPlexLibraries returns a status message if the Plex libraries are not usable
+func (m Model) checkPlexLibraries() string {
 	// Validate config - paths must be set
 	if m.cfg.Plex.MovieLibrary == "" || m.cfg.Plex.TVLibrary == "" {
-		m.statusMsg = "Configure Plex libraries in Settings (c) first"
-		return m, handled()
+		return "Configure Plex libraries in Settings (c) first"
 	}
 
 	// Validate that library paths exist and are directories
 	if info, err := os.Stat(m.cfg.Plex.MovieLibrary); err != nil {
-		m.statusMsg = fmt.Sprintf("Movie library not found: %s", m.cfg.Plex.MovieLibrary)
-		return m, handled()
+		return fmt.Sprintf("Movie library not found: %s", m.cfg.Plex.MovieLibrary)
 	} else if !info.IsDir() {
-		m.statusMsg = "Movie library path is not a directory"
-		return m, handled()
+		return "Movie library path is not a directory"
 	}
 
 	if info, err := os.Stat(m.cfg.Plex.TVLibrary); err != nil {
-		m.statusMsg = fmt.Sprintf("TV library not found: %s", m.cfg.Plex.TVLibrary)
-		return m, handled()
+		return fmt.Sprintf("TV library not found: %s", m.cfg.Plex.TVLibrary)
 	} else if !info.IsDir() {
-		m.statusMsg = "TV library path is not a directory"
-		return m, handled()
+		return "TV library path is not a directory"
 	}
 
-	if len(m.completed) == 0 || m.dlCursor >= len(m.completed) {
-		return m, handled()
+	if _, err := plex.ParseTransferMode(m.cfg.Plex.TransferMode); err != nil {
+		return fmt.Sprintf("Plex transfer_mode: %v", err)
+	}
+	if _, err := plex.ParseChecksum(m.cfg.Plex.Verify); err != nil {
+		return fmt.Sprintf("Plex verify: %v", err)
+	}
+	if _, err := plex.ParseTemplate(m.cfg.Plex.MovieTemplate, plex.MediaTypeMovie); err != nil {
+		return fmt.Sprintf("Plex movie_template: %v", err)
+	}
+	if _, err := plex.ParseTemplate(m.cfg.Plex.TVTemplate, plex.MediaTypeTV); err != nil {
+		return fmt.Sprintf("Plex tv_template: %v", err)
 	}
+	return ""
+}
 
-	t := m.completed[m.dlCursor]
+// plexMover returns a mover for the configured libraries
+func (m Model) plexMover() *plex.Mover {
+	return plex.NewMover(m.plexMoveConfig())
+}
+
+// seedingMover returns a mover for moves made without the modal (the
+// pipeline and bulk moves), which must leave the torrent its data:
+// transfer_mode "move" falls back to auto there
+func (m Model) seedingMover() *plex.Mover {
+	config := m.plexMoveConfig()
+	if config.TransferMode == plex.TransferMove {
+		config.TransferMode = plex.TransferAuto
+	}
+	return plex.NewMover(config)
+}
+
+// plexMoveConfig returns the move settings from the config
+func (m Model) plexMoveConfig() plex.MoveConfig {
+	// All checked by checkPlexLibraries
+	mode, _ := plex.ParseTransferMode(m.cfg.Plex.TransferMode)
+	verify, _ := plex.ParseChecksum(m.cfg.Plex.Verify)
+	movieTemplate, _ := plex.ParseTemplate(m.cfg.Plex.MovieTemplate, plex.MediaTypeMovie)
+	tvTemplate, _ := plex.ParseTemplate(m.cfg.Plex.TVTemplate, plex.MediaTypeTV)
+	return plex.MoveConfig{
+		MovieLibraryPath: m.cfg.Plex.MovieLibrary,
+		TVLibraryPath:    m.cfg.Plex.TVLibrary,
+		UseSudo:          m.cfg.Plex.UseSudo,
+		TransferMode:     mode,
+		Verify:           verify,
+		MovieTemplate:    movieTemplate,
+		TVTemplate:       tvTemplate,
+	}
+}
+
+// detectForMove returns the content path of a completed torrent and the
+// media detected from it, taking it for a movie if detection failed
+func detectForMove(t qbit.TorrentInfo) (string, plex.DetectionResult) {
+	sourcePath, detection := detectMedia(t)
+	if detection.Type == plex.MediaTypeUnknown {
+		// Default to movie if detection failed
+		detection.Type = plex.MediaTypeMovie
+		detection.Title = plex.SanitizeFilename(t.Name)
+	}
+	return sourcePath, detection
+}
+
+// detectMedia returns the content path of a completed torrent and the
+// media detected from it
+func detectMedia(t qbit.TorrentInfo) (string, plex.DetectionResult) {
 	// Use ContentPath from qBittorrent (full path to content)
 	// Fall back to SavePath + Name if ContentPath is empty
 	sourcePath := t.ContentPath
@@ -1554,23 +2388,39 @@ func (m Model) openMoveModal() (tea.Model, tea.Cmd) {
 		// Fallback to folder detection if no video found
 		detection, _ = plex.DetectFromPath(sourcePath)
 	}
-	if detection.Type == plex.MediaTypeUnknown {
-		// Default to movie if detection failed
-		detection.Type = plex.MediaTypeMovie
-		detection.Title = plex.SanitizeFilename(t.Name)
+	return sourcePath, detection
+}
+
+// openMoveModal opens the move to Plex modal for the selected torrent
+func (m Model) openMoveModal() (tea.Model, tea.Cmd) {
+	if msg := m.checkPlexLibraries(); msg != "" {
+		m.statusMsg = msg
+		return m, handled()
 	}
 
+	t, ok := m.selectedTorrent()
+	if !ok {
+		return m, handled()
+	}
+
+	sourcePath, detection := detectForMove(t)
+
 	m.showMoveModal = true
 	m.moveDetection = detection
 	m.moveMediaType = detection.Type
 	m.moveSourcePath = sourcePath
+	m.moveHash = t.Hash
+	m.moveName = t.Name
 	m.moveCleanup = false // Default OFF - user must explicitly enable
+	m.moveRelocate = false
+	m.moveFiles = nil
 	m.moveError = ""
 	m.moveInProgress = false
 	m.moveProgress = 0
 	m.moveComplete = false
 	m.moveShimmerPos = -1
 	m.moveShowCleanup = false
+	m.moveCleanupLinked = false
 	m.moveRemainingFiles = nil
 	m.moveSourceDir = ""
 
@@ -1605,35 +2455,25 @@ func (m Model) openMoveModal() (tea.Model, tea.Cmd) {
 
 // updateMoveDestPreview updates the destination preview based on current settings
 func (m *Model) updateMoveDestPreview() {
-	ext := ".mkv" // Default extension
-	if video, err := plex.FindMainVideo(m.moveSourcePath); err == nil {
-		ext = filepath.Ext(video)
-	}
-
 	title := m.moveTitleInput.Value()
 	if title == "" {
 		title = m.moveDetection.Title
 	}
+	detection := m.moveDetection
+	detection.Type = m.moveMediaType
+	detection.Title = title
 
-	switch m.moveMediaType {
-	case plex.MediaTypeMovie:
-		// Movies go directly in Movies/ folder (no subdirectory)
-		if m.moveDetection.Year > 0 {
-			m.moveDestPreview = filepath.Join(
-				m.cfg.Plex.MovieLibrary,
-				fmt.Sprintf("%s (%d)%s", title, m.moveDetection.Year, ext),
-			)
-		} else {
-			m.moveDestPreview = filepath.Join(m.cfg.Plex.MovieLibrary, title+ext)
-		}
-	case plex.MediaTypeTV:
-		m.moveDestPreview = filepath.Join(
-			m.cfg.Plex.TVLibrary,
-			title,
-			fmt.Sprintf("Season %02d", m.moveDetection.Season),
-			filepath.Base(m.moveSourcePath),
-		)
+	if m.moveRelocate {
+		m.moveDestPreview = m.relocatePreview(detection)
+		return
 	}
+	// Named by the templates, as the move will
+	dest, err := m.plexMover().Destination(m.moveSourcePath, detection)
+	if err != nil {
+		m.moveDestPreview = err.Error()
+		return
+	}
+	m.moveDestPreview = dest
 }
 
 // handleMoveModalKey handles keyboard input for the move modal
@@ -1654,6 +2494,7 @@ func (m Model) handleMoveModalKey(key string) (tea.Model, tea.Cmd) {
 		default:
 			var cmd tea.Cmd
 			m.moveTitleInput, cmd = m.moveTitleInput.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			m.updateMoveDestPreview() // Preview follows the title as it's typed
 			return m, cmd
 		}
 	}
@@ -1706,8 +2547,24 @@ func (m Model) handleMoveModalKey(key string) (tea.Model, tea.Cmd) {
 		return m, handled()
 
 	case "c":
-		// Toggle cleanup
+		// Toggle cleanup (relocated data is still seeded, nothing to clean up)
 		m.moveCleanup = !m.moveCleanup
+		if m.moveCleanup && m.moveRelocate {
+			m.moveRelocate = false
+			m.updateMoveDestPreview()
+		}
+		return m, handled()
+
+	case "r":
+		// Toggle between copying and relocating in qBittorrent
+		m.moveRelocate = !m.moveRelocate
+		if m.moveRelocate {
+			m.moveCleanup = false
+		}
+		m.updateMoveDestPreview()
+		if m.moveRelocate && m.moveFiles == nil {
+			return m, m.fetchRelocateFiles()
+		}
 		return m, handled()
 
 	case "enter":
@@ -1750,8 +2607,12 @@ var (
 
 // startMoveOperation begins the async move operation
 func (m Model) startMoveOperation() (tea.Model, tea.Cmd) {
-	// Check if sudo rsync is available without password (if UseSudo is enabled)
-	if m.cfg.Plex.UseSudo {
+	if m.moveRelocate {
+		return m.startRelocate()
+	}
+
+	// Check if sudo rsync is available without password (if it will be used)
+	if m.cfg.Plex.UseSudo && m.plexMoveConfig().UsesRsync() {
 		if err := exec.Command("sudo", "-n", "rsync", "--version").Run(); err != nil {
 			m.moveError = "Sudo requires password. Add to sudoers: username ALL=(ALL) NOPASSWD: /usr/bin/rsync"
 			return m, handled()
@@ -1779,9 +2640,7 @@ func (m Model) startMoveOperation() (tea.Model, tea.Cmd) {
 
 	sourcePath := m.moveSourcePath
 	cleanup := m.moveCleanup
-	movieLib := m.cfg.Plex.MovieLibrary
-	tvLib := m.cfg.Plex.TVLibrary
-	useSudo := m.cfg.Plex.UseSudo
+	mover := m.plexMover()
 
 	// Create channels for progress and result
 	moveProgressChan = make(chan plex.MoveProgress, 100)
@@ -1790,12 +2649,6 @@ func (m Model) startMoveOperation() (tea.Model, tea.Cmd) {
 
 	// Start move in background goroutine
 	go func() {
-		mover := plex.NewMover(plex.MoveConfig{
-			MovieLibraryPath: movieLib,
-			TVLibraryPath:    tvLib,
-			UseSudo:          useSudo,
-		})
-
 		result, err := mover.MoveToLibraryWithProgress(
 			context.Background(),
 			sourcePath,
@@ -1869,78 +2722,46 @@ func (m Model) checkQbitStatus() tea.Cmd {
 	}
 }
 
+// fetchTorrents refreshes the sync store with the changes since the last
+// poll and splits the resulting snapshot into the Downloads/Completed lists
 func (m Model) fetchTorrents() tea.Cmd {
-	client := m.qbitClient
+	store := m.qbitSync
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		torrents, err := client.GetTorrents(ctx)
-		if err != nil {
+		if err := store.Update(ctx); err != nil {
 			return torrentListMsg{err: err}
 		}
 
-		var downloading, completed []qbit.TorrentInfo
-		for _, t := range torrents {
-			// States: downloading, stalledDL, pausedDL, queuedDL, checkingDL
-			// completed: uploading, stalledUP, pausedUP, queuedUP, checkingUP, completed
-			switch t.State {
-			case "downloading", "stalledDL", "pausedDL", "queuedDL", "checkingDL", "metaDL", "forcedDL":
-				downloading = append(downloading, t)
-			default:
-				// Everything else is considered completed/seeding
-				if t.Progress >= 1.0 {
-					completed = append(completed, t)
-				} else {
-					downloading = append(downloading, t)
-				}
-			}
+		downloading, completed := splitTorrents(store.Torrents())
+		return torrentListMsg{
+			downloading: downloading,
+			completed:   completed,
+			categories:  store.Categories(),
+			tags:        store.Tags(),
+			server:      store.ServerState(),
 		}
-
-		return torrentListMsg{downloading: downloading, completed: completed}
 	}
 }
 
-func (m Model) togglePauseTorrent() tea.Cmd {
-	if m.dlCursor >= len(m.downloading) {
-		return nil
-	}
-	t := m.downloading[m.dlCursor]
-	client := m.qbitClient
-	isPaused := strings.Contains(t.State, "paused")
-
-	return func() tea.Msg {
-		var err error
-		var action string
-		if isPaused {
-			err = client.Resume(context.Background(), t.Hash)
-			action = "Resumed"
-		} else {
-			err = client.Pause(context.Background(), t.Hash)
-			action = "Paused"
-		}
-		return torrentActionMsg{action: action, name: t.Name, err: err}
-	}
-}
-
-func (m Model) deleteTorrent(deleteFiles bool) tea.Cmd {
-	var t qbit.TorrentInfo
-	if m.activeTab == tabDownloads && m.dlCursor < len(m.downloading) {
-		t = m.downloading[m.dlCursor]
-	} else if m.activeTab == tabCompleted && m.dlCursor < len(m.completed) {
-		t = m.completed[m.dlCursor]
-	} else {
-		return nil
-	}
-
-	client := m.qbitClient
-	return func() tea.Msg {
-		err := client.Delete(context.Background(), t.Hash, deleteFiles)
-		action := "Removed"
-		if deleteFiles {
-			action = "Deleted"
+// splitTorrents separates active downloads from completed/seeding torrents
+func splitTorrents(torrents []qbit.TorrentInfo) (downloading, completed []qbit.TorrentInfo) {
+	for _, t := range torrents {
+		// States: downloading, stalledDL, pausedDL, queuedDL, checkingDL
+		// completed: uploading, stalledUP, pausedUP, queuedUP, checkingUP, completed
+		switch t.State {
+		case "downloading", "stalledDL", "pausedDL", "queuedDL", "checkingDL", "metaDL", "forcedDL":
+			downloading = append(downloading, t)
+		default:
+			// Everything else is considered completed/seeding
+			if t.Progress >= 1.0 {
+				completed = append(completed, t)
+			} else {
+				downloading = append(downloading, t)
+			}
 		}
-		return torrentActionMsg{action: action, name: t.Name, err: err}
 	}
+	return downloading, completed
 }
 
 func (m Model) moveToPlexMovie() tea.Cmd {
@@ -2000,13 +2821,10 @@ func (m Model) loadFiles() tea.Cmd {
 	}
 }
 
-func (m Model) downloadTorrent() tea.Cmd {
-	if m.cursor >= len(m.results) {
-		return nil
-	}
-	t := m.results[m.cursor]
+// downloadResult sends a search result to qBittorrent, fetching its magnet
+// from the source first if the listing didn't have one
+func (m Model) downloadResult(t scraper.Torrent, opts qbit.AddTorrentOptions) tea.Cmd {
 	client := m.qbitClient
-	savePath := m.cfg.Downloads.Path
 
 	// Find the scraper for this torrent's source
 	var src scraper.Scraper
@@ -2033,8 +2851,8 @@ func (m Model) downloadTorrent() tea.Cmd {
 			return torrentAddedMsg{err: fmt.Errorf("no download link available")}
 		}
 
-		err := client.AddMagnet(context.Background(), t.Magnet, savePath)
-		return torrentAddedMsg{name: t.Name, infohash: ExtractInfohash(t.Magnet), err: err}
+		err := client.AddURLs(context.Background(), []string{t.Magnet}, opts)
+		return torrentAddedMsg{name: t.Name, infohash: resultKey(t), err: err}
 	}
 }
 
@@ -2081,11 +2899,21 @@ func (m Model) View() string {
 		case tabSearch:
 			b.WriteString(m.renderSearchTab(contentHeight))
 		case tabDownloads:
-			b.WriteString(m.renderDownloadsTab(contentHeight))
+			if m.detailOpen() {
+				b.WriteString(m.renderDetailPane(contentHeight))
+			} else {
+				b.WriteString(m.renderWithSidebar(contentHeight, Model.renderDownloadsTab))
+			}
 		case tabCompleted:
-			b.WriteString(m.renderCompletedTab(contentHeight))
+			if m.detailOpen() {
+				b.WriteString(m.renderDetailPane(contentHeight))
+			} else {
+				b.WriteString(m.renderWithSidebar(contentHeight, Model.renderCompletedTab))
+			}
 		case tabSources:
 			b.WriteString(m.renderSourcesTab(contentHeight))
+		case tabFeeds:
+			b.WriteString(m.renderFeedsTab(contentHeight))
 		}
 	}
 
@@ -2099,6 +2927,30 @@ func (m Model) View() string {
 	if m.showSettings {
 		return m.overlayModal(baseContent, m.renderSettingsModal())
 	}
+	if m.showAddModal {
+		return m.overlayModal(baseContent, m.renderAddModal())
+	}
+	if m.showRuleEditor {
+		return m.overlayModal(baseContent, m.renderRuleEditor())
+	}
+	if m.showProfileTest {
+		return m.overlayModal(baseContent, m.renderProfileTest())
+	}
+	if m.showAddOptions {
+		return m.overlayModal(baseContent, m.renderAddOptionsModal())
+	}
+	if m.showLabelPicker {
+		return m.overlayModal(baseContent, m.renderLabelPicker())
+	}
+	if m.showLimits {
+		return m.overlayModal(baseContent, m.renderLimitsModal())
+	}
+	if m.showSaved {
+		return m.overlayModal(baseContent, m.renderSavedSearches())
+	}
+	if m.pick.open {
+		return m.overlayModal(baseContent, m.renderFilePicker())
+	}
 	if m.confirmingQuit {
 		return m.overlayModal(baseContent, m.renderQuitModal())
 	}
@@ -2324,6 +3176,13 @@ func (m Model) renderMoveModal() string {
 	}
 	content.WriteString(fmt.Sprintf("  Type:        %s  %s\n", movieLabel, tvLabel))
 
+	// Copy/relocate toggle
+	copyLabel, relocateLabel := styles.Title.Render("[Copy]"), styles.Muted.Render(" Relocate ")
+	if m.moveRelocate {
+		copyLabel, relocateLabel = styles.Muted.Render(" Copy "), styles.Title.Render("[Relocate]")
+	}
+	content.WriteString(fmt.Sprintf("  Mode:        %s  %s\n", copyLabel, relocateLabel))
+
 	// Title (editable)
 	if m.moveEditing {
 		content.WriteString(fmt.Sprintf("  Title:       %s\n", m.moveTitleInput.View()))
@@ -2364,18 +3223,34 @@ func (m Model) renderMoveModal() string {
 		content.WriteString(fmt.Sprintf("  Subtitles:   %d files found\n", len(m.moveSubtitles)))
 	}
 
+	// Transfer mode from the config, qBittorrent moves relocated data
+	mode, _ := plex.ParseTransferMode(m.cfg.Plex.TransferMode)
+	transfer := mode.String()
+	if m.moveRelocate {
+		transfer = "qBittorrent, keeps seeding"
+	}
+	content.WriteString(fmt.Sprintf("  Transfer:    %s\n", styles.Muted.Render(transfer)))
+
 	content.WriteString("\n")
 
 	// Cleanup toggle
-	cleanupStr := "  [ ] Delete source after move"
-	if m.moveCleanup {
-		cleanupStr = "  [×] Delete source after move"
+	if !m.moveRelocate {
+		cleanupStr := "  [ ] Delete source after move"
+		if m.moveCleanup {
+			cleanupStr = "  [×] Delete source after move"
+		}
+		content.WriteString(styles.Muted.Render(cleanupStr))
+		if m.moveCleanup {
+			content.WriteString(styles.Error.Render(" - the torrent stops seeding"))
+		}
+		content.WriteString("\n\n")
 	}
-	content.WriteString(styles.Muted.Render(cleanupStr))
-	content.WriteString("\n\n")
 
 	// Status message
-	if m.moveInProgress {
+	if m.moveInProgress && m.moveRelocate {
+		content.WriteString(styles.Muted.Render("  Renaming files in qBittorrent..."))
+		content.WriteString("\n")
+	} else if m.moveInProgress {
 		// Show live progress bar
 		content.WriteString(m.renderProgressBar())
 		content.WriteString("\n")
@@ -2383,8 +3258,16 @@ func (m Model) renderMoveModal() string {
 		// Show cleanup confirmation with remaining files
 		content.WriteString(styles.VPNConnected.Render("  " + m.moveError))
 		content.WriteString("\n\n")
-		content.WriteString(styles.Title.Render("  Remaining files to delete:"))
-		content.WriteString("\n")
+		if m.moveCleanupLinked {
+			content.WriteString(styles.Error.Render("  The torrent will stop seeding: the library keeps its linked copy"))
+			content.WriteString("\n")
+			content.WriteString(styles.Title.Render("  Delete the torrent's files:"))
+			content.WriteString(fmt.Sprintf(" %s\n", styles.Muted.Render(TruncateString(m.moveSourceDir, 40))))
+		}
+		if len(m.moveRemainingFiles) > 0 {
+			content.WriteString(styles.Title.Render("  Remaining files to delete:"))
+			content.WriteString("\n")
+		}
 		maxFiles := 5
 		for i, f := range m.moveRemainingFiles {
 			if i >= maxFiles {
@@ -2418,7 +3301,7 @@ func (m Model) renderMoveModal() string {
 	} else if m.moveComplete {
 		content.WriteString(styles.Muted.Render("  [esc] Close"))
 	} else {
-		content.WriteString(styles.Muted.Render("  [tab]Type [i]Edit [c]Cleanup [enter]Move [esc]Cancel"))
+		content.WriteString(styles.Muted.Render("  [tab]Type [i]Edit [r]Relocate [c]Cleanup [enter]Move [esc]Cancel"))
 	}
 
 	// Use success green border when complete or showing cleanup
@@ -2765,13 +3648,19 @@ func (m Model) renderLogo() string {
 func (m Model) renderTabBar() string {
 	styles := GetStyles()
 
-	// Count enabled sources
+	// Count enabled sources and feeds
 	enabledSources := 0
 	for _, s := range m.sources {
 		if s.Enabled {
 			enabledSources++
 		}
 	}
+	enabledFeeds := 0
+	for _, f := range m.cfg.Feeds {
+		if f.Enabled {
+			enabledFeeds++
+		}
+	}
 
 	tabs := []struct {
 		num   string
@@ -2783,6 +3672,7 @@ func (m Model) renderTabBar() string {
 		{"[2]", "Downloads", tabDownloads, len(m.downloading)},
 		{"[3]", "Completed", tabCompleted, len(m.completed)},
 		{"[4]", "Sources", tabSources, enabledSources},
+		{"[5]", "Feeds", tabFeeds, enabledFeeds},
 	}
 
 	var parts []string
@@ -2794,16 +3684,21 @@ func (m Model) renderTabBar() string {
 		if t.count > 0 {
 			label = fmt.Sprintf("%s(%d)", t.name, t.count)
 		}
+		// New saved search results
+		badge := ""
+		if n := m.newSavedResults(); t.tab == tabSearch && n > 0 {
+			badge = styles.HealthGood.Render(fmt.Sprintf("+%d", n))
+		}
 
 		if t.tab == m.activeTab {
-			parts = append(parts, num+styles.Title.Render(label))
+			parts = append(parts, num+styles.Title.Render(label)+badge)
 		} else {
-			parts = append(parts, num+styles.Muted.Render(label))
+			parts = append(parts, num+styles.Muted.Render(label)+badge)
 		}
 	}
 
 	tabLine := strings.Join(parts, "  ")
-	hint := styles.Muted.Render("Alt+1-4 to switch tabs")
+	hint := styles.Muted.Render("Alt+1-5 to switch tabs")
 
 	return tabLine + "\n" + hint
 }
@@ -2814,7 +3709,11 @@ func (m Model) renderSearchTab(height int) string {
 
 	// Search bar
 	prompt := styles.SearchPrompt.Render("Search: ")
-	b.WriteString(prompt + m.searchInput.View())
+	if m.histSearching {
+		b.WriteString(prompt + m.renderHistorySearch())
+	} else {
+		b.WriteString(prompt + m.searchInput.View())
+	}
 	b.WriteString("\n")
 
 	switch m.mode {
@@ -2822,9 +3721,20 @@ func (m Model) renderSearchTab(height int) string {
 		if m.err != nil {
 			b.WriteString(styles.Error.Render(fmt.Sprintf("Error: %v", m.err)))
 		} else if m.searching {
-			b.WriteString(m.spinner.View() + " Searching...")
+			b.WriteString(m.spinner.View() + " Searching...\n")
+			b.WriteString(m.searchStatusLine())
 		}
 	case viewResults, viewDetails:
+		if len(m.searchSources) > 0 {
+			b.WriteString(m.searchStatusLine())
+			b.WriteString("\n")
+			height--
+		}
+		if line := m.renderFilterBar(); line != "" {
+			b.WriteString(line)
+			b.WriteString("\n")
+			height--
+		}
 		b.WriteString(m.renderResults(height - 1))
 	}
 
@@ -2854,6 +3764,8 @@ func sortTorrents(torrents []qbit.TorrentInfo, col int, asc bool) {
 			etaI := calcETA(torrents[i].AmountLeft, torrents[i].DLSpeed)
 			etaJ := calcETA(torrents[j].AmountLeft, torrents[j].DLSpeed)
 			less = etaI < etaJ
+		case dlSortQueue: // Queue position
+			less = queueLess(torrents[i], torrents[j])
 		default:
 			less = torrents[i].Name < torrents[j].Name
 		}
@@ -2881,21 +3793,31 @@ func findTorrentByHash(torrents []qbit.TorrentInfo, hash string) (int, bool) {
 	return 0, false
 }
 
-// sortSearchResults sorts search results (5 columns: name, size, seeds, leech, health)
-func sortSearchResults(results []scraper.Torrent, col int, asc bool) {
+// sortSearchResults sorts search results (7 columns: name, size, seeds, leech, health, age, score)
+func sortSearchResults(results []scraper.Torrent, col int, asc bool, profile config.QualityProfile) {
+	if col == searchSortScore { // Score, best first when descending
+		quality.Rank(profile, results, time.Now())
+		if asc {
+			slices.Reverse(results)
+		}
+		return
+	}
 	sort.Slice(results, func(i, j int) bool {
 		var less bool
 		switch col {
 		case 0: // Name
 			less = strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
-		case 1: // Size (string comparison - not ideal but works for display)
-			less = results[i].Size < results[j].Size
+		case 1: // Size
+			less = results[i].SizeBytes < results[j].SizeBytes
 		case 2: // Seeds
 			less = results[i].Seeders < results[j].Seeders
 		case 3: // Leech
 			less = results[i].Leechers < results[j].Leechers
 		case 4: // Health
 			less = results[i].Health() < results[j].Health()
+		case searchSortAge: // Age, unknown oldest
+			ti, tj := results[i].PublishedAt, results[j].PublishedAt
+			less = !ti.IsZero() && (tj.IsZero() || ti.After(tj))
 		default:
 			less = results[i].Health() < results[j].Health()
 		}
@@ -2936,21 +3858,25 @@ func (m Model) renderDownloadsTab(height int) string {
 	var b strings.Builder
 
 	if len(m.downloading) == 0 {
-		b.WriteString(styles.Muted.Render("No active downloads"))
+		if m.filter.kind != filterAll {
+			b.WriteString(styles.Muted.Render("No downloads in " + m.filter.label()))
+		} else {
+			b.WriteString(styles.Muted.Render("No active downloads"))
+		}
 		return b.String()
 	}
 
 	// Fixed column widths for right-side columns
-	sizeW, doneW, dlW, ulW, seedW, leechW, etaW := 8, 7, 11, 11, 5, 6, 8
-	rightColsWidth := sizeW + doneW + dlW + ulW + seedW + leechW + etaW + 7 // 7 spaces between
-	nameWidth := m.width - 2 - rightColsWidth                               // 2 for prefix
+	sizeW, doneW, dlW, ulW, seedW, leechW, etaW, queueW := 8, 7, 11, 11, 5, 6, 8, 4
+	rightColsWidth := sizeW + doneW + dlW + ulW + seedW + leechW + etaW + queueW + 8 // 8 spaces between
+	nameWidth := m.width - 2 - rightColsWidth                                        // 2 for prefix
 	if nameWidth < 20 {
 		nameWidth = 20
 	}
 
 	// Build header with per-column styling
-	colNames := []string{"NAME", "SIZE", "DONE", "DL", "UL", "SEED", "LEECH", "ETA"}
-	colWidths := []int{nameWidth, sizeW, doneW, dlW, ulW, seedW, leechW, etaW}
+	colNames := []string{"NAME", "SIZE", "DONE", "DL", "UL", "SEED", "LEECH", "ETA", "#"}
+	colWidths := []int{nameWidth, sizeW, doneW, dlW, ulW, seedW, leechW, etaW, queueW}
 
 	var headerRow strings.Builder
 	headerRow.WriteString("  ") // prefix
@@ -3025,18 +3951,25 @@ func (m Model) renderDownloadsTab(height int) string {
 			" " + PadLeft(ulSpeed, ulW) +
 			" " + PadLeft(seeds, seedW) +
 			" " + PadLeft(leechers, leechW) +
-			" " + PadLeft(eta, etaW)
+			" " + PadLeft(eta, etaW) +
+			" " + PadLeft(queueLabel(t), queueW)
 
+		mark := " "
+		if m.isMarked(i, t) {
+			mark = "*"
+		}
 		isFollowing := m.followingHash == t.Hash
 		if i == m.dlCursor {
 			if isFollowing {
-				b.WriteString(styles.VPNConnected.Render("◉ ") + styles.TableSelected.Render(row))
+				b.WriteString(styles.VPNConnected.Render("◉"+mark) + styles.TableSelected.Render(row))
 			} else {
-				b.WriteString(styles.TableSelected.Render("› " + row))
+				b.WriteString(styles.TableSelected.Render("›" + mark + row))
 			}
 		} else {
 			if isFollowing {
-				b.WriteString(styles.VPNConnected.Render("◉ ") + styles.TableRow.Render(row))
+				b.WriteString(styles.VPNConnected.Render("◉"+mark) + styles.TableRow.Render(row))
+			} else if mark != " " {
+				b.WriteString(styles.HealthMed.Render(" "+mark) + styles.TableRow.Render(row))
 			} else {
 				b.WriteString(styles.TableRow.Render("  " + row))
 			}
@@ -3052,20 +3985,31 @@ func (m Model) renderCompletedTab(height int) string {
 	var b strings.Builder
 
 	if len(m.completed) == 0 {
-		b.WriteString(styles.Muted.Render("No completed torrents"))
+		if m.filter.kind != filterAll {
+			b.WriteString(styles.Muted.Render("No completed torrents in " + m.filter.label()))
+		} else {
+			b.WriteString(styles.Muted.Render("No completed torrents"))
+		}
 		return b.String()
 	}
 
 	// Column widths - must match row widths exactly
 	// Rows have 2-char prefix ("› " or "  "), so header needs it too
-	colWidths := []int{0, 8, 7, 11}           // nameWidth set below, others fixed
-	nameWidth := m.width - 2 - 8 - 7 - 11 - 3 // 2=prefix, 3=spaces between cols
+	colWidths := []int{0, 8, 7, 11, 6}            // nameWidth set below, others fixed
+	nameWidth := m.width - 2 - 8 - 7 - 11 - 6 - 4 // 2=prefix, 4=spaces between cols
 	if nameWidth < 20 {
 		nameWidth = 20
 	}
 	colWidths[0] = nameWidth
 
-	colNames := []string{"NAME", "SIZE", "RATIO", "UPLOADED"}
+	colNames := []string{"NAME", "SIZE", "RATIO", "UPLOADED", "GOAL"}
+
+	// Review queue of the post-completion pipeline
+	if m.reviewCount() > 0 {
+		b.WriteString(m.renderReviewLine())
+		b.WriteString("\n")
+		height--
+	}
 
 	// Build header with sort indicator - sorted column gets highlighted
 	var headerParts []string
@@ -3137,20 +4081,31 @@ func (m Model) renderCompletedTab(height int) string {
 	for i := startIdx; i < endIdx; i++ {
 		t := m.completed[i]
 		name := TruncateString(t.Name, nameWidth-2) // -2 for "› " prefix
+		if m.pipeline != nil && m.pipeline.InReview(t.Hash) {
+			name = "? " + TruncateString(t.Name, nameWidth-4)
+		}
 		size := formatSize(t.Size)
 		ratio := fmt.Sprintf("%.2f", float64(t.UploadedEver)/float64(t.Size))
 		uploaded := formatSize(t.UploadedEver)
+		goal := m.seedingGoal(t)
 
-		// Match header widths exactly: nameWidth, 8, 7, 11
-		// All left-aligned except UPLOADED (right-aligned)
-		row := fmt.Sprintf("%s %s %s %s",
+		// Match header widths exactly: nameWidth, 8, 7, 11, 6
+		// All left-aligned except UPLOADED and GOAL (right-aligned)
+		row := fmt.Sprintf("%s %s %s %s %s",
 			PadRight(name, nameWidth),
 			PadRight(size, 8),
 			PadRight(ratio, 7),
-			PadLeft(uploaded, 11))
+			PadLeft(uploaded, 11),
+			PadLeft(goal, 6))
 
+		mark := " "
+		if m.isMarked(i, t) {
+			mark = "*"
+		}
 		if i == m.dlCursor {
-			b.WriteString(styles.TableSelected.Render("› " + row))
+			b.WriteString(styles.TableSelected.Render("›" + mark + row))
+		} else if mark != " " {
+			b.WriteString(styles.HealthMed.Render(" "+mark) + styles.TableRow.Render(row))
 		} else {
 			b.WriteString(styles.TableRow.Render("  " + row))
 		}
@@ -3177,14 +4132,23 @@ func (m Model) renderSourcesTab(height int) string {
 		}
 		b.WriteString(styles.SearchPrompt.Render("Validating") + dots[0] + dots[1] + dots[2])
 		b.WriteString("\n\n")
+	} else if m.testingProfile {
+		prompt := styles.SearchPrompt.Render("Test profile with: ")
+		b.WriteString(prompt + m.profileInput.View())
+		b.WriteString("\n\n")
 	} else if m.addingURL {
-		prompt := styles.SearchPrompt.Render("Add URL: ")
+		kind := "Site"
+		if m.addingType == config.SourceTypeTorznab {
+			kind = "Torznab"
+		}
+		prompt := styles.SearchPrompt.Render("Add " + kind + " URL: ")
 		b.WriteString(prompt + m.urlInput.View())
+		b.WriteString("  " + styles.Muted.Render("[tab]Type"))
 		b.WriteString("\n\n")
 	} else {
 		b.WriteString(styles.PanelTitle.Render("Search Sources"))
 		b.WriteString("  ")
-		b.WriteString(styles.Muted.Render("[a]Add URL  [enter]Toggle  [x]Remove"))
+		b.WriteString(styles.Muted.Render("[a]Add URL  [enter]Toggle  [t]Test profile  [x]Remove"))
 		b.WriteString("\n\n")
 	}
 
@@ -3194,15 +4158,16 @@ func (m Model) renderSourcesTab(height int) string {
 	}
 
 	// Column widths
-	statusWidth := 12
-	nameWidth := m.width - statusWidth - 6 // 2=prefix, 4=spacing
+	statusWidth, typeWidth := 12, 8
+	nameWidth := m.width - statusWidth - typeWidth - 7 // 2=prefix, 5=spacing
 	if nameWidth < 20 {
 		nameWidth = 20
 	}
 
 	// Header with border style like other tables
-	header := fmt.Sprintf("  %s %s",
+	header := fmt.Sprintf("  %s %s %s",
 		PadRight("SOURCE", nameWidth),
+		PadRight("TYPE", typeWidth),
 		PadLeft("STATUS", statusWidth))
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -3233,7 +4198,7 @@ func (m Model) renderSourcesTab(height int) string {
 
 		// Show warning indicator if source has issues
 		name := src.Name
-		if src.Warning != "" {
+		if src.Warning != "" || src.ProfileErr != nil {
 			name = "⚠ " + name
 		}
 		name = TruncateString(name, nameWidth-2)
@@ -3243,7 +4208,7 @@ func (m Model) renderSourcesTab(height int) string {
 		if !src.Enabled {
 			status = "Disabled"
 			statusStyled = styles.Muted.Render(PadLeft(status, statusWidth))
-		} else if src.Warning != "" {
+		} else if src.Warning != "" || src.ProfileErr != nil {
 			status = "Warning"
 			statusStyled = styles.HealthMed.Render(PadLeft(status, statusWidth))
 		} else {
@@ -3251,8 +4216,14 @@ func (m Model) renderSourcesTab(height int) string {
 			statusStyled = styles.VPNConnected.Render(PadLeft(status, statusWidth))
 		}
 
-		// Build row: prefix + padded name + space + styled status
-		namePadded := PadRight(name, nameWidth)
+		// Build row: prefix + padded name + type + space + styled status
+		typ := "site"
+		if src.Type == config.SourceTypeTorznab {
+			typ = "torznab"
+		} else if src.Profile != nil {
+			typ = "profile"
+		}
+		namePadded := PadRight(name, nameWidth) + " " + PadRight(typ, typeWidth)
 		if i == m.srcCursor {
 			b.WriteString(styles.TableSelected.Render("› "+namePadded+" ") + statusStyled)
 		} else {
@@ -3264,10 +4235,25 @@ func (m Model) renderSourcesTab(height int) string {
 	return b.String()
 }
 
+// resultKey identifies a search result in the downloaded set: its infohash
+// if known, otherwise its name
+func resultKey(t scraper.Torrent) string {
+	if t.InfoHash != "" {
+		return t.InfoHash
+	}
+	if hash := ExtractInfohash(t.Magnet); hash != "" {
+		return hash
+	}
+	return t.Name
+}
+
 func (m Model) renderResults(height int) string {
 	styles := GetStyles()
 
 	if len(m.results) == 0 {
+		if len(m.searchAll) > 0 {
+			return styles.Muted.Render("No results match the filters ([a]Age [z]Size)")
+		}
 		return styles.Muted.Render("No results")
 	}
 
@@ -3275,14 +4261,16 @@ func (m Model) renderResults(height int) string {
 
 	// Column widths - must match row widths exactly
 	// Rows have 2-char prefix ("› " or "  "), so header needs it too
-	colWidths := []int{0, 10, 6, 6, 6}                // nameWidth set below, others fixed
-	nameWidth := m.width - 2 - 10 - 6 - 6 - 6 - 4 - 2 // 2=prefix, 4=spaces between cols, 2=margin
+	colWidths := []int{0, 10, 6, 6, 6, 5, 6, 10, 5, 8, 4}                       // nameWidth set below, others fixed
+	nameWidth := m.width - 2 - 10 - 6 - 6 - 6 - 5 - 6 - 10 - 5 - 8 - 4 - 10 - 2 // 2=prefix, 10=spaces between cols, 2=margin
 	if nameWidth < 20 {
 		nameWidth = 20
 	}
 	colWidths[0] = nameWidth
 
-	colNames := []string{"NAME", "SIZE", "SEED", "LEECH", "HEALTH"}
+	// EP, RES and QUALITY (parsed from the name) and SRC (number of sources
+	// listing the torrent) are not sortable
+	colNames := []string{"NAME", "SIZE", "SEED", "LEECH", "HEALTH", "AGE", "SCORE", "EP", "RES", "QUALITY", "SRC"}
 
 	// Build header with sort indicator - sorted column gets highlighted
 	var headerParts []string
@@ -3339,6 +4327,13 @@ func (m Model) renderResults(height int) string {
 		visibleRows = 1
 	}
 
+	// An expanded row lists each source below it
+	var listings []scraper.Torrent
+	if m.resultsExpanded && m.cursor < len(m.results) {
+		listings = m.results[m.cursor].Listings
+		visibleRows = max(visibleRows-len(listings), 1)
+	}
+
 	startIdx := 0
 	if m.cursor >= visibleRows {
 		startIdx = m.cursor - visibleRows + 1
@@ -3350,24 +4345,34 @@ func (m Model) renderResults(height int) string {
 	}
 
 	// Render rows
+	now := time.Now()
+	profile := m.qualityProfile()
 	for i := startIdx; i < endIdx; i++ {
 		t := m.results[i]
 		name := TruncateString(t.Name, nameWidth-2) // -2 for "› " prefix
 
+		rel := release.Parse(t.Name)
+		score := "-"
+		if m.hasQualityProfile() {
+			score = resultScore(quality.Evaluate(profile, t, now))
+		}
+
 		// Match header widths exactly
-		row := fmt.Sprintf("%s %s %s %s %s",
+		row := fmt.Sprintf("%s %s %s %s %s %s %s %s %s %s %s",
 			PadRight(name, nameWidth),
 			PadLeft(t.Size, 10),
 			PadLeft(fmt.Sprintf("%d", t.Seeders), 6),
 			PadLeft(fmt.Sprintf("%d", t.Leechers), 6),
-			HealthBar(t.Health(), 6))
+			HealthBar(t.HealthAt(now), 6),
+			PadLeft(resultAge(t, now), 5),
+			PadLeft(score, 6),
+			PadLeft(orDash(TruncateString(rel.EpisodeString(), 10)), 10),
+			PadLeft(orDash(rel.Resolution), 5),
+			PadLeft(orDash(rel.SourceLabel()), 8),
+			PadLeft(fmt.Sprintf("%d", len(t.Sources())), 4))
 
 		// Check if this item has been downloaded (by infohash if available)
-		key := ExtractInfohash(t.Magnet)
-		if key == "" {
-			key = t.Name // fallback
-		}
-		isDownloaded := m.downloaded[key]
+		isDownloaded := m.downloaded[resultKey(t)]
 
 		if i == m.cursor {
 			if isDownloaded {
@@ -3383,11 +4388,30 @@ func (m Model) renderResults(height int) string {
 			}
 		}
 		b.WriteString("\n")
+
+		if i == m.cursor {
+			for _, l := range listings {
+				line := fmt.Sprintf("    ↳ %s  %d seeds  %d leech  %s", l.Source, l.Seeders, l.Leechers, l.InfoURL)
+				b.WriteString(styles.Muted.Render(TruncateString(line, m.width-2)))
+				b.WriteString("\n")
+			}
+		}
 	}
 
 	// Files panel (if in details mode and files loaded)
 	if m.mode == viewDetails && m.cursor < len(m.results) {
 		t := m.results[m.cursor]
+		var lines []string
+		for _, line := range []string{resultRelease(t), m.resultScoreReason(t), resultMetadata(t)} {
+			if line != "" {
+				lines = append(lines, styles.Muted.Render(TruncateString("  "+line, m.width-2)))
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n")
+			b.WriteString(strings.Join(lines, "\n"))
+			b.WriteString("\n")
+		}
 		if len(t.Files) > 0 {
 			b.WriteString("\n")
 			b.WriteString(styles.PanelTitle.Render(fmt.Sprintf("FILES (%d)", len(t.Files))))
@@ -3432,34 +4456,55 @@ func (m Model) renderStatusBar() string {
 	var modeStr string
 	if m.searchInput.Focused() {
 		modeStr = styles.VPNConnected.Render("INPUT")
+	} else if m.visualAnchor != "" && (m.activeTab == tabDownloads || m.activeTab == tabCompleted) {
+		modeStr = styles.SortedHeader.Render("VISUAL")
 	} else {
 		modeStr = styles.HealthMed.Render("CMD")
 	}
 
 	// Context-sensitive help (mode + tab aware)
 	var help string
-	if m.searchInput.Focused() {
-		help = "[esc]CMD [ctrl+u]Clear [enter]Search"
-	} else if m.addingURL {
+	if m.confirmingDelete {
+		help = "[y]Delete [n]Cancel"
+	} else if m.histSearching {
+		help = "[ctrl+r]Older [enter]Use [esc]Cancel"
+	} else if m.searchInput.Focused() {
+		help = "[esc]CMD [↑↓]History [ctrl+r]Find [ctrl+u]Clear [enter]Search"
+	} else if m.addingURL || m.addingFeed {
 		help = "[esc]Cancel [enter]Add"
+	} else if m.testingProfile {
+		help = "[esc]Cancel [enter]Test"
+	} else if m.filterFocus {
+		help = "[←→]Move [space]On/off [x]Remove [esc]Done"
+	} else if m.detailOpen() {
+		help = "[tab/←→]Section [↑↓]Scroll [p]Pause [r]Recheck [C]Category [T]Tags [esc]Back"
 	} else {
 		switch m.activeTab {
 		case tabDownloads:
-			help = "[←→]Sort [s]Toggle [f]Follow [p]Pause [x]Remove [q]Quit"
+			help = "[enter]Details [space]Mark [M]Range [p]Pause [+/-]Queue [F]Force [x]Remove [[ ]]Filter [C]Category [T]Tags [L]Limits [A]Add [q]Quit"
 		case tabCompleted:
-			help = "[←→]Sort col [s]Toggle sort [m]Plex [x]Remove [q]Quit"
+			help = "[enter]Details [space]Mark [M]Range [m]Plex [r]Recheck [x]Remove [[ ]]Filter [C]Category [T]Tags [q]Quit"
+			if m.reviewCount() > 0 {
+				help = "[enter]Details [space]Mark [m]Plex [D]Dismiss review [r]Recheck [x]Remove [[ ]]Filter [C]Category [T]Tags [q]Quit"
+			}
 		case tabSources:
-			help = "[a]Add [enter]Toggle [x]Remove [q]Quit"
+			help = "[a]Add [enter]Toggle [t]Test profile [x]Remove [q]Quit"
+		case tabFeeds:
+			help = "[a]Add feed [n]New rule [enter]Edit [space]Toggle [r]Poll [x]Remove [q]Quit"
 		default:
 			if m.mode == viewResults || m.mode == viewDetails {
-				help = "[←→]Sort [s]Toggle [enter]Download [d]Details [c]Config [q]Quit"
+				help = "[←→]Sort [s]Toggle [enter]Download [d]Details [e]Expand [f]Filters [a]Age [z]Size [P]Profile [g]Grab best [w]Save [W]Saved [c]Config [q]Quit"
 			} else {
-				help = "[/]Search [v]VPN [c]Config [q]Quit"
+				help = "[/]Search [W]Saved [A]Add [v]VPN [L]Limits [S]Alt speed [c]Config [q]Quit"
 			}
 		}
 	}
 
 	// Left side: mode + status message
+	if n := m.selectionCount(); n > 0 {
+		modeStr += " " + styles.HealthMed.Render(fmt.Sprintf("%d selected", n))
+	}
+
 	var leftPart string
 	if m.statusMsg != "" {
 		leftPart = modeStr + " │ " + m.statusMsg
@@ -3469,6 +4514,18 @@ func (m Model) renderStatusBar() string {
 
 	// Right side: connection status
 	rightLine1 := qbitStr + "  " + vpnStr
+	if m.qbitOnline && m.serverState.FreeSpaceOnDisk > 0 {
+		free := styles.Muted.Render("Free " + formatSize(m.serverState.FreeSpaceOnDisk))
+		rightLine1 = free + "  " + rightLine1
+	}
+	if m.qbitOnline {
+		rates := styles.Muted.Render(fmt.Sprintf("↓ %s ↑ %s",
+			formatSpeed(m.serverState.DLInfoSpeed), formatSpeed(m.serverState.UPInfoSpeed)))
+		if m.serverState.UseAltSpeedLimits {
+			rates = styles.HealthMed.Render("ALT") + " " + rates
+		}
+		rightLine1 = rates + "  " + rightLine1
+	}
 
 	// Line 2: context-sensitive shortcuts (right-justified)
 	rightLine2 := styles.HelpKey.Render(help)
@@ -0,0 +1,539 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// Sections of the torrent detail pane
+const (
+	detailGeneral = iota
+	detailFiles
+	detailTrackers
+	detailPeers
+	detailSectionCount
+)
+
+var detailSectionNames = [detailSectionCount]string{"General", "Files", "Trackers", "Peers"}
+
+// torrentDetail is everything the detail pane shows for one torrent
+type torrentDetail struct {
+	props    *qbit.TorrentProperties
+	files    []qbit.TorrentFile
+	trackers []qbit.Tracker
+	peers    []qbit.Peer
+	pieces   []int
+}
+
+type torrentDetailMsg struct {
+	hash   string
+	detail torrentDetail
+	err    error
+}
+
+// openDetail opens the detail pane for the torrent under the cursor
+func (m Model) openDetail() (tea.Model, tea.Cmd) {
+	t, ok := m.selectedTorrent()
+	if !ok {
+		return m, handled()
+	}
+	m.detailHash = t.Hash
+	m.detailSection = detailGeneral
+	m.detailScroll = 0
+	m.detailFileCursor = 0
+	m.detail = torrentDetail{}
+	m.detailErr = nil
+	m.detailPeers = qbit.NewPeerSync(m.qbitClient, t.Hash)
+	m.detailFetching = true
+	return m, m.fetchDetail()
+}
+
+// closeDetail returns to the torrent list
+func (m *Model) closeDetail() {
+	m.detailHash = ""
+	m.detailPeers = nil
+	m.detail = torrentDetail{}
+}
+
+// detailOpen reports whether the detail pane is showing
+func (m Model) detailOpen() bool {
+	return m.detailHash != "" && (m.activeTab == tabDownloads || m.activeTab == tabCompleted)
+}
+
+// fetchDetail loads properties, files, trackers, peers and pieces for the
+// detail pane's torrent
+func (m Model) fetchDetail() tea.Cmd {
+	client := m.qbitClient
+	peers := m.detailPeers
+	hash := m.detailHash
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var d torrentDetail
+		var err error
+		if d.props, err = client.Properties(ctx, hash); err != nil {
+			return torrentDetailMsg{hash: hash, err: err}
+		}
+		if d.files, err = client.Files(ctx, hash); err != nil {
+			return torrentDetailMsg{hash: hash, err: err}
+		}
+		if d.trackers, err = client.Trackers(ctx, hash); err != nil {
+			return torrentDetailMsg{hash: hash, err: err}
+		}
+		if d.pieces, err = client.PieceStates(ctx, hash); err != nil {
+			return torrentDetailMsg{hash: hash, err: err}
+		}
+		if err = peers.Update(ctx); err != nil {
+			return torrentDetailMsg{hash: hash, err: err}
+		}
+		d.peers = peers.Peers()
+		return torrentDetailMsg{hash: hash, detail: d}
+	}
+}
+
+// handleDetailKey handles navigation keys while the detail pane is open
+func (m Model) handleDetailKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "esc", "enter", "backspace":
+		m.closeDetail()
+
+	case "tab", "right", "l":
+		m.detailSection = (m.detailSection + 1) % detailSectionCount
+		m.detailScroll = 0
+		m.detailFileCursor = 0
+
+	case "shift+tab", "left", "h":
+		m.detailSection = (m.detailSection + detailSectionCount - 1) % detailSectionCount
+		m.detailScroll = 0
+		m.detailFileCursor = 0
+
+	case "down", "j":
+		if m.detailSection == detailFiles {
+			if m.detailFileCursor < len(buildFileTree(m.detail.files))-1 {
+				m.detailFileCursor++
+			}
+			m.scrollToFileCursor()
+		} else if m.detailScroll < len(m.detailLines(m.width))-1 {
+			m.detailScroll++
+		}
+
+	case "up", "k":
+		if m.detailSection == detailFiles {
+			if m.detailFileCursor > 0 {
+				m.detailFileCursor--
+			}
+			m.scrollToFileCursor()
+		} else if m.detailScroll > 0 {
+			m.detailScroll--
+		}
+
+	case " ", "space", "+", "=", "-":
+		// Change priority of the file (or directory) under the cursor
+		if m.detailSection != detailFiles {
+			break
+		}
+		delta := 1
+		if key == "-" {
+			delta = -1
+		}
+		// Copy so the update doesn't leak into older models
+		files := make([]qbit.TorrentFile, len(m.detail.files))
+		copy(files, m.detail.files)
+		indexes, prio := changeRowPriority(files, m.detailFileCursor, key == " " || key == "space", delta)
+		if len(indexes) == 0 {
+			break
+		}
+		m.detail.files = files
+		return m, m.setFilePriority(m.detailHash, indexes, prio)
+
+	case "pgdown":
+		m.detailScroll = min(m.detailScroll+10, max(len(m.detailLines(m.width))-1, 0))
+
+	case "pgup":
+		m.detailScroll = max(m.detailScroll-10, 0)
+	}
+	return m, handled()
+}
+
+// detailVisibleRows returns how many section lines fit in the detail pane
+func (m Model) detailVisibleRows() int {
+	return max(max(m.height-18, 5)-5, 1)
+}
+
+// scrollToFileCursor keeps the file cursor inside the visible rows
+func (m *Model) scrollToFileCursor() {
+	visible := m.detailVisibleRows()
+	if m.detailFileCursor < m.detailScroll {
+		m.detailScroll = m.detailFileCursor
+	} else if m.detailFileCursor >= m.detailScroll+visible {
+		m.detailScroll = m.detailFileCursor - visible + 1
+	}
+}
+
+// renderDetailPane renders the detail view for the selected torrent
+func (m Model) renderDetailPane(height int) string {
+	styles := GetStyles()
+	var b strings.Builder
+
+	t, _ := findTorrentInfo(m.allTorrents(), m.detailHash)
+	b.WriteString(styles.Title.Render(TruncateString(t.Name, m.width-30)))
+	b.WriteString(styles.Muted.Render(fmt.Sprintf("  %s  %.1f%%", t.State, t.Progress*100)))
+	b.WriteString("\n")
+
+	// Piece availability bar
+	pieces := "Pieces"
+	if p := m.detail.props; p != nil {
+		pieces = fmt.Sprintf("Pieces %d/%d × %s", p.PiecesHave, p.PiecesNum, formatSize(p.PieceSize))
+	}
+	b.WriteString(styles.Muted.Render(PadRight(pieces, 28)))
+	b.WriteString(renderPieceBar(m.detail.pieces, max(m.width-32, 10)))
+	b.WriteString("\n\n")
+
+	// Section tabs
+	for i, name := range detailSectionNames {
+		if i == m.detailSection {
+			b.WriteString(styles.SortedHeader.Render(" " + name + " "))
+		} else {
+			b.WriteString(styles.Muted.Render(" " + name + " "))
+		}
+	}
+	b.WriteString("\n\n")
+
+	if m.detailErr != nil {
+		b.WriteString(styles.Error.Render("Error: " + m.detailErr.Error()))
+		return b.String()
+	}
+	if m.detail.props == nil {
+		b.WriteString(m.spinner.View() + " Loading...")
+		return b.String()
+	}
+
+	lines := m.detailLines(m.width)
+	visible := m.detailVisibleRows()
+	start := min(m.detailScroll, max(len(lines)-visible, 0))
+	end := min(start+visible, len(lines))
+	b.WriteString(strings.Join(lines[start:end], "\n"))
+
+	return b.String()
+}
+
+// findTorrentInfo looks up a torrent by hash
+func findTorrentInfo(torrents []qbit.TorrentInfo, hash string) (qbit.TorrentInfo, bool) {
+	if idx, ok := findTorrentByHash(torrents, hash); ok {
+		return torrents[idx], true
+	}
+	return qbit.TorrentInfo{}, false
+}
+
+// detailLines returns the body of the current detail section, one entry per line
+func (m Model) detailLines(width int) []string {
+	switch m.detailSection {
+	case detailFiles:
+		return renderFileTree(m.detail.files, width, m.detailFileCursor)
+	case detailTrackers:
+		return renderTrackers(m.detail.trackers, width)
+	case detailPeers:
+		return renderPeers(m.detail.peers, width)
+	}
+	return m.renderGeneral()
+}
+
+// renderGeneral renders the General section
+func (m Model) renderGeneral() []string {
+	styles := GetStyles()
+	p := m.detail.props
+	if p == nil {
+		return nil
+	}
+
+	limit := func(v int64) string {
+		if v <= 0 {
+			return "∞"
+		}
+		return formatSpeed(v)
+	}
+	date := func(unix int64) string {
+		if unix <= 0 {
+			return "-"
+		}
+		return time.Unix(unix, 0).Format("2006-01-02 15:04")
+	}
+
+	t, _ := findTorrentInfo(m.allTorrents(), m.detailHash)
+	rows := [][2]string{
+		{"Save path", p.SavePath},
+		{"Size", formatSize(p.TotalSize)},
+		{"Category", t.Category},
+		{"Tags", t.Tags},
+		{"Downloaded", fmt.Sprintf("%s (avg %s)", formatSize(p.TotalDownloaded), formatSpeed(p.DlSpeedAvg))},
+		{"Uploaded", fmt.Sprintf("%s (avg %s)", formatSize(p.TotalUploaded), formatSpeed(p.UpSpeedAvg))},
+		{"Ratio", fmt.Sprintf("%.2f", p.ShareRatio)},
+		{"Wasted", formatSize(p.TotalWasted)},
+		{"Limits", fmt.Sprintf("DL %s  UL %s", limit(p.DlLimit), limit(p.UpLimit))},
+		{"Seeds", fmt.Sprintf("%d (%d total)", p.Seeds, p.SeedsTotal)},
+		{"Peers", fmt.Sprintf("%d (%d total)", p.Peers, p.PeersTotal)},
+		{"Connections", fmt.Sprintf("%d (%d max)", p.NbConnections, p.NbConnectionsLimit)},
+		{"Time active", formatDuration(p.TimeElapsed)},
+		{"Seeding time", formatDuration(p.SeedingTime)},
+		{"Reannounce in", formatDuration(p.Reannounce)},
+		{"Added", date(p.AdditionDate)},
+		{"Completed", date(p.CompletionDate)},
+		{"Created", date(p.CreationDate)},
+		{"Created by", p.CreatedBy},
+		{"Comment", p.Comment},
+		{"Hash", m.detailHash},
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row[1] == "" {
+			row[1] = "-"
+		}
+		lines = append(lines, styles.Muted.Render(PadRight(row[0]+":", 16))+row[1])
+	}
+	return lines
+}
+
+// fileTreeRow is one line of the flattened file tree
+type fileTreeRow struct {
+	depth    int
+	name     string
+	path     string // Full path within the torrent
+	isDir    bool
+	index    int // File index, -1 for directories
+	size     int64
+	progress float64
+	priority int
+}
+
+// buildFileTree flattens torrent files into a tree with directory rows.
+// Directory size is the sum of its files and progress is size-weighted.
+func buildFileTree(files []qbit.TorrentFile) []fileTreeRow {
+	sorted := make([]qbit.TorrentFile, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	// Aggregate sizes and completed bytes per directory
+	dirSize := make(map[string]int64)
+	dirDone := make(map[string]float64)
+	for _, f := range sorted {
+		for dir := path.Dir(f.Name); dir != "."; dir = path.Dir(dir) {
+			dirSize[dir] += f.Size
+			dirDone[dir] += float64(f.Size) * f.Progress
+		}
+	}
+
+	var rows []fileTreeRow
+	emitted := make(map[string]bool)
+	for _, f := range sorted {
+		parts := strings.Split(f.Name, "/")
+		for depth := 1; depth < len(parts); depth++ {
+			dir := strings.Join(parts[:depth], "/")
+			if emitted[dir] {
+				continue
+			}
+			emitted[dir] = true
+			progress := 1.0
+			if dirSize[dir] > 0 {
+				progress = dirDone[dir] / float64(dirSize[dir])
+			}
+			rows = append(rows, fileTreeRow{
+				depth:    depth - 1,
+				name:     parts[depth-1],
+				path:     dir,
+				isDir:    true,
+				index:    -1,
+				size:     dirSize[dir],
+				progress: progress,
+			})
+		}
+		rows = append(rows, fileTreeRow{
+			depth:    len(parts) - 1,
+			name:     parts[len(parts)-1],
+			path:     f.Name,
+			index:    f.Index,
+			size:     f.Size,
+			progress: f.Progress,
+			priority: f.Priority,
+		})
+	}
+	return rows
+}
+
+// filePriorityLabel returns the display name of a qBittorrent file priority
+func filePriorityLabel(priority int) string {
+	switch {
+	case priority == 0:
+		return "Skip"
+	case priority >= 7:
+		return "Max"
+	case priority >= 6:
+		return "High"
+	}
+	return "Normal"
+}
+
+// renderFileTree renders a file tree with per-file progress and priority,
+// highlighting the row at cursor
+func renderFileTree(files []qbit.TorrentFile, width, cursor int) []string {
+	styles := GetStyles()
+	if len(files) == 0 {
+		return []string{styles.Muted.Render("No files (metadata not downloaded yet?)")}
+	}
+
+	sizeW, pctW, prioW := 10, 7, 7
+	nameW := max(width-sizeW-pctW-prioW-6, 20) // 2 for cursor prefix
+
+	var lines []string
+	for i, row := range buildFileTree(files) {
+		prio := rowPriority(files, row)
+		name := strings.Repeat("  ", row.depth) + row.name
+		if row.isDir {
+			name = strings.Repeat("  ", row.depth) + "▾ " + row.name + "/"
+		}
+		line := PadRight(TruncateString(name, nameW-1), nameW) +
+			" " + PadLeft(formatSize(row.size), sizeW) +
+			" " + PadLeft(fmt.Sprintf("%.1f%%", row.progress*100), pctW) +
+			" " + PadLeft(filePriorityLabel(prio), prioW)
+		switch {
+		case i == cursor:
+			lines = append(lines, styles.TableSelected.Render("› "+line))
+		case prio == qbit.FilePrioritySkip:
+			lines = append(lines, styles.Muted.Render("  "+line))
+		case row.isDir:
+			lines = append(lines, styles.HelpDesc.Render("  "+line))
+		default:
+			lines = append(lines, styles.TableRow.Render("  "+line))
+		}
+	}
+	return lines
+}
+
+// renderTrackers renders the Trackers section
+func renderTrackers(trackers []qbit.Tracker, width int) []string {
+	styles := GetStyles()
+	if len(trackers) == 0 {
+		return []string{styles.Muted.Render("No trackers")}
+	}
+
+	statusW, countW := 14, 20
+	urlW := max(width-statusW-countW-2, 20)
+
+	var lines []string
+	for _, tr := range trackers {
+		status := PadRight(tr.StatusString(), statusW)
+		switch tr.Status {
+		case qbit.TrackerWorking:
+			status = styles.HealthGood.Render(status)
+		case qbit.TrackerNotWorking:
+			status = styles.HealthBad.Render(status)
+		default:
+			status = styles.Muted.Render(status)
+		}
+		counts := fmt.Sprintf("S:%d L:%d P:%d", tr.NumSeeds, tr.NumLeeches, tr.NumPeers)
+		lines = append(lines, status+" "+PadRight(counts, countW)+" "+TruncateString(tr.URL, urlW))
+		if tr.Msg != "" {
+			lines = append(lines, styles.Muted.Render(strings.Repeat(" ", statusW+1)+"└ "+TruncateString(tr.Msg, width-statusW-4)))
+		}
+	}
+	return lines
+}
+
+// renderPeers renders the Peers section
+func renderPeers(peers []qbit.Peer, width int) []string {
+	styles := GetStyles()
+	if len(peers) == 0 {
+		return []string{styles.Muted.Render("No connected peers")}
+	}
+
+	addrW, flagsW, pctW, dlW, ulW := 24, 8, 7, 11, 11
+	clientW := max(width-addrW-flagsW-pctW-dlW-ulW-5, 10)
+
+	header := PadRight("ADDRESS", addrW) + " " + PadRight("CLIENT", clientW) + " " +
+		PadRight("FLAGS", flagsW) + " " + PadLeft("DONE", pctW) + " " +
+		PadLeft("DL", dlW) + " " + PadLeft("UL", ulW)
+	lines := []string{styles.Muted.Render(header)}
+
+	for _, p := range peers {
+		line := PadRight(TruncateString(p.Address, addrW-1), addrW) +
+			" " + PadRight(TruncateString(p.Client, clientW-1), clientW) +
+			" " + PadRight(TruncateString(p.Flags, flagsW-1), flagsW) +
+			" " + PadLeft(fmt.Sprintf("%.1f%%", p.Progress*100), pctW) +
+			" " + PadLeft(formatSpeed(p.DLSpeed), dlW) +
+			" " + PadLeft(formatSpeed(p.UPSpeed), ulW)
+		lines = append(lines, styles.TableRow.Render(line))
+	}
+	return lines
+}
+
+// pieceBarCells compresses piece states into width cells. A cell is
+// PieceDownloaded when all its pieces are, PieceDownloading when only some
+// are (or any is in flight), and PieceMissing otherwise.
+func pieceBarCells(pieces []int, width int) []int {
+	if len(pieces) == 0 || width <= 0 {
+		return nil
+	}
+	cells := make([]int, width)
+	n := len(pieces)
+	for c := range cells {
+		lo := c * n / width
+		hi := max((c+1)*n/width, lo+1)
+		have, partial := 0, false
+		for _, state := range pieces[lo:min(hi, n)] {
+			switch state {
+			case qbit.PieceDownloaded:
+				have++
+			case qbit.PieceDownloading:
+				partial = true
+			}
+		}
+		switch {
+		case have == min(hi, n)-lo:
+			cells[c] = qbit.PieceDownloaded
+		case have > 0 || partial:
+			cells[c] = qbit.PieceDownloading
+		default:
+			cells[c] = qbit.PieceMissing
+		}
+	}
+	return cells
+}
+
+// renderPieceBar renders the piece availability bar
+func renderPieceBar(pieces []int, width int) string {
+	styles := GetStyles()
+	cells := pieceBarCells(pieces, width)
+	if cells == nil {
+		return styles.Muted.Render(strings.Repeat("░", width))
+	}
+
+	// Group runs of equal cells to keep the number of styled segments small
+	partial := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.CurrentPalette.Accent))
+	var b strings.Builder
+	for i := 0; i < len(cells); {
+		j := i
+		for j < len(cells) && cells[j] == cells[i] {
+			j++
+		}
+		switch cells[i] {
+		case qbit.PieceDownloaded:
+			b.WriteString(styles.HealthGood.Render(strings.Repeat("█", j-i)))
+		case qbit.PieceDownloading:
+			b.WriteString(partial.Render(strings.Repeat("▒", j-i)))
+		default:
+			b.WriteString(styles.Muted.Render(strings.Repeat("░", j-i)))
+		}
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,696 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/feeds"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// Fields of the feed rule editor
+const (
+	ruleFieldName = iota
+	ruleFieldInclude
+	ruleFieldExclude
+	ruleFieldEpisodes
+	ruleFieldMinSize
+	ruleFieldMaxSize
+	ruleFieldCategory
+	ruleFieldSavePath
+	ruleFieldPaused
+	ruleFieldEnabled
+	ruleFieldCount
+)
+
+var ruleFieldLabels = [ruleFieldCount]string{
+	"Name",
+	"Include (regex)",
+	"Exclude (regex)",
+	"Episodes",
+	"Min Size (MB)",
+	"Max Size (MB)",
+	"Category",
+	"Save Path",
+	"Start Paused",
+	"Enabled",
+}
+
+// feedStatus is the poll state of a feed, keyed by URL in Model.feedState
+type feedStatus struct {
+	items   []feeds.Item // Items of the last successful fetch
+	err     error        // Error of the last poll
+	polled  time.Time    // When the last poll finished
+	polling bool         // Guard against overlapping polls
+}
+
+// feedRow is a row of the Feeds tab: a feed, or one of its rules
+type feedRow struct {
+	feed int
+	rule int // -1 for the feed itself
+}
+
+// feedAddedMsg reports the validation fetch of a new feed
+type feedAddedMsg struct {
+	url  string
+	feed *feeds.Feed
+	err  error
+}
+
+// feedPollMsg reports a feed poll
+type feedPollMsg struct {
+	url    string
+	result feeds.Result
+}
+
+// feedRows flattens the feeds and their rules into the tab's rows
+func (m Model) feedRows() []feedRow {
+	var rows []feedRow
+	for i, f := range m.cfg.Feeds {
+		rows = append(rows, feedRow{feed: i, rule: -1})
+		for j := range f.Rules {
+			rows = append(rows, feedRow{feed: i, rule: j})
+		}
+	}
+	return rows
+}
+
+// selectedFeedRow returns the row under the cursor
+func (m Model) selectedFeedRow() (feedRow, bool) {
+	rows := m.feedRows()
+	if m.feedCursor < 0 || m.feedCursor >= len(rows) {
+		return feedRow{}, false
+	}
+	return rows[m.feedCursor], true
+}
+
+// feedStatusFor returns the poll state of a feed, creating it if needed
+func (m Model) feedStatusFor(feedURL string) *feedStatus {
+	st, ok := m.feedState[feedURL]
+	if !ok {
+		st = &feedStatus{}
+		m.feedState[feedURL] = st
+	}
+	return st
+}
+
+// saveFeeds persists the feed list
+func (m Model) saveFeeds() {
+	_ = config.Save(m.cfg) // Ignore error, it's just persistence
+}
+
+// feedPoller returns a poller adding torrents through the qBittorrent client
+func (m Model) feedPoller() *feeds.Poller {
+	return &feeds.Poller{
+		Adder:    m.qbitClient,
+		History:  m.feedHistory,
+		SavePath: m.cfg.Downloads.Path,
+	}
+}
+
+// pollFeed polls a feed. Disabled feeds are only fetched, for the preview.
+func (m Model) pollFeed(feed config.FeedConfig) tea.Cmd {
+	m.feedStatusFor(feed.URL).polling = true
+	poller := m.feedPoller()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		if !feed.Enabled {
+			f, err := poller.Fetch(ctx, feed.URL)
+			return feedPollMsg{url: feed.URL, result: feeds.Result{Feed: f, Err: err}}
+		}
+		return feedPollMsg{url: feed.URL, result: poller.Poll(ctx, feed)}
+	}
+}
+
+// canAutoAdd reports whether feeds may add torrents right now
+func (m Model) canAutoAdd() bool {
+	return m.qbitOnline && (!m.cfg.VPN.Required || m.vpnStatus.Connected)
+}
+
+// pollDueFeeds polls the enabled feeds whose interval has passed
+func (m Model) pollDueFeeds() tea.Cmd {
+	if !m.canAutoAdd() {
+		return nil
+	}
+	var cmds []tea.Cmd
+	now := time.Now()
+	for _, f := range m.cfg.Feeds {
+		st := m.feedStatusFor(f.URL)
+		if !f.Enabled || st.polling || now.Sub(st.polled) < feeds.Interval(f) {
+			continue
+		}
+		cmds = append(cmds, m.pollFeed(f))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleFeedPoll records a poll result
+func (m *Model) handleFeedPoll(msg feedPollMsg) tea.Cmd {
+	st := m.feedStatusFor(msg.url)
+	st.polling = false
+	st.polled = time.Now()
+	st.err = msg.result.Err
+	if msg.result.Feed != nil {
+		st.items = msg.result.Feed.Items
+	}
+
+	name := msg.url
+	for _, f := range m.cfg.Feeds {
+		if f.URL == msg.url {
+			name = f.Name
+		}
+	}
+	added := msg.result.Added
+	switch {
+	case len(added) == 1:
+		m.statusMsg = fmt.Sprintf("%s: added %s", name, TruncateString(added[0], 40))
+	case len(added) > 1:
+		m.statusMsg = fmt.Sprintf("%s: added %d torrents", name, len(added))
+	case msg.result.Err != nil:
+		m.statusMsg = fmt.Sprintf("%s: %v", name, msg.result.Err)
+	}
+	if len(added) > 0 {
+		return m.fetchTorrents()
+	}
+	return nil
+}
+
+// startAddFeed opens the feed URL prompt
+func (m *Model) startAddFeed() {
+	m.addingFeed = true
+	m.feedInput.SetValue("")
+	m.feedInput.Focus()
+}
+
+// handleAddFeedKey handles keyboard input for the feed URL prompt
+func (m Model) handleAddFeedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.addingFeed = false
+		m.feedInput.Blur()
+		return m, handled()
+	case "enter":
+		feedURL := strings.TrimSpace(m.feedInput.Value())
+		if feedURL == "" {
+			return m, handled()
+		}
+		for _, f := range m.cfg.Feeds {
+			if f.URL == feedURL {
+				m.statusMsg = "Feed already added: " + f.Name
+				return m, handled()
+			}
+		}
+		m.feedInput.Blur()
+		m.validatingURL = true
+		return m, tea.Batch(m.spinner.Tick, m.validateFeed(feedURL))
+	}
+
+	var cmd tea.Cmd
+	m.feedInput, cmd = m.feedInput.Update(msg)
+	if cmd == nil {
+		cmd = handled()
+	}
+	return m, cmd
+}
+
+// validateFeed fetches a new feed to check it parses
+func (m Model) validateFeed(feedURL string) tea.Cmd {
+	poller := m.feedPoller()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		f, err := poller.Fetch(ctx, feedURL)
+		return feedAddedMsg{url: feedURL, feed: f, err: err}
+	}
+}
+
+// handleFeedAdded adds a validated feed
+func (m *Model) handleFeedAdded(msg feedAddedMsg) {
+	m.validatingURL = false
+	m.addingFeed = false
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Invalid feed: %v", msg.err)
+		return
+	}
+
+	name := msg.feed.Title
+	if name == "" {
+		if u, err := url.Parse(msg.url); err == nil {
+			name = u.Hostname()
+		}
+	}
+	m.cfg.Feeds = append(m.cfg.Feeds, config.FeedConfig{Name: name, URL: msg.url, Enabled: true})
+	st := m.feedStatusFor(msg.url)
+	st.items = msg.feed.Items
+	st.polled = time.Now() // Nothing to add until it has rules
+	m.saveFeeds()
+
+	rows := m.feedRows()
+	m.feedCursor = len(rows) - 1
+	m.statusMsg = fmt.Sprintf("Added feed: %s (%d items). Press n to add a rule", name, len(msg.feed.Items))
+}
+
+// toggleFeedRow enables or disables the selected feed or rule
+func (m *Model) toggleFeedRow() {
+	row, ok := m.selectedFeedRow()
+	if !ok {
+		return
+	}
+	feed := &m.cfg.Feeds[row.feed]
+	if row.rule < 0 {
+		feed.Enabled = !feed.Enabled
+		m.statusMsg = enabledStatus(feed.Enabled, feed.Name)
+	} else {
+		rule := &feed.Rules[row.rule]
+		rule.Enabled = !rule.Enabled
+		m.statusMsg = enabledStatus(rule.Enabled, rule.Name)
+	}
+	m.saveFeeds()
+}
+
+func enabledStatus(enabled bool, name string) string {
+	if enabled {
+		return "Enabled: " + name
+	}
+	return "Disabled: " + name
+}
+
+// deleteFeedRow removes the selected feed (with its history) or rule
+func (m *Model) deleteFeedRow() {
+	row, ok := m.selectedFeedRow()
+	if !ok {
+		return
+	}
+	if row.rule < 0 {
+		feed := m.cfg.Feeds[row.feed]
+		m.cfg.Feeds = append(m.cfg.Feeds[:row.feed:row.feed], m.cfg.Feeds[row.feed+1:]...)
+		delete(m.feedState, feed.URL)
+		m.feedHistory.Forget(feed.URL)
+		_ = m.feedHistory.Save()
+		m.statusMsg = "Removed feed: " + feed.Name
+	} else {
+		feed := &m.cfg.Feeds[row.feed]
+		rule := feed.Rules[row.rule]
+		feed.Rules = append(feed.Rules[:row.rule:row.rule], feed.Rules[row.rule+1:]...)
+		m.statusMsg = "Removed rule: " + rule.Name
+	}
+	if n := len(m.feedRows()); m.feedCursor >= n && m.feedCursor > 0 {
+		m.feedCursor = n - 1
+	}
+	m.saveFeeds()
+}
+
+// pollSelectedFeed polls the selected feed now
+func (m Model) pollSelectedFeed() (tea.Model, tea.Cmd) {
+	row, ok := m.selectedFeedRow()
+	if !ok {
+		return m, handled()
+	}
+	feed := m.cfg.Feeds[row.feed]
+	if m.feedStatusFor(feed.URL).polling {
+		return m, handled()
+	}
+	if feed.Enabled && !m.canAutoAdd() {
+		m.statusMsg = "Feed polling needs qBittorrent and the VPN connected"
+		return m, handled()
+	}
+	m.statusMsg = "Polling " + feed.Name + "..."
+	return m, m.pollFeed(feed)
+}
+
+// openRuleEditor opens the rule editor for the selected rule, or for a new
+// rule of the selected feed
+func (m Model) openRuleEditor(create bool) (tea.Model, tea.Cmd) {
+	row, ok := m.selectedFeedRow()
+	if !ok {
+		return m, handled()
+	}
+	if !create && row.rule < 0 {
+		return m, handled()
+	}
+
+	r := config.FeedRule{Enabled: true}
+	m.ruleFeed, m.ruleIndex = row.feed, -1
+	if !create {
+		r = m.cfg.Feeds[row.feed].Rules[row.rule]
+		m.ruleIndex = row.rule
+	}
+
+	values := [ruleFieldCount]string{
+		ruleFieldName:     r.Name,
+		ruleFieldInclude:  r.Include,
+		ruleFieldExclude:  r.Exclude,
+		ruleFieldEpisodes: r.Episodes,
+		ruleFieldCategory: r.Category,
+		ruleFieldSavePath: r.SavePath,
+		ruleFieldPaused:   yesNo(r.Paused),
+		ruleFieldEnabled:  yesNo(r.Enabled),
+	}
+	if r.MinSizeMB > 0 {
+		values[ruleFieldMinSize] = strconv.FormatInt(r.MinSizeMB, 10)
+	}
+	if r.MaxSizeMB > 0 {
+		values[ruleFieldMaxSize] = strconv.FormatInt(r.MaxSizeMB, 10)
+	}
+
+	m.ruleInputs = make([]textinput.Model, ruleFieldCount)
+	for i := range m.ruleInputs {
+		m.ruleInputs[i] = textinput.New()
+		m.ruleInputs[i].CharLimit = 256
+		m.ruleInputs[i].Width = 50
+		m.ruleInputs[i].SetValue(values[i])
+	}
+	m.ruleInputs[ruleFieldEpisodes].Placeholder = "e.g. S01E01+ (empty = all)"
+	m.ruleInputs[ruleFieldSavePath].Placeholder = m.cfg.Downloads.Path
+	m.ruleField = ruleFieldName
+	m.ruleInputs[m.ruleField].Focus()
+	m.showRuleEditor = true
+	return m, textinput.Blink
+}
+
+// ruleFromInputs converts the editor fields into a rule, checking that its
+// patterns compile
+func (m Model) ruleFromInputs() (config.FeedRule, error) {
+	v := func(field int) string {
+		return strings.TrimSpace(m.ruleInputs[field].Value())
+	}
+
+	r := config.FeedRule{
+		Name:     v(ruleFieldName),
+		Include:  v(ruleFieldInclude),
+		Exclude:  v(ruleFieldExclude),
+		Episodes: v(ruleFieldEpisodes),
+		Category: v(ruleFieldCategory),
+		SavePath: v(ruleFieldSavePath),
+		Paused:   isYes(v(ruleFieldPaused)),
+		Enabled:  isYes(v(ruleFieldEnabled)),
+	}
+	if r.Name == "" {
+		r.Name = r.Include
+	}
+	if r.Name == "" {
+		return r, fmt.Errorf("rule needs a name or an include pattern")
+	}
+
+	var err error
+	if s := v(ruleFieldMinSize); s != "" {
+		if r.MinSizeMB, err = strconv.ParseInt(s, 10, 64); err != nil || r.MinSizeMB < 0 {
+			return r, fmt.Errorf("invalid minimum size %q", s)
+		}
+	}
+	if s := v(ruleFieldMaxSize); s != "" {
+		if r.MaxSizeMB, err = strconv.ParseInt(s, 10, 64); err != nil || r.MaxSizeMB < 0 {
+			return r, fmt.Errorf("invalid maximum size %q", s)
+		}
+	}
+	if _, err := feeds.NewRule(r); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// handleRuleEditorKey handles keyboard input for the rule editor
+func (m Model) handleRuleEditorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showRuleEditor = false
+		return m, handled()
+
+	case "tab", "down", "shift+tab", "up":
+		m.ruleInputs[m.ruleField].Blur()
+		if key := msg.String(); key == "shift+tab" || key == "up" {
+			m.ruleField = (m.ruleField + ruleFieldCount - 1) % ruleFieldCount
+		} else {
+			m.ruleField = (m.ruleField + 1) % ruleFieldCount
+		}
+		m.ruleInputs[m.ruleField].Focus()
+		return m, textinput.Blink
+
+	case " ", "space":
+		// Toggle yes/no fields
+		if m.ruleField == ruleFieldPaused || m.ruleField == ruleFieldEnabled {
+			input := &m.ruleInputs[m.ruleField]
+			input.SetValue(yesNo(!isYes(input.Value())))
+			return m, handled()
+		}
+
+	case "enter":
+		r, err := m.ruleFromInputs()
+		if err != nil {
+			m.statusMsg = "Rule not saved: " + err.Error()
+			return m, handled()
+		}
+		feed := &m.cfg.Feeds[m.ruleFeed]
+		if m.ruleIndex < 0 {
+			feed.Rules = append(feed.Rules, r)
+			m.statusMsg = "Added rule: " + r.Name
+			// Put the cursor on the new rule
+			for i, row := range m.feedRows() {
+				if row.feed == m.ruleFeed && row.rule == len(feed.Rules)-1 {
+					m.feedCursor = i
+				}
+			}
+		} else {
+			feed.Rules[m.ruleIndex] = r
+			m.statusMsg = "Saved rule: " + r.Name
+		}
+		m.showRuleEditor = false
+		m.saveFeeds()
+		return m, handled()
+	}
+
+	var cmd tea.Cmd
+	m.ruleInputs[m.ruleField], cmd = m.ruleInputs[m.ruleField].Update(msg)
+	if cmd == nil {
+		cmd = handled()
+	}
+	return m, cmd
+}
+
+// renderRuleEditor renders the rule editor modal
+func (m Model) renderRuleEditor() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(76)
+
+	var content strings.Builder
+	if m.ruleIndex < 0 {
+		content.WriteString(styles.Title.Render("New Feed Rule"))
+	} else {
+		content.WriteString(styles.Title.Render("Edit Feed Rule"))
+	}
+	content.WriteString("\n\n")
+	content.WriteString(styles.Muted.Render("  " + TruncateString(m.cfg.Feeds[m.ruleFeed].Name, 60)))
+	content.WriteString("\n\n")
+
+	for field := 0; field < ruleFieldCount; field++ {
+		label := ruleFieldLabels[field]
+		if field == m.ruleField {
+			content.WriteString(styles.Title.Render(PadRight("› "+label+":", 20)))
+		} else {
+			content.WriteString(styles.Muted.Render(PadRight("  "+label+":", 20)))
+		}
+		content.WriteString(" " + m.ruleInputs[field].View() + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styles.Muted.Render("  Episodes: S01E05+  S02  S02+  S01E03-S01E08 (comma separated)"))
+	content.WriteString("\n")
+	content.WriteString(styles.Muted.Render("  [tab]Field [space]Toggle yes/no [enter]Save [esc]Cancel"))
+
+	return modalStyle.Render(content.String())
+}
+
+// renderFeedsTab renders the feed list with a preview of the selected
+// feed's items
+func (m Model) renderFeedsTab(height int) string {
+	styles := GetStyles()
+	var b strings.Builder
+
+	if m.validatingURL {
+		b.WriteString(styles.SearchPrompt.Render("Fetching feed") + styles.Muted.Render("..."))
+		b.WriteString("\n\n")
+	} else if m.addingFeed {
+		b.WriteString(styles.SearchPrompt.Render("Add Feed URL: ") + m.feedInput.View())
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(styles.PanelTitle.Render("Feeds"))
+		b.WriteString("  ")
+		b.WriteString(styles.Muted.Render("[a]Add feed  [n]New rule  [e]Edit  [space]Toggle  [r]Poll  [x]Remove"))
+		b.WriteString("\n\n")
+	}
+
+	rows := m.feedRows()
+	if len(rows) == 0 {
+		b.WriteString(styles.Muted.Render("No feeds configured. Press 'a' to add an RSS or Atom feed URL."))
+		return b.String()
+	}
+
+	// The list gets up to half the height, the preview the rest
+	listRows := min(len(rows), max((height-4)/2, 3))
+	startIdx := 0
+	if m.feedCursor >= listRows {
+		startIdx = m.feedCursor - listRows + 1
+	}
+	endIdx := min(startIdx+listRows, len(rows))
+
+	statusWidth := 22
+	nameWidth := max(m.width-statusWidth-5, 20)
+	for i := startIdx; i < endIdx; i++ {
+		row := rows[i]
+		feed := m.cfg.Feeds[row.feed]
+
+		var name, status string
+		var statusStyle lipgloss.Style
+		if row.rule < 0 {
+			name = feed.Name
+			status, statusStyle = m.feedStatusLabel(feed)
+		} else {
+			rule := feed.Rules[row.rule]
+			name = "  ↳ " + rule.Name + "  " + styles.Muted.Render(feeds.Describe(rule))
+			status, statusStyle = "Enabled", styles.VPNConnected
+			if !rule.Enabled {
+				status, statusStyle = "Disabled", styles.Muted
+			}
+		}
+
+		line := PadRight(TruncateString(name, nameWidth), nameWidth) + " "
+		statusStyled := statusStyle.Render(PadLeft(status, statusWidth))
+		if i == m.feedCursor {
+			b.WriteString(styles.TableSelected.Render("› "+line) + statusStyled)
+		} else {
+			b.WriteString(styles.TableRow.Render("  "+line) + statusStyled)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderFeedPreview(height - (endIdx - startIdx) - 3))
+	return b.String()
+}
+
+// feedStatusLabel describes a feed's poll state for the list
+func (m Model) feedStatusLabel(feed config.FeedConfig) (string, lipgloss.Style) {
+	styles := GetStyles()
+	st := m.feedState[feed.URL]
+	switch {
+	case st != nil && st.polling:
+		return "Polling...", styles.HealthMed
+	case !feed.Enabled:
+		return "Disabled", styles.Muted
+	case st != nil && st.err != nil:
+		return "Error", styles.Error
+	case st == nil || st.polled.IsZero():
+		return "Waiting", styles.Muted
+	}
+	next := time.Until(st.polled.Add(feeds.Interval(feed))).Round(time.Minute)
+	return fmt.Sprintf("Next poll %s", formatNextPoll(max(next, 0))), styles.VPNConnected
+}
+
+// formatNextPoll formats a poll countdown ("in 12m")
+func formatNextPoll(d time.Duration) string {
+	if d < time.Minute {
+		return "soon"
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("in %dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("in %dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// renderFeedPreview lists the selected feed's items. With a rule selected,
+// each item shows what that rule would do with it.
+func (m Model) renderFeedPreview(height int) string {
+	styles := GetStyles()
+	var b strings.Builder
+
+	row, ok := m.selectedFeedRow()
+	if !ok {
+		return ""
+	}
+	feed := m.cfg.Feeds[row.feed]
+	st := m.feedState[feed.URL]
+
+	title := "Items"
+	if row.rule >= 0 {
+		title = "Preview: " + feed.Rules[row.rule].Name
+	}
+	b.WriteString(styles.PanelTitle.Render(title))
+	b.WriteString("\n")
+	if st != nil && st.err != nil {
+		b.WriteString(styles.Error.Render(TruncateString(st.err.Error(), m.width-2)))
+		b.WriteString("\n")
+	}
+	if st == nil || len(st.items) == 0 {
+		b.WriteString(styles.Muted.Render("No items fetched yet. Press 'r' to poll the feed."))
+		return b.String()
+	}
+
+	var statuses []feeds.Status
+	if row.rule >= 0 {
+		var err error
+		statuses, err = feeds.Preview(feed.URL, feed.Rules[row.rule], st.items, m.feedHistory)
+		if err != nil {
+			b.WriteString(styles.Error.Render("Invalid rule: " + err.Error()))
+			return b.String()
+		}
+		matched := 0
+		for _, s := range statuses {
+			if s == feeds.StatusMatch {
+				matched++
+			}
+		}
+		b.WriteString(styles.Muted.Render(fmt.Sprintf("%d of %d items would be added", matched, len(st.items))))
+		b.WriteString("\n")
+	}
+
+	sizeWidth := 10
+	titleWidth := max(m.width-sizeWidth-16, 20)
+	for i, item := range st.items {
+		if i >= max(height-3, 1) {
+			break
+		}
+		size := "-"
+		if item.Size > 0 {
+			size = formatSize(item.Size)
+		}
+		line := PadRight(TruncateString(item.Title, titleWidth), titleWidth) + " " + PadLeft(size, sizeWidth)
+
+		if statuses == nil {
+			b.WriteString(styles.TableRow.Render("  " + line))
+		} else {
+			switch statuses[i] {
+			case feeds.StatusMatch:
+				b.WriteString(styles.VPNConnected.Render("✓ "+line) + styles.VPNConnected.Render("  match"))
+			case feeds.StatusAdded:
+				b.WriteString(styles.Muted.Render("· "+line) + styles.Muted.Render("  added"))
+			case feeds.StatusDuplicate:
+				b.WriteString(styles.HealthMed.Render("= "+line) + styles.HealthMed.Render("  dupe"))
+			default:
+				b.WriteString(styles.Muted.Render("  " + line))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
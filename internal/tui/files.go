@@ -0,0 +1,344 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// filePriorities is the order +/- steps through
+var filePriorities = []int{qbit.FilePrioritySkip, qbit.FilePriorityNormal, qbit.FilePriorityHigh, qbit.FilePriorityMax}
+
+// stepFilePriority returns the priority delta steps away from p, clamped
+func stepFilePriority(p, delta int) int {
+	idx := 1
+	for i, prio := range filePriorities {
+		if p >= prio {
+			idx = i
+		}
+	}
+	idx = min(max(idx+delta, 0), len(filePriorities)-1)
+	return filePriorities[idx]
+}
+
+// rowFileIndexes returns the indexes of the files a tree row covers: the
+// file itself, or every file below a directory
+func rowFileIndexes(files []qbit.TorrentFile, row fileTreeRow) []int {
+	if !row.isDir {
+		return []int{row.index}
+	}
+	var indexes []int
+	for _, f := range files {
+		if strings.HasPrefix(f.Name, row.path+"/") {
+			indexes = append(indexes, f.Index)
+		}
+	}
+	return indexes
+}
+
+// rowPriority returns the priority shown for a row. Directories report the
+// highest priority of their files, so a directory is skipped only if all are.
+func rowPriority(files []qbit.TorrentFile, row fileTreeRow) int {
+	if !row.isDir {
+		return row.priority
+	}
+	prio := qbit.FilePrioritySkip
+	for _, idx := range rowFileIndexes(files, row) {
+		for _, f := range files {
+			if f.Index == idx {
+				prio = max(prio, f.Priority)
+			}
+		}
+	}
+	return prio
+}
+
+// changeRowPriority updates files under the tree row at cursor in place.
+// With toggle set it flips between skip and normal; otherwise it steps by
+// delta. Returns the changed file indexes and their new priority.
+func changeRowPriority(files []qbit.TorrentFile, cursor int, toggle bool, delta int) ([]int, int) {
+	rows := buildFileTree(files)
+	if cursor < 0 || cursor >= len(rows) {
+		return nil, 0
+	}
+	row := rows[cursor]
+	current := rowPriority(files, row)
+
+	prio := stepFilePriority(current, delta)
+	if toggle {
+		prio = qbit.FilePriorityNormal
+		if current != qbit.FilePrioritySkip {
+			prio = qbit.FilePrioritySkip
+		}
+	}
+
+	indexes := rowFileIndexes(files, row)
+	for i := range files {
+		for _, idx := range indexes {
+			if files[i].Index == idx {
+				files[i].Priority = prio
+			}
+		}
+	}
+	return indexes, prio
+}
+
+// setFilePriority sends a file priority change to qBittorrent
+func (m Model) setFilePriority(hash string, indexes []int, priority int) tea.Cmd {
+	client := m.qbitClient
+	name := fmt.Sprintf("%d files", len(indexes))
+	if len(indexes) == 1 {
+		name = "1 file"
+	}
+	return func() tea.Msg {
+		err := client.SetFilePriority(context.Background(), hash, indexes, priority)
+		return torrentActionMsg{action: filePriorityLabel(priority), name: name, err: err}
+	}
+}
+
+// filePick tracks a torrent added with "pick files": it is added with a
+// temporary tag so it can be found, stopped once metadata arrives, and
+// only resumed after the user has chosen which files to download
+type filePick struct {
+	tag      string             // Temporary tag identifying the torrent
+	name     string             // Display name while the hash is unknown
+	hash     string             // Set once the torrent shows up
+	started  time.Time          // When the torrent was added
+	fetching bool               // Guard against overlapping file fetches
+	files    []qbit.TorrentFile // File list once metadata is in
+	cursor   int                // Selected file tree row
+	open     bool               // Is the picker modal showing?
+}
+
+// pickMetadataTimeout is how long to wait for metadata before giving up
+const pickMetadataTimeout = 10 * time.Minute
+
+type pickFilesMsg struct {
+	hash  string
+	files []qbit.TorrentFile
+	err   error
+}
+
+// beginPick prepares add options for a pick-files add. A paused magnet never
+// fetches its metadata, so instead of adding paused the torrent is started
+// with a stop condition that halts it as soon as the metadata is in.
+func (m *Model) beginPick(name string, opts *qbit.AddTorrentOptions) bool {
+	if m.pick.tag != "" {
+		m.statusMsg = "Already picking files for " + TruncateString(m.pick.name, 30)
+		return false
+	}
+	m.pick = filePick{
+		tag:     fmt.Sprintf("pick-files-%d", time.Now().UnixNano()),
+		name:    name,
+		started: time.Now(),
+	}
+	opts.Tags = append(opts.Tags, m.pick.tag)
+	opts.Paused = false
+	opts.StopCondition = qbit.StopConditionMetadataReceived
+	return true
+}
+
+// pollPick finds the picked torrent and fetches its file list once the
+// metadata has arrived. Called on every refresh tick.
+func (m *Model) pollPick() tea.Cmd {
+	if m.pick.tag == "" || m.pick.open || m.pick.fetching {
+		return nil
+	}
+	if time.Since(m.pick.started) > pickMetadataTimeout {
+		m.statusMsg = "No metadata for " + TruncateString(m.pick.name, 30) + " - file picking cancelled"
+		cmd := m.cleanupPick(false)
+		m.pick = filePick{}
+		return cmd
+	}
+
+	if m.pick.hash == "" {
+		for _, t := range m.allTorrents() {
+			if t.HasTag(m.pick.tag) {
+				m.pick.hash = t.Hash
+				m.pick.name = t.Name
+			}
+		}
+		if m.pick.hash == "" {
+			return nil
+		}
+	}
+
+	m.pick.fetching = true
+	client := m.qbitClient
+	hash := m.pick.hash
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		files, err := client.Files(ctx, hash)
+		if err != nil || len(files) == 0 {
+			// Still downloading metadata (metaDL)
+			return pickFilesMsg{hash: hash, err: err}
+		}
+		// Older qBittorrent ignores stopCondition, so make sure it's paused
+		err = client.Pause(ctx, hash)
+		return pickFilesMsg{hash: hash, files: files, err: err}
+	}
+}
+
+// handlePickFiles opens the picker once the file list has arrived
+func (m *Model) handlePickFiles(msg pickFilesMsg) {
+	m.pick.fetching = false
+	if msg.hash != m.pick.hash || len(msg.files) == 0 {
+		return
+	}
+	m.pick.files = msg.files
+	m.pick.cursor = 0
+	m.pick.open = true
+	m.searchInput.Blur()
+}
+
+// cleanupPick removes the temporary tag and optionally starts the torrent
+// with the chosen file priorities
+func (m Model) cleanupPick(start bool) tea.Cmd {
+	client := m.qbitClient
+	pick := m.pick
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var err error
+		if start {
+			// One request per priority level
+			byPrio := make(map[int][]int)
+			for _, f := range pick.files {
+				byPrio[f.Priority] = append(byPrio[f.Priority], f.Index)
+			}
+			prios := make([]int, 0, len(byPrio))
+			for prio := range byPrio {
+				prios = append(prios, prio)
+			}
+			sort.Ints(prios)
+			for _, prio := range prios {
+				if err = client.SetFilePriority(ctx, pick.hash, byPrio[prio], prio); err != nil {
+					return torrentActionMsg{action: "Set file priorities", name: pick.name, err: err}
+				}
+			}
+		}
+
+		if pick.hash != "" {
+			_ = client.RemoveTags(ctx, []string{pick.hash}, pick.tag)
+		}
+		_ = client.DeleteTags(ctx, pick.tag)
+
+		if !start {
+			return torrentActionMsg{action: "Left paused", name: pick.name}
+		}
+		err = client.Resume(ctx, pick.hash)
+		return torrentActionMsg{action: "Started", name: pick.name, err: err}
+	}
+}
+
+// handleFilePickerKey handles keyboard input for the file picker modal
+func (m Model) handleFilePickerKey(key string) (tea.Model, tea.Cmd) {
+	rows := buildFileTree(m.pick.files)
+
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.pick.cursor > 0 {
+			m.pick.cursor--
+		}
+
+	case "down", "j":
+		if m.pick.cursor < len(rows)-1 {
+			m.pick.cursor++
+		}
+
+	case " ", "space":
+		changeRowPriority(m.pick.files, m.pick.cursor, true, 0)
+
+	case "+", "=":
+		changeRowPriority(m.pick.files, m.pick.cursor, false, 1)
+
+	case "-":
+		changeRowPriority(m.pick.files, m.pick.cursor, false, -1)
+
+	case "a": // Download everything
+		for i := range m.pick.files {
+			m.pick.files[i].Priority = qbit.FilePriorityNormal
+		}
+
+	case "n": // Skip everything
+		for i := range m.pick.files {
+			m.pick.files[i].Priority = qbit.FilePrioritySkip
+		}
+
+	case "enter":
+		wanted := false
+		for _, f := range m.pick.files {
+			if f.Priority != qbit.FilePrioritySkip {
+				wanted = true
+			}
+		}
+		if !wanted {
+			m.statusMsg = "Select at least one file"
+			return m, handled()
+		}
+		cmd := m.cleanupPick(true)
+		m.pick = filePick{}
+		return m, cmd
+
+	case "esc":
+		cmd := m.cleanupPick(false)
+		m.pick = filePick{}
+		return m, cmd
+	}
+
+	return m, handled()
+}
+
+// renderFilePicker renders the pick-files modal
+func (m Model) renderFilePicker() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(90)
+
+	var content strings.Builder
+	content.WriteString(styles.Title.Render("Select Files"))
+	content.WriteString("\n\n")
+	content.WriteString(styles.Muted.Render("  " + TruncateString(m.pick.name, 80)))
+	content.WriteString("\n\n")
+
+	var wanted, total int64
+	for _, f := range m.pick.files {
+		total += f.Size
+		if f.Priority != qbit.FilePrioritySkip {
+			wanted += f.Size
+		}
+	}
+
+	lines := renderFileTree(m.pick.files, 84, m.pick.cursor)
+	visible := max(min(m.height-16, 20), 5)
+	start := 0
+	if m.pick.cursor >= visible {
+		start = m.pick.cursor - visible + 1
+	}
+	end := min(start+visible, len(lines))
+	content.WriteString(strings.Join(lines[start:end], "\n"))
+	content.WriteString("\n\n")
+
+	content.WriteString(fmt.Sprintf("  Selected: %s of %s\n\n", formatSize(wanted), formatSize(total)))
+	content.WriteString(styles.Muted.Render("  [space]Get/Skip [+/-]Priority [a]All [n]None [enter]Start [esc]Leave paused"))
+
+	return modalStyle.Render(content.String())
+}
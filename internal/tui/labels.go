@@ -0,0 +1,535 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// sidebarWidth is the width of the filter sidebar including its border
+const sidebarWidth = 24
+
+// filterKind selects which torrents the Downloads/Completed lists show
+type filterKind int
+
+const (
+	filterAll filterKind = iota
+	filterUncategorized
+	filterCategory
+	filterTag
+)
+
+// torrentFilter is the active sidebar filter
+type torrentFilter struct {
+	kind  filterKind
+	value string // Category or tag name
+}
+
+// matches reports whether a torrent passes the filter
+func (f torrentFilter) matches(t qbit.TorrentInfo) bool {
+	switch f.kind {
+	case filterUncategorized:
+		return t.Category == ""
+	case filterCategory:
+		return t.Category == f.value
+	case filterTag:
+		return t.HasTag(f.value)
+	}
+	return true
+}
+
+// label returns the filter's display name
+func (f torrentFilter) label() string {
+	switch f.kind {
+	case filterUncategorized:
+		return "Uncategorized"
+	case filterCategory:
+		return f.value
+	case filterTag:
+		return "#" + f.value
+	}
+	return "All"
+}
+
+// filterEntry is one line of the sidebar
+type filterEntry struct {
+	filter torrentFilter
+	count  int
+}
+
+// filterTorrents returns the torrents that pass the filter
+func filterTorrents(torrents []qbit.TorrentInfo, f torrentFilter) []qbit.TorrentInfo {
+	if f.kind == filterAll {
+		return torrents
+	}
+	var filtered []qbit.TorrentInfo
+	for _, t := range torrents {
+		if f.matches(t) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// buildFilterEntries returns the sidebar entries with per-entry counts.
+// Categories and tags that only exist on torrents are included too.
+func buildFilterEntries(torrents []qbit.TorrentInfo, categories map[string]qbit.Category, tags []string) []filterEntry {
+	catCounts := make(map[string]int)
+	tagCounts := make(map[string]int)
+	for name := range categories {
+		catCounts[name] = 0
+	}
+	for _, tag := range tags {
+		tagCounts[tag] = 0
+	}
+
+	uncategorized := 0
+	for _, t := range torrents {
+		if t.Category == "" {
+			uncategorized++
+		} else {
+			catCounts[t.Category]++
+		}
+		for _, tag := range t.TagList() {
+			tagCounts[tag]++
+		}
+	}
+
+	entries := []filterEntry{
+		{filter: torrentFilter{kind: filterAll}, count: len(torrents)},
+		{filter: torrentFilter{kind: filterUncategorized}, count: uncategorized},
+	}
+	for _, name := range sortedKeys(catCounts) {
+		entries = append(entries, filterEntry{filter: torrentFilter{kind: filterCategory, value: name}, count: catCounts[name]})
+	}
+	for _, tag := range sortedKeys(tagCounts) {
+		entries = append(entries, filterEntry{filter: torrentFilter{kind: filterTag, value: tag}, count: tagCounts[tag]})
+	}
+	return entries
+}
+
+// sortedKeys returns a map's keys in case-insensitive order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+	return keys
+}
+
+// tabTorrents returns the unfiltered torrent list for the active tab
+func (m Model) tabTorrents() []qbit.TorrentInfo {
+	if m.activeTab == tabCompleted {
+		return m.compAll
+	}
+	return m.dlAll
+}
+
+// allTorrents returns the unfiltered Downloads and Completed lists combined
+func (m Model) allTorrents() []qbit.TorrentInfo {
+	all := make([]qbit.TorrentInfo, 0, len(m.dlAll)+len(m.compAll))
+	all = append(all, m.dlAll...)
+	return append(all, m.compAll...)
+}
+
+// filterEntries returns the sidebar entries for the active tab
+func (m Model) filterEntries() []filterEntry {
+	return buildFilterEntries(m.tabTorrents(), m.categories, m.tags)
+}
+
+// applyTorrentFilter rebuilds the visible Downloads/Completed lists from the
+// full lists, keeping the current sort and cursor in range
+func (m *Model) applyTorrentFilter() {
+	// Drop a category/tag filter once it no longer exists anywhere
+	if m.filter.kind == filterCategory || m.filter.kind == filterTag {
+		found := false
+		for _, e := range buildFilterEntries(m.allTorrents(), m.categories, m.tags) {
+			if e.filter == m.filter {
+				found = true
+			}
+		}
+		if !found {
+			m.filter = torrentFilter{}
+		}
+	}
+
+	m.downloading = filterTorrents(m.dlAll, m.filter)
+	m.completed = filterTorrents(m.compAll, m.filter)
+	sortTorrents(m.downloading, m.dlSortCol, m.dlSortAsc)
+	sortCompletedTorrents(m.completed, m.compSortCol, m.compSortAsc)
+
+	n := len(m.downloading)
+	if m.activeTab == tabCompleted {
+		n = len(m.completed)
+	}
+	if m.dlCursor >= n {
+		m.dlCursor = max(n-1, 0)
+	}
+}
+
+// cycleFilter moves the sidebar selection by delta entries
+func (m *Model) cycleFilter(delta int) {
+	entries := m.filterEntries()
+	idx := 0
+	for i, e := range entries {
+		if e.filter == m.filter {
+			idx = i
+		}
+	}
+	idx = (idx + delta + len(entries)) % len(entries)
+	m.filter = entries[idx].filter
+	m.dlCursor = 0
+	m.applyTorrentFilter()
+	m.statusMsg = "Filter: " + m.filter.label()
+}
+
+// selectedTorrent returns the torrent under the cursor in Downloads/Completed,
+// or the one shown in the detail pane while it is open
+func (m Model) selectedTorrent() (qbit.TorrentInfo, bool) {
+	if m.detailOpen() {
+		return findTorrentInfo(m.allTorrents(), m.detailHash)
+	}
+	switch m.activeTab {
+	case tabDownloads:
+		if m.dlCursor < len(m.downloading) {
+			return m.downloading[m.dlCursor], true
+		}
+	case tabCompleted:
+		if m.dlCursor < len(m.completed) {
+			return m.completed[m.dlCursor], true
+		}
+	}
+	return qbit.TorrentInfo{}, false
+}
+
+// renderWithSidebar renders a torrent list tab with the filter sidebar on its left
+func (m Model) renderWithSidebar(height int, render func(Model, int) string) string {
+	if !m.showSidebar || m.width < sidebarWidth+60 {
+		return render(m, height)
+	}
+	list := m
+	list.width -= sidebarWidth + 1
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.renderFilterSidebar(height), " ", render(list, height))
+}
+
+// renderFilterSidebar renders the category/tag filter list with counts
+func (m Model) renderFilterSidebar(height int) string {
+	styles := GetStyles()
+	inner := sidebarWidth - 2 // border + gap
+
+	entries := m.filterEntries()
+	var lines []string
+	selected := 0
+	lastKind := filterAll
+	for _, e := range entries {
+		// Section headings before the first category and first tag
+		if e.filter.kind != lastKind && (e.filter.kind == filterCategory || e.filter.kind == filterTag) {
+			heading := "CATEGORIES"
+			if e.filter.kind == filterTag {
+				heading = "TAGS"
+			}
+			lines = append(lines, "", styles.Muted.Render(heading))
+		}
+		lastKind = e.filter.kind
+
+		count := fmt.Sprintf("%d", e.count)
+		name := TruncateString(e.filter.label(), inner-len(count)-3)
+		row := PadRight(name, inner-len(count)-2) + count
+		if e.filter == m.filter {
+			selected = len(lines)
+			lines = append(lines, styles.TableSelected.Render("› "+row))
+		} else {
+			lines = append(lines, styles.TableRow.Render("  "+row))
+		}
+	}
+
+	// Scroll so the selected entry stays visible
+	if height < 1 {
+		height = 1
+	}
+	start := 0
+	if selected >= height {
+		start = selected - height + 1
+	}
+	end := min(start+height, len(lines))
+
+	sidebarStyle := lipgloss.NewStyle().
+		Width(sidebarWidth - 1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderRight(true).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Muted))
+	return sidebarStyle.Render(strings.Join(lines[start:end], "\n"))
+}
+
+// labelPickerMode selects what the label picker assigns
+type labelPickerMode int
+
+const (
+	pickCategory labelPickerMode = iota
+	pickTags
+)
+
+// openLabelPicker opens the category or tag picker for the selected torrents
+func (m Model) openLabelPicker(mode labelPickerMode) (tea.Model, tea.Cmd) {
+	targets := m.targetTorrents()
+	if len(targets) == 0 {
+		return m, handled()
+	}
+	t := targets[0]
+
+	m.labelMode = mode
+	m.labelHashes, m.labelTarget = targetHashes(targets)
+	m.labelCreating = false
+	m.labelCursor = 0
+	m.labelChecked = make(map[string]bool)
+
+	if mode == pickCategory {
+		m.labelItems = []string{""} // "(none)" clears the category
+		for _, e := range buildFilterEntries(m.allTorrents(), m.categories, nil) {
+			if e.filter.kind == filterCategory {
+				m.labelItems = append(m.labelItems, e.filter.value)
+				if e.filter.value == t.Category {
+					m.labelCursor = len(m.labelItems) - 1
+				}
+			}
+		}
+	} else {
+		m.labelItems = nil
+		for _, e := range buildFilterEntries(m.allTorrents(), nil, m.tags) {
+			if e.filter.kind == filterTag {
+				m.labelItems = append(m.labelItems, e.filter.value)
+			}
+		}
+		// Only tags every target carries start checked
+		for _, tag := range t.TagList() {
+			m.labelChecked[tag] = true
+			for _, other := range targets[1:] {
+				if !other.HasTag(tag) {
+					delete(m.labelChecked, tag)
+				}
+			}
+		}
+	}
+
+	m.labelInput = textinput.New()
+	m.labelInput.CharLimit = 64
+	m.labelInput.Width = 40
+	m.showLabelPicker = true
+	return m, handled()
+}
+
+// handleLabelPickerKey handles keyboard input for the category/tag picker
+func (m Model) handleLabelPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Naming a new category or tag
+	if m.labelCreating {
+		switch key {
+		case "esc":
+			m.labelCreating = false
+			m.labelInput.Blur()
+			return m, handled()
+		case "enter":
+			name := strings.TrimSpace(m.labelInput.Value())
+			m.labelCreating = false
+			m.labelInput.Blur()
+			if name == "" {
+				return m, handled()
+			}
+			if m.labelMode == pickCategory {
+				m.showLabelPicker = false
+				return m, m.createAndSetCategory(name)
+			}
+			m.labelItems = append(m.labelItems, name)
+			m.labelCursor = len(m.labelItems) - 1
+			m.labelChecked[name] = true
+			return m, m.setTag(name, true)
+		}
+		var cmd tea.Cmd
+		m.labelInput, cmd = m.labelInput.Update(msg)
+		if cmd == nil {
+			cmd = handled()
+		}
+		return m, cmd
+	}
+
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.showLabelPicker = false
+		return m, handled()
+
+	case "up", "k":
+		if m.labelCursor > 0 {
+			m.labelCursor--
+		}
+		return m, handled()
+
+	case "down", "j":
+		if m.labelCursor < len(m.labelItems)-1 {
+			m.labelCursor++
+		}
+		return m, handled()
+
+	case "n":
+		m.labelCreating = true
+		m.labelInput.SetValue("")
+		if m.labelMode == pickCategory {
+			m.labelInput.Placeholder = "New category name"
+		} else {
+			m.labelInput.Placeholder = "New tag name"
+		}
+		m.labelInput.Focus()
+		return m, textinput.Blink
+
+	case "enter", " ", "space":
+		if m.labelCursor >= len(m.labelItems) {
+			return m, handled()
+		}
+		item := m.labelItems[m.labelCursor]
+		if m.labelMode == pickCategory {
+			m.showLabelPicker = false
+			return m, m.setCategory(item)
+		}
+		on := !m.labelChecked[item]
+		m.labelChecked[item] = on
+		return m, m.setTag(item, on)
+
+	case "x", "delete":
+		// Delete the category/tag from qBittorrent entirely
+		if m.labelCursor >= len(m.labelItems) || m.labelItems[m.labelCursor] == "" {
+			return m, handled()
+		}
+		item := m.labelItems[m.labelCursor]
+		m.labelItems = append(m.labelItems[:m.labelCursor], m.labelItems[m.labelCursor+1:]...)
+		if m.labelCursor >= len(m.labelItems) && m.labelCursor > 0 {
+			m.labelCursor--
+		}
+		delete(m.labelChecked, item)
+		return m, m.deleteLabel(item)
+	}
+
+	return m, handled()
+}
+
+// setCategory assigns a category to the picker's torrents
+func (m Model) setCategory(category string) tea.Cmd {
+	client := m.qbitClient
+	action := "Category " + category
+	if category == "" {
+		action = "Category cleared"
+	}
+	return m.bulkHashAction(action, m.labelHashes, m.labelTarget, func(ctx context.Context, hashes []string) error {
+		return client.SetCategory(ctx, hashes, category)
+	})
+}
+
+// createAndSetCategory creates a category and assigns it to the picker's torrents
+func (m Model) createAndSetCategory(category string) tea.Cmd {
+	client := m.qbitClient
+	return m.bulkHashAction("Category "+category, m.labelHashes, m.labelTarget, func(ctx context.Context, hashes []string) error {
+		if err := client.CreateCategory(ctx, category, ""); err != nil {
+			return err
+		}
+		return client.SetCategory(ctx, hashes, category)
+	})
+}
+
+// setTag adds or removes a tag on the picker's torrents
+func (m Model) setTag(tag string, on bool) tea.Cmd {
+	client := m.qbitClient
+	if on {
+		return m.bulkHashAction("Tagged #"+tag, m.labelHashes, m.labelTarget, func(ctx context.Context, hashes []string) error {
+			return client.AddTags(ctx, hashes, tag)
+		})
+	}
+	return m.bulkHashAction("Untagged #"+tag, m.labelHashes, m.labelTarget, func(ctx context.Context, hashes []string) error {
+		return client.RemoveTags(ctx, hashes, tag)
+	})
+}
+
+// deleteLabel removes a category or tag from qBittorrent
+func (m Model) deleteLabel(label string) tea.Cmd {
+	client := m.qbitClient
+	mode := m.labelMode
+	return func() tea.Msg {
+		if mode == pickCategory {
+			err := client.RemoveCategories(context.Background(), label)
+			return torrentActionMsg{action: "Deleted category", name: label, err: err}
+		}
+		err := client.DeleteTags(context.Background(), label)
+		return torrentActionMsg{action: "Deleted tag", name: "#" + label, err: err}
+	}
+}
+
+// renderLabelPicker renders the category/tag picker modal
+func (m Model) renderLabelPicker() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(60)
+
+	var content strings.Builder
+	if m.labelMode == pickCategory {
+		content.WriteString(styles.Title.Render("Set Category"))
+	} else {
+		content.WriteString(styles.Title.Render("Tags"))
+	}
+	content.WriteString("\n\n")
+	content.WriteString(styles.Muted.Render("  " + TruncateString(m.labelTarget, 50)))
+	content.WriteString("\n\n")
+
+	if len(m.labelItems) == 0 {
+		content.WriteString(styles.Muted.Render("  No tags yet - press n to create one"))
+		content.WriteString("\n")
+	}
+	for i, item := range m.labelItems {
+		var mark string
+		if m.labelMode == pickTags {
+			if m.labelChecked[item] {
+				mark = "[x] "
+			} else {
+				mark = "[ ] "
+			}
+		}
+		label := item
+		if label == "" {
+			label = "(none)"
+		}
+		row := mark + TruncateString(label, 44)
+		if i == m.labelCursor {
+			content.WriteString(styles.TableSelected.Render("› " + row))
+		} else {
+			content.WriteString(styles.TableRow.Render("  " + row))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	if m.labelCreating {
+		content.WriteString("  " + m.labelInput.View() + "\n\n")
+		content.WriteString(styles.Muted.Render("  [enter]Create [esc]Cancel"))
+	} else if m.labelMode == pickCategory {
+		content.WriteString(styles.Muted.Render("  [enter]Set [n]New [x]Delete [esc]Close"))
+	} else {
+		content.WriteString(styles.Muted.Render("  [space]Toggle [n]New [x]Delete [esc]Close"))
+	}
+
+	return modalStyle.Render(content.String())
+}
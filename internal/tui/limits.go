@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// Fields of the speed limit modal
+const (
+	limitFieldDL = iota
+	limitFieldUL
+	limitFieldCount
+)
+
+var limitFieldLabels = [limitFieldCount]string{"Download (KiB/s)", "Upload (KiB/s)"}
+
+// altSpeedMsg reports the alternative speed limit state after a toggle
+type altSpeedMsg struct {
+	enabled bool
+	err     error
+}
+
+// kibString formats a bytes/s limit for the modal ("" = unlimited)
+func kibString(limit int64) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(limit/1024, 10)
+}
+
+// parseLimitKiB parses a KiB/s limit from the modal into bytes/s.
+// Empty or 0 means unlimited.
+func parseLimitKiB(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	kib, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || kib < 0 {
+		return 0, fmt.Errorf("invalid limit %q", s)
+	}
+	return kib * 1024, nil
+}
+
+// openLimitsModal opens the speed limit modal for the target torrents, or
+// for the global limits outside the torrent tabs
+func (m Model) openLimitsModal() (tea.Model, tea.Cmd) {
+	values := [limitFieldCount]string{}
+	m.limitsGlobal = m.activeTab != tabDownloads && m.activeTab != tabCompleted
+
+	if m.limitsGlobal {
+		values[limitFieldDL] = kibString(m.serverState.DLRateLimit)
+		values[limitFieldUL] = kibString(m.serverState.UPRateLimit)
+	} else {
+		targets := m.targetTorrents()
+		if len(targets) == 0 {
+			return m, handled()
+		}
+		m.limitsHashes, m.limitsTarget = targetHashes(targets)
+		// Prefill only limits every target shares
+		dl, ul := targets[0].DlLimit, targets[0].UpLimit
+		for _, t := range targets[1:] {
+			if t.DlLimit != dl {
+				dl = 0
+			}
+			if t.UpLimit != ul {
+				ul = 0
+			}
+		}
+		values[limitFieldDL] = kibString(dl)
+		values[limitFieldUL] = kibString(ul)
+	}
+
+	m.limitsInputs = make([]textinput.Model, limitFieldCount)
+	for i := range m.limitsInputs {
+		m.limitsInputs[i] = textinput.New()
+		m.limitsInputs[i].Placeholder = "unlimited"
+		m.limitsInputs[i].CharLimit = 10
+		m.limitsInputs[i].Width = 20
+		m.limitsInputs[i].SetValue(values[i])
+	}
+	m.limitsField = limitFieldDL
+	m.limitsInputs[m.limitsField].Focus()
+	m.showLimits = true
+	m.searchInput.Blur()
+	return m, textinput.Blink
+}
+
+// handleLimitsKey handles keyboard input for the speed limit modal
+func (m Model) handleLimitsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showLimits = false
+		return m, handled()
+
+	case "tab", "shift+tab", "up", "down":
+		m.limitsInputs[m.limitsField].Blur()
+		m.limitsField = (m.limitsField + 1) % limitFieldCount
+		m.limitsInputs[m.limitsField].Focus()
+		return m, textinput.Blink
+
+	case "enter":
+		dl, err := parseLimitKiB(m.limitsInputs[limitFieldDL].Value())
+		if err != nil {
+			m.statusMsg = "Download " + err.Error()
+			return m, handled()
+		}
+		ul, err := parseLimitKiB(m.limitsInputs[limitFieldUL].Value())
+		if err != nil {
+			m.statusMsg = "Upload " + err.Error()
+			return m, handled()
+		}
+		m.showLimits = false
+		return m, m.applyLimits(dl, ul)
+	}
+
+	var cmd tea.Cmd
+	m.limitsInputs[m.limitsField], cmd = m.limitsInputs[m.limitsField].Update(msg)
+	if cmd == nil {
+		cmd = handled()
+	}
+	return m, cmd
+}
+
+// applyLimits sends the modal's limits to qBittorrent
+func (m Model) applyLimits(dl, ul int64) tea.Cmd {
+	client := m.qbitClient
+	if !m.limitsGlobal {
+		return m.bulkHashAction("Limited", m.limitsHashes, m.limitsTarget, func(ctx context.Context, hashes []string) error {
+			if err := client.SetTorrentDownloadLimit(ctx, hashes, dl); err != nil {
+				return err
+			}
+			return client.SetTorrentUploadLimit(ctx, hashes, ul)
+		})
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		name := fmt.Sprintf("↓ %s ↑ %s", limitString(dl), limitString(ul))
+		if err := client.SetDownloadLimit(ctx, dl); err != nil {
+			return torrentActionMsg{action: "Set global limits", name: name, err: err}
+		}
+		err := client.SetUploadLimit(ctx, ul)
+		return torrentActionMsg{action: "Set global limits", name: name, err: err}
+	}
+}
+
+// limitString formats a bytes/s limit for display
+func limitString(limit int64) string {
+	if limit <= 0 {
+		return "∞"
+	}
+	return formatSpeed(limit)
+}
+
+// toggleAltSpeeds switches qBittorrent's alternative ("turtle") speed limits
+func (m Model) toggleAltSpeeds() tea.Cmd {
+	client := m.qbitClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := client.ToggleSpeedLimitsMode(ctx); err != nil {
+			return altSpeedMsg{err: err}
+		}
+		enabled, err := client.SpeedLimitsMode(ctx)
+		return altSpeedMsg{enabled: enabled, err: err}
+	}
+}
+
+// renderLimitsModal renders the speed limit modal
+func (m Model) renderLimitsModal() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(60)
+
+	var content strings.Builder
+	if m.limitsGlobal {
+		content.WriteString(styles.Title.Render("Global Speed Limits"))
+		content.WriteString("\n\n")
+		if m.serverState.UseAltSpeedLimits {
+			content.WriteString(styles.HealthMed.Render("  Alternative limits are active"))
+			content.WriteString("\n\n")
+		}
+	} else {
+		content.WriteString(styles.Title.Render("Torrent Speed Limits"))
+		content.WriteString("\n\n")
+		content.WriteString(styles.Muted.Render("  " + TruncateString(m.limitsTarget, 50)))
+		content.WriteString("\n\n")
+	}
+
+	for field := 0; field < limitFieldCount; field++ {
+		label := limitFieldLabels[field]
+		if field == m.limitsField {
+			content.WriteString(styles.Title.Render(PadRight("› "+label+":", 20)))
+		} else {
+			content.WriteString(styles.Muted.Render(PadRight("  "+label+":", 20)))
+		}
+		content.WriteString(" " + m.limitsInputs[field].View() + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styles.Muted.Render("  [tab]Field [enter]Apply [esc]Cancel  (empty = unlimited)"))
+
+	return modalStyle.Render(content.String())
+}
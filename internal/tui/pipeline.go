@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/pipeline"
+)
+
+// autoMoveMsg reports what the pipeline did with a completed torrent
+type autoMoveMsg struct {
+	hash   string
+	name   string
+	status pipeline.Status // Moved or review
+	detail string          // Destination, or why it needs review
+}
+
+// runPipeline copies the next newly completed torrent into the Plex
+// libraries, one at a time. The torrent keeps seeding from where it is.
+// Torrents already completed when the pipeline is first turned on are left
+// alone, and nothing happens until the libraries are usable.
+func (m *Model) runPipeline() tea.Cmd {
+	if !m.cfg.Plex.AutoMove || m.pipeline == nil || m.autoMoving {
+		return nil
+	}
+	if !m.pipeline.IsStarted() {
+		m.pipeline.Baseline(m.compAll, time.Now())
+		m.savePipeline()
+		return nil
+	}
+	if m.checkPlexLibraries() != "" {
+		return nil
+	}
+	t, ok := m.pipeline.Next(m.compAll)
+	if !ok {
+		return nil
+	}
+
+	// Recorded before moving, so a move the app is closed during is
+	// reviewed rather than repeated. If that can't be recorded, it is left
+	// for the user.
+	m.pipeline.Set(t.Hash, t.Name, pipeline.StatusMoving, "", time.Now())
+	if !m.savePipeline() {
+		m.pipeline.Set(t.Hash, t.Name, pipeline.StatusReview, "pipeline state couldn't be saved", time.Now())
+		return nil
+	}
+	m.autoMoving = true
+
+	mover := m.seedingMover()
+	minConfidence := pipeline.MinConfidence(m.cfg.Plex)
+	return func() tea.Msg {
+		msg := autoMoveMsg{hash: t.Hash, name: t.Name, status: pipeline.StatusReview}
+		sourcePath, detection := detectMedia(t)
+		if ok, reason := pipeline.Decide(detection, minConfidence); !ok {
+			msg.detail = reason
+			return msg
+		}
+		result, err := mover.MoveToLibraryWithProgress(context.Background(), sourcePath, detection, false, nil)
+		if err != nil {
+			msg.detail = fmt.Sprintf("move failed: %v", err)
+			return msg
+		}
+		msg.status, msg.detail = pipeline.StatusMoved, result.DestinationPath
+		return msg
+	}
+}
+
+// handleAutoMove records what the pipeline did and moves on to the next
+// completed torrent
+func (m *Model) handleAutoMove(msg autoMoveMsg) tea.Cmd {
+	m.autoMoving = false
+	m.pipeline.Set(msg.hash, msg.name, msg.status, msg.detail, time.Now())
+
+	if msg.status == pipeline.StatusMoved {
+		m.statusMsg = fmt.Sprintf("Auto-moved to Plex: %s", TruncateString(msg.detail, 40))
+	} else {
+		m.statusMsg = fmt.Sprintf("%s needs review: %s (3 to view)", TruncateString(msg.name, 30), msg.detail)
+	}
+	m.savePipeline()
+	return m.runPipeline()
+}
+
+// savePipeline writes the pipeline state, reporting a failure in the
+// status bar. It returns whether the state was saved.
+func (m *Model) savePipeline() bool {
+	if err := m.pipeline.Save(); err != nil {
+		m.statusMsg = fmt.Sprintf("Couldn't save pipeline state: %v", err)
+		return false
+	}
+	return true
+}
+
+// recordManualMove takes a torrent moved from the move modal out of the
+// pipeline, so it isn't moved or reviewed again
+func (m *Model) recordManualMove(hash, name, dest string) {
+	if m.pipeline == nil || hash == "" {
+		return
+	}
+	m.pipeline.Set(hash, name, pipeline.StatusMoved, dest, time.Now())
+	m.savePipeline()
+}
+
+// dismissReview takes the selected torrent out of the review queue
+func (m *Model) dismissReview() {
+	t, ok := m.selectedTorrent()
+	if !ok || m.pipeline == nil || !m.pipeline.InReview(t.Hash) {
+		m.statusMsg = "Not waiting for review"
+		return
+	}
+	m.pipeline.Dismiss(t.Hash, time.Now())
+	m.statusMsg = "Dismissed " + TruncateString(t.Name, 40)
+	m.savePipeline()
+}
+
+// reviewCount returns how many completed torrents wait for review
+func (m Model) reviewCount() int {
+	if m.pipeline == nil {
+		return 0
+	}
+	n := 0
+	for _, t := range m.compAll {
+		if m.pipeline.InReview(t.Hash) {
+			n++
+		}
+	}
+	return n
+}
+
+// renderReviewLine summarizes the review queue above the Completed tab,
+// with why the selected torrent needs review
+func (m Model) renderReviewLine() string {
+	styles := GetStyles()
+	n := m.reviewCount()
+	line := styles.HealthMed.Render(fmt.Sprintf("? %d awaiting review", n))
+	hint := "[m]Move [D]Dismiss"
+	if t, ok := m.selectedTorrent(); ok {
+		if e, ok := m.pipeline.Get(t.Hash); ok && e.Status == pipeline.StatusReview {
+			hint = e.Detail + " · " + hint
+		}
+	}
+	return line + styles.Muted.Render(" · "+hint)
+}
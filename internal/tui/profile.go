@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// profileTestRows is how many parsed rows the profile test modal lists
+const profileTestRows = 12
+
+// profileTestMsg is the outcome of testing a source's scraping profile
+type profileTestMsg struct {
+	source string
+	target string // Query or file the profile was tested against
+	rows   []scraper.Torrent
+	err    error
+}
+
+// newSourceScraper creates the scraper for a configured source. A generic
+// source with an invalid profile falls back to the heuristics; the error is
+// returned so it can be shown.
+func newSourceScraper(src config.SourceConfig) (scraper.Scraper, error) {
+	if src.Type == config.SourceTypeTorznab {
+		return scraper.NewTorznabScraper(src.Name, src.URL, src.APIKey, src.Categories), nil
+	}
+	s := scraper.NewGenericScraper(src.Name, src.URL)
+	if src.Profile == nil {
+		return s, nil
+	}
+	p, err := scraper.NewProfile(*src.Profile)
+	if err != nil {
+		return s, fmt.Errorf("profile: %w", err)
+	}
+	s.SetProfile(p)
+	return s, nil
+}
+
+// startProfileTest prompts for what to test the selected source's profile
+// against: a search query, or a saved HTML page
+func (m *Model) startProfileTest() {
+	if m.srcCursor >= len(m.sources) {
+		return
+	}
+	src := m.sources[m.srcCursor]
+	switch {
+	case src.Profile == nil:
+		m.statusMsg = fmt.Sprintf("%s has no scraping profile (add [sources.profile] to the config)", src.Name)
+		return
+	case src.ProfileErr != nil:
+		m.statusMsg = fmt.Sprintf("Invalid %v", src.ProfileErr)
+		return
+	}
+	m.testingProfile = true
+	m.profileInput.SetValue("test")
+	m.profileInput.CursorEnd()
+	m.profileInput.Focus()
+}
+
+// handleProfileTestKey handles keyboard input for the profile test prompt
+func (m Model) handleProfileTestKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.testingProfile = false
+		m.profileInput.Blur()
+		return m, handled()
+	case "enter":
+		target := strings.TrimSpace(m.profileInput.Value())
+		if target == "" || m.srcCursor >= len(m.sources) {
+			return m, handled()
+		}
+		m.testingProfile = false
+		m.profileInput.Blur()
+		m.validatingURL = true
+		return m, tea.Batch(m.spinner.Tick, testProfile(m.sources[m.srcCursor], target))
+	}
+
+	var cmd tea.Cmd
+	m.profileInput, cmd = m.profileInput.Update(msg)
+	if cmd == nil {
+		cmd = handled()
+	}
+	return m, cmd
+}
+
+// profileTestFile returns the path of a saved page to test against, or ""
+// if target isn't an existing file (and so is a query)
+func profileTestFile(target string) string {
+	path := target
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		return path
+	}
+	return ""
+}
+
+// testProfile parses a saved page with the source's profile, or searches
+// the live site with it
+func testProfile(src SearchSource, target string) tea.Cmd {
+	return func() tea.Msg {
+		msg := profileTestMsg{source: src.Name, target: target}
+		p, err := scraper.NewProfile(*src.Profile)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+
+		if path := profileTestFile(target); path != "" {
+			f, err := os.Open(path)
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			defer f.Close()
+			msg.rows, msg.err = p.ParseFile(f, src.Name)
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		s := scraper.NewGenericScraper(src.Name, src.URL)
+		s.SetProfile(p)
+		msg.rows, msg.err = s.Search(ctx, target)
+		return msg
+	}
+}
+
+// handleProfileTest shows the rows a profile test parsed
+func (m *Model) handleProfileTest(msg profileTestMsg) {
+	m.validatingURL = false
+	m.profileTest = msg
+	m.showProfileTest = true
+}
+
+// renderProfileTest renders the profile test results modal
+func (m Model) renderProfileTest() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(90)
+
+	res := m.profileTest
+	var content strings.Builder
+	content.WriteString(styles.Title.Render("Profile Test: " + TruncateString(res.source, 50)))
+	content.WriteString("\n")
+	content.WriteString(styles.Muted.Render("  " + TruncateString(res.target, 80)))
+	content.WriteString("\n\n")
+
+	switch {
+	case res.err != nil:
+		content.WriteString(styles.Error.Render(TruncateString(res.err.Error(), 84)))
+		content.WriteString("\n")
+	case len(res.rows) == 0:
+		content.WriteString(styles.HealthBad.Render("No rows parsed. Check the row selector."))
+		content.WriteString("\n")
+	default:
+		content.WriteString(styles.HealthGood.Render(fmt.Sprintf("%d rows parsed", len(res.rows))))
+		content.WriteString("\n\n")
+
+		nameWidth := 44
+		header := fmt.Sprintf("%s %s %s %s %s",
+			PadRight("NAME", nameWidth), PadLeft("SIZE", 10), PadLeft("SEED", 6), PadLeft("LEECH", 6), PadRight(" LINKS", 8))
+		content.WriteString(styles.SortedHeader.Render(header))
+		content.WriteString("\n")
+		for i, t := range res.rows {
+			if i >= profileTestRows {
+				content.WriteString(styles.Muted.Render(fmt.Sprintf("... and %d more", len(res.rows)-profileTestRows)))
+				content.WriteString("\n")
+				break
+			}
+			// Which links the row gave: magnet/.torrent and detail page
+			links := " "
+			if t.Magnet != "" {
+				links += "M"
+			}
+			if t.InfoURL != "" {
+				links += "D"
+			}
+			size := t.Size
+			if size == "" {
+				size = "-"
+			}
+			row := fmt.Sprintf("%s %s %s %s %s",
+				PadRight(TruncateString(t.Name, nameWidth), nameWidth),
+				PadLeft(TruncateString(size, 10), 10),
+				PadLeft(fmt.Sprintf("%d", t.Seeders), 6),
+				PadLeft(fmt.Sprintf("%d", t.Leechers), 6),
+				PadRight(links, 8))
+			content.WriteString(styles.TableRow.Render(row))
+			content.WriteString("\n")
+		}
+		content.WriteString(styles.Muted.Render("M = magnet or .torrent link, D = detail page"))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styles.Muted.Render("  [esc]Close"))
+	return modalStyle.Render(content.String())
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// dlSortQueue is the Downloads sort column for queue position
+const dlSortQueue = 8
+
+// queueLabel formats a torrent's queue position for the Downloads tab
+func queueLabel(t qbit.TorrentInfo) string {
+	switch {
+	case t.ForceStart:
+		return "F"
+	case t.QueuePosition > 0:
+		return strconv.Itoa(t.QueuePosition)
+	}
+	return "-"
+}
+
+// queueLess orders torrents by queue position, unqueued torrents last
+func queueLess(a, b qbit.TorrentInfo) bool {
+	if (a.QueuePosition > 0) != (b.QueuePosition > 0) {
+		return a.QueuePosition > 0
+	}
+	return a.QueuePosition < b.QueuePosition
+}
+
+// moveInQueue shifts the target torrents in qBittorrent's queue. The cursor
+// stays on the torrent under it, wherever it ends up after the refresh.
+func (m *Model) moveInQueue(action string, fn func(ctx context.Context, client *qbit.Client, hashes []string) error) tea.Cmd {
+	if !m.serverState.Queueing {
+		m.statusMsg = "Torrent queueing is disabled in qBittorrent"
+		return handled()
+	}
+	if t, ok := m.selectedTorrent(); ok {
+		m.cursorHash = t.Hash
+	}
+	client := m.qbitClient
+	return m.bulkAction(action, m.targetTorrents(), func(ctx context.Context, hashes []string) error {
+		return fn(ctx, client, hashes)
+	})
+}
+
+// queueUp moves the targets one position up the queue
+func (m *Model) queueUp() tea.Cmd {
+	return m.moveInQueue("Moved up", func(ctx context.Context, client *qbit.Client, hashes []string) error {
+		return client.QueueUp(ctx, hashes...)
+	})
+}
+
+// queueDown moves the targets one position down the queue
+func (m *Model) queueDown() tea.Cmd {
+	return m.moveInQueue("Moved down", func(ctx context.Context, client *qbit.Client, hashes []string) error {
+		return client.QueueDown(ctx, hashes...)
+	})
+}
+
+// queueTop moves the targets to the top of the queue
+func (m *Model) queueTop() tea.Cmd {
+	return m.moveInQueue("Moved to top", func(ctx context.Context, client *qbit.Client, hashes []string) error {
+		return client.QueueTop(ctx, hashes...)
+	})
+}
+
+// queueBottom moves the targets to the bottom of the queue
+func (m *Model) queueBottom() tea.Cmd {
+	return m.moveInQueue("Moved to bottom", func(ctx context.Context, client *qbit.Client, hashes []string) error {
+		return client.QueueBottom(ctx, hashes...)
+	})
+}
+
+// toggleForceStart force-starts the targets, or returns them to the queue
+// if they are all force-started already
+func (m Model) toggleForceStart() tea.Cmd {
+	targets := m.targetTorrents()
+	allForced := len(targets) > 0
+	for _, t := range targets {
+		if !t.ForceStart {
+			allForced = false
+		}
+	}
+
+	client := m.qbitClient
+	if allForced {
+		return m.bulkAction("Queued", targets, func(ctx context.Context, hashes []string) error {
+			return client.SetForceStart(ctx, hashes, false)
+		})
+	}
+	return m.bulkAction("Force started", targets, func(ctx context.Context, hashes []string) error {
+		return client.SetForceStart(ctx, hashes, true)
+	})
+}
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/plex"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// relocateMsg reports a relocation handed over to qBittorrent
+type relocateMsg struct {
+	dest string
+	err  error
+}
+
+// relocateFilesMsg carries a torrent's files for the relocation preview
+type relocateFilesMsg struct {
+	hash  string
+	files []plex.TorrentFile
+	err   error
+}
+
+// relocateStepTimeout bounds each call of a relocation, such as a rename
+// and the wait for qBittorrent to apply it
+const relocateStepTimeout = 30 * time.Second
+
+// startRelocate has qBittorrent rename the torrent's files into the Plex
+// layout and move them into the library, so it keeps seeding from there.
+// If a step fails, the renames already applied are undone.
+func (m Model) startRelocate() (tea.Model, tea.Cmd) {
+	m.moveInProgress = true
+	m.moveError = ""
+
+	detection := m.moveDetection
+	detection.Type = m.moveMediaType
+	detection.Title = m.moveTitleInput.Value()
+
+	client := m.qbitClient
+	hash := m.moveHash
+	config := m.plexMoveConfig()
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), relocateStepTimeout)
+		files, err := client.Files(ctx, hash)
+		cancel()
+		if err != nil {
+			return relocateMsg{err: fmt.Errorf("list files: %w", err)}
+		}
+		r, err := plex.PlanRelocation(torrentFiles(files), detection, config)
+		if err != nil {
+			return relocateMsg{err: err}
+		}
+		var applied []plex.Rename
+		for _, rn := range r.Renames {
+			if err := applyRename(client, hash, rn); err != nil {
+				return relocateMsg{err: fmt.Errorf("rename %s: %w%s", rn.Old, err, undoRenames(client, hash, applied))}
+			}
+			applied = append(applied, rn)
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), relocateStepTimeout)
+		defer cancel()
+		if err := client.SetLocation(ctx, []string{hash}, r.Location); err != nil {
+			return relocateMsg{err: fmt.Errorf("set location: %w%s", err, undoRenames(client, hash, applied))}
+		}
+		return relocateMsg{dest: r.DestinationPath}
+	}
+}
+
+// applyRename renames a file or folder of a torrent and waits until
+// qBittorrent has applied it
+func applyRename(client *qbit.Client, hash string, rn plex.Rename) error {
+	ctx, cancel := context.WithTimeout(context.Background(), relocateStepTimeout)
+	defer cancel()
+	rename := client.RenameFile
+	if rn.Folder {
+		rename = client.RenameFolder
+	}
+	if err := rename(ctx, hash, rn.Old, rn.New); err != nil {
+		return err
+	}
+	return waitForRename(ctx, client, hash, rn.New)
+}
+
+// undoRenames reverts the renames applied so far, last first, and says how
+// that went for the error message
+func undoRenames(client *qbit.Client, hash string, applied []plex.Rename) string {
+	if len(applied) == 0 {
+		return ""
+	}
+	for i := len(applied) - 1; i >= 0; i-- {
+		rn := applied[i]
+		if err := applyRename(client, hash, plex.Rename{Folder: rn.Folder, Old: rn.New, New: rn.Old}); err != nil {
+			return fmt.Sprintf(" (%d of %d renames still applied, undo failed: %v)", i+1, len(applied), err)
+		}
+	}
+	return fmt.Sprintf(" (%d applied renames undone)", len(applied))
+}
+
+// waitForRename waits until qBittorrent lists a renamed file or folder.
+// Renames are applied in the background, and a later rename or the move
+// would otherwise see the old paths.
+func waitForRename(ctx context.Context, client *qbit.Client, hash, newPath string) error {
+	for {
+		files, err := client.Files(ctx, hash)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.Name == newPath || strings.HasPrefix(f.Name, newPath+"/") {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// handleRelocate shows the outcome of a relocation in the move modal
+func (m *Model) handleRelocate(msg relocateMsg) tea.Cmd {
+	m.moveInProgress = false
+	if msg.err != nil {
+		m.moveError = msg.err.Error()
+		return nil
+	}
+	m.moveError = "✓ qBittorrent is moving it to: " + msg.dest
+	m.statusMsg = fmt.Sprintf("Relocating to Plex: %s", TruncateString(msg.dest, 40))
+	m.recordManualMove(m.moveHash, m.moveName, msg.dest)
+	m.moveComplete = true
+	m.moveShimmerPos = 0
+	return m.tickMoveShimmer()
+}
+
+// fetchRelocateFiles lists the torrent's files in qBittorrent for the
+// preview, as the relocation will
+func (m Model) fetchRelocateFiles() tea.Cmd {
+	client := m.qbitClient
+	hash := m.moveHash
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		files, err := client.Files(ctx, hash)
+		if err != nil {
+			return relocateFilesMsg{hash: hash, err: err}
+		}
+		return relocateFilesMsg{hash: hash, files: torrentFiles(files)}
+	}
+}
+
+// handleRelocateFiles updates the preview with the torrent's files, if the
+// modal is still open for it
+func (m *Model) handleRelocateFiles(msg relocateFilesMsg) {
+	if !m.showMoveModal || msg.hash != m.moveHash {
+		return
+	}
+	if msg.err != nil {
+		m.moveError = fmt.Sprintf("list files: %v", msg.err)
+		return
+	}
+	m.moveFiles = msg.files
+	m.updateMoveDestPreview()
+}
+
+// relocatePreview returns where a relocation would put the torrent, worked
+// out from qBittorrent's file list
+func (m Model) relocatePreview(detection plex.DetectionResult) string {
+	if m.moveFiles == nil {
+		return "Listing the torrent's files..."
+	}
+	r, err := plex.PlanRelocation(m.moveFiles, detection, m.plexMoveConfig())
+	if err != nil {
+		return err.Error()
+	}
+	return r.DestinationPath
+}
+
+// torrentFiles converts qBittorrent's file list for the relocation planner
+func torrentFiles(files []qbit.TorrentFile) []plex.TorrentFile {
+	out := make([]plex.TorrentFile, len(files)) // Not nil, even if empty
+	for i, f := range files {
+		out[i] = plex.TorrentFile{Path: f.Name, Size: f.Size}
+	}
+	return out
+}
@@ -0,0 +1,390 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/quality"
+	"github.com/litescript/ls-torrent-tui/internal/release"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+)
+
+// Search results sort columns past the health column
+const (
+	searchSortAge   = 5 // Upload age
+	searchSortScore = 6 // Quality profile score
+)
+
+const day = 24 * time.Hour
+
+// resultFilter is a filter of the Results view. Filters come from the
+// query ("ubuntu size:>2GB") or the 'a'/'z' presets and are kept for the
+// session; one can be switched off without losing it.
+type resultFilter struct {
+	cond scraper.Condition
+	off  bool
+}
+
+// ageFilters are the maximum ages 'a' cycles through ("" = any)
+var ageFilters = []string{"", "1d", "7d", "30d", "1y"}
+
+// sizeFilters are the size ranges 'z' cycles through, as query terms
+var sizeFilters = [][]string{
+	nil,
+	{"size:<1GB"},
+	{"size:>=1GB", "size:<5GB"},
+	{"size:>=5GB", "size:<20GB"},
+	{"size:>=20GB"},
+}
+
+// setResultFilters adds filters from a query. A filter replaces those on
+// the same key, except that excluded words add up.
+func (m *Model) setResultFilters(conds []scraper.Condition) {
+	replaced := make(map[string]bool)
+	for _, c := range conds {
+		if c.Key != scraper.KeyExclude {
+			replaced[c.Key] = true
+		}
+	}
+	m.searchFilters = slices.DeleteFunc(m.searchFilters, func(f resultFilter) bool {
+		if f.cond.Key == scraper.KeyExclude {
+			return slices.ContainsFunc(conds, func(c scraper.Condition) bool { return c == f.cond })
+		}
+		return replaced[f.cond.Key]
+	})
+	for _, c := range conds {
+		m.searchFilters = append(m.searchFilters, resultFilter{cond: c})
+	}
+	m.filterCursor = min(m.filterCursor, max(len(m.searchFilters)-1, 0))
+}
+
+// removeResultFilters removes the filters on a key
+func (m *Model) removeResultFilters(key string) {
+	m.searchFilters = slices.DeleteFunc(m.searchFilters, func(f resultFilter) bool {
+		return f.cond.Key == key
+	})
+	m.filterCursor = min(m.filterCursor, max(len(m.searchFilters)-1, 0))
+}
+
+// activeFilters returns the conditions of the filters switched on
+func (m Model) activeFilters() []scraper.Condition {
+	var conds []scraper.Condition
+	for _, f := range m.searchFilters {
+		if !f.off {
+			conds = append(conds, f.cond)
+		}
+	}
+	return conds
+}
+
+// filterTerms returns the filters on a key as typed, e.g. ["size:<1GB"]
+func (m Model) filterTerms(key string) []string {
+	var terms []string
+	for _, f := range m.searchFilters {
+		if f.cond.Key == key && !f.off {
+			terms = append(terms, f.cond.String())
+		}
+	}
+	return terms
+}
+
+// applyResultFilters rebuilds the results list from every result of the
+// search, keeping the cursor on the selected result
+func (m *Model) applyResultFilters() {
+	var selected scraper.Torrent
+	if m.cursor < len(m.results) {
+		selected = m.results[m.cursor]
+	}
+
+	m.results = scraper.Apply(m.activeFilters(), m.searchAll, time.Now())
+	sortSearchResults(m.results, m.searchSortCol, m.searchSortAsc, m.qualityProfile())
+
+	m.cursor = min(m.cursor, max(len(m.results)-1, 0))
+	for i, t := range m.results {
+		if selected.Name != "" && t.Name == selected.Name && t.Magnet == selected.Magnet {
+			m.cursor = i
+		}
+	}
+}
+
+// cycleAgeFilter switches to the next maximum age. Any other age filter
+// is replaced by the first preset.
+func (m *Model) cycleAgeFilter() {
+	next := 1
+	if terms := m.filterTerms(scraper.KeyAge); len(terms) == 1 {
+		for i, age := range ageFilters {
+			if age != "" && terms[0] == scraper.KeyAge+":<="+age {
+				next = (i + 1) % len(ageFilters)
+			}
+		}
+	}
+
+	m.removeResultFilters(scraper.KeyAge)
+	if next == 0 {
+		m.statusMsg = "Age filter: any"
+	} else {
+		cond, _ := scraper.ParseCondition(scraper.KeyAge + ":<=" + ageFilters[next])
+		m.setResultFilters([]scraper.Condition{cond})
+		m.statusMsg = "Age filter: " + cond.String()
+	}
+	m.applyResultFilters()
+}
+
+// cycleSizeFilter switches to the next size range. Any other size filter
+// is replaced by the first preset.
+func (m *Model) cycleSizeFilter() {
+	next := 1
+	terms := m.filterTerms(scraper.KeySize)
+	for i, preset := range sizeFilters {
+		if len(terms) > 0 && slices.Equal(terms, preset) {
+			next = (i + 1) % len(sizeFilters)
+		}
+	}
+
+	m.removeResultFilters(scraper.KeySize)
+	var conds []scraper.Condition
+	for _, term := range sizeFilters[next] {
+		cond, _ := scraper.ParseCondition(term)
+		conds = append(conds, cond)
+	}
+	m.setResultFilters(conds)
+	m.applyResultFilters()
+	if next == 0 {
+		m.statusMsg = "Size filter: any"
+	} else {
+		m.statusMsg = "Size filter: " + strings.Join(sizeFilters[next], " ")
+	}
+}
+
+// toggleResultFilter switches the focused filter off or back on
+func (m *Model) toggleResultFilter() {
+	if m.filterCursor >= len(m.searchFilters) {
+		return
+	}
+	f := &m.searchFilters[m.filterCursor]
+	f.off = !f.off
+	m.applyResultFilters()
+	if f.off {
+		m.statusMsg = "Filter off: " + f.cond.String()
+	} else {
+		m.statusMsg = "Filter on: " + f.cond.String()
+	}
+}
+
+// deleteResultFilter removes the focused filter
+func (m *Model) deleteResultFilter() {
+	if m.filterCursor >= len(m.searchFilters) {
+		return
+	}
+	removed := m.searchFilters[m.filterCursor].cond
+	m.searchFilters = slices.Delete(m.searchFilters, m.filterCursor, m.filterCursor+1)
+	m.filterCursor = min(m.filterCursor, max(len(m.searchFilters)-1, 0))
+	if len(m.searchFilters) == 0 {
+		m.filterFocus = false
+	}
+	m.applyResultFilters()
+	m.statusMsg = "Removed filter: " + removed.String()
+}
+
+// handleFilterBarKey handles keys while the filter bar is focused
+func (m Model) handleFilterBarKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "esc", "f":
+		m.filterFocus = false
+	case "left", "h":
+		if m.filterCursor > 0 {
+			m.filterCursor--
+		}
+	case "right", "l":
+		if m.filterCursor < len(m.searchFilters)-1 {
+			m.filterCursor++
+		}
+	case " ", "space", "enter":
+		m.toggleResultFilter()
+	case "x", "delete", "backspace":
+		m.deleteResultFilter()
+	}
+	return m, handled()
+}
+
+// renderFilterBar renders the filters as chips, with how many results they
+// hide; "" when there are none. Filters switched off are dimmed.
+func (m Model) renderFilterBar() string {
+	if len(m.searchFilters) == 0 {
+		return ""
+	}
+	styles := GetStyles()
+	var b strings.Builder
+	b.WriteString(styles.Muted.Render("Filters:"))
+	for i, f := range m.searchFilters {
+		chip := " " + f.cond.String() + " "
+		switch {
+		case m.filterFocus && i == m.filterCursor:
+			chip = styles.TableSelected.Render(chip)
+		case f.off:
+			chip = styles.Muted.Render(chip)
+		default:
+			chip = styles.HealthMed.Render(chip)
+		}
+		b.WriteString(" " + chip)
+	}
+	b.WriteString(styles.Muted.Render(fmt.Sprintf("  (%d of %d shown)", len(m.results), len(m.searchAll))))
+	return b.String()
+}
+
+// hasQualityProfile reports whether results are scored by a profile
+func (m Model) hasQualityProfile() bool {
+	return m.qualityIdx >= 0 && m.qualityIdx < len(m.cfg.Quality)
+}
+
+// qualityProfile returns the active quality profile; the zero profile,
+// which ranks by health alone, if there is none
+func (m Model) qualityProfile() config.QualityProfile {
+	if m.hasQualityProfile() {
+		return m.cfg.Quality[m.qualityIdx]
+	}
+	return config.QualityProfile{}
+}
+
+// cycleQualityProfile switches to the next quality profile, then to none.
+// Choosing a profile ranks the results by it, best first.
+func (m *Model) cycleQualityProfile() {
+	if len(m.cfg.Quality) == 0 {
+		m.statusMsg = "No quality profiles configured (add [[quality]] to the config)"
+		return
+	}
+	m.qualityIdx++
+	if m.qualityIdx >= len(m.cfg.Quality) {
+		m.qualityIdx = -1
+		m.statusMsg = "Quality profile: none"
+		return
+	}
+	m.searchSortCol, m.searchSortAsc = searchSortScore, false
+	m.saveSortSettings()
+	m.applyResultFilters()
+	m.statusMsg = "Quality profile: " + m.qualityProfile().Name
+}
+
+// grabBest sends the top-scoring healthy result shown to qBittorrent, with
+// its source's add defaults
+func (m *Model) grabBest() tea.Cmd {
+	t, score, ok := quality.Best(m.qualityProfile(), m.results, time.Now())
+	if !ok {
+		m.statusMsg = "No result fits the quality profile"
+		return nil
+	}
+	for i, r := range m.results {
+		if resultKey(r) == resultKey(t) {
+			m.cursor = i
+		}
+	}
+	m.statusMsg = fmt.Sprintf("Grabbing %s (%s)", TruncateString(t.Name, 40), score.Reason())
+	return m.downloadResult(t, addOptionsFromDefaults(m.sourceDefaults(t.Source), m.cfg.Downloads.Path))
+}
+
+// resultScore formats a score for the SCORE column, "✗" if rejected
+func resultScore(s quality.Score) string {
+	if !s.OK() {
+		return "✗"
+	}
+	return strconv.Itoa(s.Points)
+}
+
+// resultScoreReason explains a result's score under the active profile,
+// for the details view; "" without one
+func (m Model) resultScoreReason(t scraper.Torrent) string {
+	if !m.hasQualityProfile() {
+		return ""
+	}
+	p := m.qualityProfile()
+	s := quality.Evaluate(p, t, time.Now())
+	return fmt.Sprintf("%s: %s %s", p.Name, resultScore(s), s.Reason())
+}
+
+// formatAge formats an upload age compactly: "45m", "5h", "3d", "2mo", "1y"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 60*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	default:
+		return fmt.Sprintf("%dy", int(d/(365*day)))
+	}
+}
+
+// resultAge formats how old a search result is, "-" if unknown
+func resultAge(t scraper.Torrent, now time.Time) string {
+	if t.PublishedAt.IsZero() {
+		return "-"
+	}
+	return formatAge(t.Age(now))
+}
+
+// resultMetadata summarises a result's upload details for the details
+// view, e.g. "Movies · by someone · 2024-03-02 · grabs: 512"
+func resultMetadata(t scraper.Torrent) string {
+	var parts []string
+	if t.Category != "" {
+		parts = append(parts, t.Category)
+	}
+	if t.Uploader != "" {
+		parts = append(parts, "by "+t.Uploader)
+	}
+	if !t.PublishedAt.IsZero() {
+		parts = append(parts, t.PublishedAt.Local().Format("2006-01-02"))
+	}
+	keys := slices.Sorted(maps.Keys(t.Extra))
+	for _, k := range keys {
+		parts = append(parts, k+": "+t.Extra[k])
+	}
+	return strings.Join(parts, " · ")
+}
+
+// resultRelease summarises what the release name says, for the details
+// view, e.g. "Show Name · S01E02 · 1080p WEB-DL x265 · HDR10 · DDP5.1 · group GRP"
+func resultRelease(t scraper.Torrent) string {
+	rel := release.Parse(t.Name)
+	var parts []string
+	for _, s := range []string{rel.Title, rel.EpisodeString(), rel.Quality()} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if rel.Year > 0 && rel.Kind == release.KindMovie {
+		parts = slices.Insert(parts, min(1, len(parts)), strconv.Itoa(rel.Year))
+	}
+	for _, list := range [][]string{rel.HDR, rel.Audio, rel.Tags} {
+		if len(list) > 0 {
+			parts = append(parts, strings.Join(list, " "))
+		}
+	}
+	if rel.Proper {
+		parts = append(parts, "PROPER")
+	}
+	if rel.Repack {
+		parts = append(parts, "REPACK")
+	}
+	if rel.Group != "" {
+		parts = append(parts, "group "+rel.Group)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// orDash returns s, or "-" if it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,453 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+	"github.com/litescript/ls-torrent-tui/internal/searches"
+	"github.com/litescript/ls-torrent-tui/internal/theme"
+)
+
+// savedSearchMsg reports a background run of a saved search
+type savedSearchMsg struct {
+	name    string
+	keys    []string // resultKey of every result
+	partial bool     // Some sources failed
+	err     error
+}
+
+// startSearch runs a query typed into the search input: filters alone
+// are applied to the current results, anything else searches the sources
+func (m *Model) startSearch(value string) tea.Cmd {
+	q, err := scraper.ParseQuery(value)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Invalid filter %v", err)
+		return handled()
+	}
+	m.recordQuery(value)
+	if q.Text == "" {
+		// Only filters: apply them to the current results
+		m.setResultFilters(q.Filters)
+		m.applyResultFilters()
+		m.searchInput.Blur()
+		if len(m.searchAll) > 0 {
+			m.mode = viewResults
+		}
+		m.statusMsg = fmt.Sprintf("%d of %d results shown", len(m.results), len(m.searchAll))
+		return handled()
+	}
+	if m.cfg.VPN.Required && !m.vpnStatus.Connected {
+		m.statusMsg = "VPN required! Press V to connect"
+		return handled()
+	}
+	m.setResultFilters(q.Filters)
+	m.searching = true
+	m.err = nil
+	m.statusMsg = "Searching..."
+	m.searchInput.Blur()
+	m.lastQuery = value
+	return tea.Batch(m.spinner.Tick, m.doSearch(q.Text))
+}
+
+// recordQuery adds a query to the search history
+func (m *Model) recordQuery(query string) {
+	m.histPos = 0
+	if m.searchStore == nil {
+		return
+	}
+	m.searchStore.Add(query)
+	_ = m.searchStore.Save() // Ignore error, it's just persistence
+}
+
+// recallHistory steps through past queries in the search input: older
+// for up (delta 1), newer for down (delta -1). Stepping past the newest
+// restores what was typed before.
+func (m *Model) recallHistory(delta int) {
+	if m.searchStore == nil {
+		return
+	}
+	queries := m.searchStore.Queries()
+	pos := min(max(m.histPos+delta, 0), len(queries))
+	if pos == m.histPos {
+		return
+	}
+	if m.histPos == 0 {
+		m.histDraft = m.searchInput.Value()
+	}
+	m.histPos = pos
+	if pos == 0 {
+		m.searchInput.SetValue(m.histDraft)
+	} else {
+		m.searchInput.SetValue(queries[len(queries)-pos])
+	}
+	m.searchInput.CursorEnd()
+}
+
+// historyMatch returns the past query reverse search is on, "" if none
+func (m Model) historyMatch() string {
+	if m.searchStore == nil {
+		return ""
+	}
+	matches := m.searchStore.Find(m.histPattern)
+	if m.histMatch >= len(matches) {
+		return ""
+	}
+	return matches[m.histMatch]
+}
+
+// startHistorySearch begins a reverse search of the history (ctrl+r)
+func (m *Model) startHistorySearch() {
+	m.histSearching = true
+	m.histPattern = ""
+	m.histMatch = 0
+}
+
+// handleHistorySearchKey handles keys during reverse history search.
+// Typing narrows the search, ctrl+r steps to the next older match, enter
+// puts the match in the search input and esc leaves the input as it was.
+func (m Model) handleHistorySearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "ctrl+g":
+		m.histSearching = false
+	case "ctrl+r":
+		if m.searchStore != nil && m.histMatch < len(m.searchStore.Find(m.histPattern))-1 {
+			m.histMatch++
+		}
+	case "backspace":
+		if r := []rune(m.histPattern); len(r) > 0 {
+			m.histPattern = string(r[:len(r)-1])
+			m.histMatch = 0
+		}
+	case "enter", "tab", "right":
+		if match := m.historyMatch(); match != "" {
+			m.searchInput.SetValue(match)
+			m.searchInput.CursorEnd()
+		}
+		m.histSearching = false
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.histPattern += string(msg.Runes)
+			if msg.Type == tea.KeySpace {
+				m.histPattern += " "
+			}
+			m.histMatch = 0
+		}
+	}
+	return m, handled()
+}
+
+// renderHistorySearch renders the reverse search line in place of the
+// search input
+func (m Model) renderHistorySearch() string {
+	styles := GetStyles()
+	match := m.historyMatch()
+	if match == "" && m.histPattern != "" {
+		match = styles.Error.Render("no match")
+	}
+	return styles.Muted.Render(fmt.Sprintf("(history `%s`) ", m.histPattern)) + match
+}
+
+// newSavedResults returns how many new saved search results haven't been
+// looked at, for the Search tab badge
+func (m Model) newSavedResults() int {
+	if m.searchStore == nil {
+		return 0
+	}
+	return m.searchStore.TotalNew()
+}
+
+// canSearchInBackground reports whether saved searches may run right now
+func (m Model) canSearchInBackground() bool {
+	return !m.cfg.VPN.Required || m.vpnStatus.Connected
+}
+
+// runDueSearches re-runs the enabled saved searches whose interval has
+// passed since their last run
+func (m Model) runDueSearches() tea.Cmd {
+	if m.searchStore == nil || !m.canSearchInBackground() {
+		return nil
+	}
+	var cmds []tea.Cmd
+	now := time.Now()
+	for _, s := range m.cfg.Searches {
+		// A failed run waits its interval too
+		last := m.searchStore.LastRun(s.Name)
+		if tried := m.savedTried[s.Name]; tried.After(last) {
+			last = tried
+		}
+		if !s.Enabled || m.savedRunning[s.Name] || now.Sub(last) < searches.Interval(s) {
+			continue
+		}
+		cmds = append(cmds, m.runSavedSearch(s))
+	}
+	return tea.Batch(cmds...)
+}
+
+// runSavedSearch runs a saved search in the background, keeping the
+// results that pass its filters
+func (m Model) runSavedSearch(s config.SavedSearch) tea.Cmd {
+	var scrapers []scraper.Scraper
+	for _, src := range m.sources {
+		if src.Enabled && src.Scraper != nil {
+			scrapers = append(scrapers, src.Scraper)
+		}
+	}
+	if len(scrapers) == 0 {
+		return nil
+	}
+	m.savedRunning[s.Name] = true
+	m.savedTried[s.Name] = time.Now()
+	multi := scraper.NewMultiScraper(scrapers...)
+	return func() tea.Msg {
+		q, err := scraper.ParseQuery(s.Query)
+		if err != nil {
+			return savedSearchMsg{name: s.Name, err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		results, statuses, err := multi.Search(ctx, q.Text)
+		if err != nil {
+			return savedSearchMsg{name: s.Name, err: err}
+		}
+		msg := savedSearchMsg{name: s.Name}
+		for _, st := range statuses {
+			msg.partial = msg.partial || st.Err != nil
+		}
+		for _, t := range scraper.Apply(q.Filters, results, time.Now()) {
+			if keepResult(t) {
+				msg.keys = append(msg.keys, resultKey(t))
+			}
+		}
+		return msg
+	}
+}
+
+// handleSavedSearch records a background run, and points out new results
+// with the Search tab badge and a status message
+func (m *Model) handleSavedSearch(msg savedSearchMsg) {
+	delete(m.savedRunning, msg.name)
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Saved search %q: %v", msg.name, msg.err)
+		return
+	}
+	fresh := m.searchStore.Record(msg.name, msg.keys, msg.partial, time.Now())
+	_ = m.searchStore.Save() // Ignore error, it's just persistence
+	switch len(fresh) {
+	case 0:
+	case 1:
+		m.statusMsg = fmt.Sprintf("Saved search %q: 1 new result (W to view)", msg.name)
+	default:
+		m.statusMsg = fmt.Sprintf("Saved search %q: %d new results (W to view)", msg.name, len(fresh))
+	}
+}
+
+// openSavedSearches opens the saved search list. With naming set, it
+// starts by asking for a name to save the last search under.
+func (m Model) openSavedSearches(naming bool) (tea.Model, tea.Cmd) {
+	if naming && m.lastQuery == "" {
+		m.statusMsg = "Nothing to save: run a search first"
+		return m, handled()
+	}
+	m.showSaved = true
+	m.savedCursor = min(m.savedCursor, max(len(m.cfg.Searches)-1, 0))
+	m.savedNaming = naming
+	m.searchInput.Blur()
+	if !naming {
+		return m, handled()
+	}
+	name := m.lastQuery
+	if q, err := scraper.ParseQuery(m.lastQuery); err == nil {
+		name = q.Text
+	}
+	m.savedInput = textinput.New()
+	m.savedInput.Placeholder = "Name"
+	m.savedInput.CharLimit = 64
+	m.savedInput.Width = 40
+	m.savedInput.SetValue(name)
+	m.savedInput.Focus()
+	return m, textinput.Blink
+}
+
+// handleSavedSearchesKey handles keys in the saved search list
+func (m Model) handleSavedSearchesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.savedNaming {
+		switch key {
+		case "esc":
+			m.savedNaming = false
+			return m, handled()
+		case "enter":
+			name := strings.TrimSpace(m.savedInput.Value())
+			if name == "" {
+				return m, handled()
+			}
+			if slices.ContainsFunc(m.cfg.Searches, func(s config.SavedSearch) bool { return s.Name == name }) {
+				m.statusMsg = "A saved search is already called " + name
+				return m, handled()
+			}
+			m.cfg.Searches = append(m.cfg.Searches, config.SavedSearch{Name: name, Query: m.lastQuery, Enabled: true})
+			m.saveSavedSearches()
+			m.savedNaming = false
+			m.savedCursor = len(m.cfg.Searches) - 1
+			m.statusMsg = fmt.Sprintf("Saved %q, re-run every %s", name, formatInterval(searches.DefaultInterval))
+			return m, handled()
+		}
+		var cmd tea.Cmd
+		m.savedInput, cmd = m.savedInput.Update(msg)
+		if cmd == nil {
+			cmd = handled()
+		}
+		return m, cmd
+	}
+
+	switch key {
+	case "esc", "q", "W":
+		m.showSaved = false
+	case "up", "k":
+		if m.savedCursor > 0 {
+			m.savedCursor--
+		}
+	case "down", "j":
+		if m.savedCursor < len(m.cfg.Searches)-1 {
+			m.savedCursor++
+		}
+	}
+	if m.savedCursor >= len(m.cfg.Searches) {
+		return m, handled()
+	}
+	s := &m.cfg.Searches[m.savedCursor]
+
+	switch key {
+	case "enter": // Run it now and show the results
+		m.showSaved = false
+		m.activeTab = tabSearch
+		m.searchStore.MarkViewed(s.Name)
+		_ = m.searchStore.Save()
+		m.searchInput.SetValue(s.Query)
+		cmd := m.startSearch(s.Query)
+		return m, cmd
+	case "r": // Re-run in the background
+		if !m.canSearchInBackground() {
+			m.statusMsg = "VPN required! Press V to connect"
+			return m, handled()
+		}
+		if cmd := m.runSavedSearch(*s); cmd != nil {
+			m.statusMsg = fmt.Sprintf("Running %q...", s.Name)
+			return m, cmd
+		}
+		m.statusMsg = "No sources enabled"
+	case " ", "space":
+		s.Enabled = !s.Enabled
+		m.saveSavedSearches()
+	case "x", "delete":
+		name := s.Name
+		m.cfg.Searches = slices.Delete(m.cfg.Searches, m.savedCursor, m.savedCursor+1)
+		m.savedCursor = min(m.savedCursor, max(len(m.cfg.Searches)-1, 0))
+		m.searchStore.Forget(name)
+		_ = m.searchStore.Save()
+		m.saveSavedSearches()
+		m.statusMsg = "Deleted saved search " + name
+	}
+	return m, handled()
+}
+
+// saveSavedSearches persists the saved search list
+func (m Model) saveSavedSearches() {
+	_ = config.Save(m.cfg) // Ignore error, it's just persistence
+}
+
+// formatInterval formats a re-run interval: "45m", "2h", "1h30m"
+func formatInterval(d time.Duration) string {
+	h, mins := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, mins)
+	}
+}
+
+// renderSavedSearches renders the saved search list
+func (m Model) renderSavedSearches() string {
+	styles := GetStyles()
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.CurrentPalette.Accent)).
+		Background(lipgloss.Color(theme.CurrentPalette.BG)).
+		Padding(1, 2).
+		Width(80)
+
+	var content strings.Builder
+	content.WriteString(styles.Title.Render("Saved Searches"))
+	content.WriteString("\n\n")
+
+	if m.savedNaming {
+		content.WriteString(styles.Muted.Render("Save " + TruncateString(m.lastQuery, 60) + " as:"))
+		content.WriteString("\n")
+		content.WriteString(m.savedInput.View())
+		content.WriteString("\n\n")
+	}
+
+	if len(m.cfg.Searches) == 0 && !m.savedNaming {
+		content.WriteString(styles.Muted.Render("No saved searches. Search, then press w to save it."))
+		content.WriteString("\n")
+	}
+	now := time.Now()
+	for i, s := range m.cfg.Searches {
+		schedule := "every " + formatInterval(searches.Interval(s))
+		if !s.Enabled {
+			schedule = "paused"
+		}
+		last := "never run"
+		if at := m.searchStore.LastRun(s.Name); !at.IsZero() {
+			last = "ran " + formatAge(now.Sub(at)) + " ago"
+		}
+		if m.savedRunning[s.Name] {
+			last = "running..."
+		}
+		line := fmt.Sprintf("%s  %s  %s  %s",
+			PadRight(TruncateString(s.Name, 20), 20),
+			PadRight(TruncateString(s.Query, 24), 24),
+			PadRight(schedule, 10),
+			last)
+		if n := m.searchStore.NewCount(s.Name); n > 0 {
+			line += styles.HealthGood.Render(fmt.Sprintf("  %d new", n))
+		}
+
+		switch {
+		case i == m.savedCursor && !m.savedNaming:
+			content.WriteString(styles.TableSelected.Render("› " + line))
+		case !s.Enabled:
+			content.WriteString(styles.Muted.Render("  " + line))
+		default:
+			content.WriteString(styles.TableRow.Render("  " + line))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	if m.savedNaming {
+		content.WriteString(styles.Muted.Render("[enter]Save [esc]Cancel"))
+	} else {
+		content.WriteString(styles.Muted.Render("[enter]Run & view [r]Run in background [space]Schedule on/off [x]Delete [esc]Close"))
+	}
+	return modalStyle.Render(content.String())
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/seeding"
+)
+
+// seedingMsg reports the actions taken by the seeding rule engine
+type seedingMsg struct {
+	done  []string // e.g. "Stopped Some.Torrent"
+	acted []string // seedingActed keys of what succeeded
+	err   error
+}
+
+// enforceSeeding pushes share limits for seeding rules qBittorrent can
+// enforce and acts on torrents that reached an app-enforced goal. Each
+// torrent is only acted on once per session, so a torrent the user resumes
+// after its goal is left alone; failed actions are retried on the next
+// refresh.
+func (m *Model) enforceSeeding() tea.Cmd {
+	if len(m.cfg.Seeding) == 0 || m.seedingBusy {
+		return nil
+	}
+	libraries := []string{m.cfg.Plex.MovieLibrary, m.cfg.Plex.TVLibrary}
+	limits, decisions := seeding.Plan(m.cfg.Seeding, m.allTorrents(), libraries, time.Now())
+
+	if m.seedingActed == nil {
+		m.seedingActed = make(map[string]bool)
+	}
+	var pendingLimits []seeding.Limit
+	for _, l := range limits {
+		var hashes []string
+		for _, h := range l.Hashes {
+			if !m.seedingActed["limit:"+h] {
+				hashes = append(hashes, h)
+			}
+		}
+		if len(hashes) > 0 {
+			l.Hashes = hashes
+			pendingLimits = append(pendingLimits, l)
+		}
+	}
+	var pending []seeding.Decision
+	for _, d := range decisions {
+		if !m.seedingActed[d.Hash] {
+			pending = append(pending, d)
+		}
+	}
+	if len(pendingLimits) == 0 && len(pending) == 0 {
+		return nil
+	}
+
+	m.seedingBusy = true
+	client := m.qbitClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var msg seedingMsg
+		for _, l := range pendingLimits {
+			if err := client.SetShareLimits(ctx, l.Hashes, l.Ratio, l.SeedingMinutes, qbit.LimitActionStop); err != nil {
+				msg.err = err
+				continue
+			}
+			for _, h := range l.Hashes {
+				msg.acted = append(msg.acted, "limit:"+h)
+			}
+		}
+		for _, d := range pending {
+			var err error
+			verb := "Stopped"
+			switch d.Action {
+			case config.SeedingActionRemove:
+				verb = "Removed"
+				err = client.Delete(ctx, []string{d.Hash}, false)
+			case config.SeedingActionRemoveFiles:
+				verb = "Deleted"
+				err = client.Delete(ctx, []string{d.Hash}, true)
+			default:
+				err = client.Pause(ctx, d.Hash)
+			}
+			if err != nil {
+				msg.err = err
+				continue
+			}
+			msg.acted = append(msg.acted, d.Hash)
+			msg.done = append(msg.done, verb+" "+TruncateString(d.Name, 30))
+		}
+		return msg
+	}
+}
+
+// status formats the engine's result for the status bar
+func (msg seedingMsg) status() string {
+	if len(msg.done) == 0 && msg.err == nil {
+		return ""
+	}
+	s := "Seeding goal reached: " + strings.Join(msg.done, ", ")
+	if len(msg.done) > 2 {
+		s = fmt.Sprintf("Seeding goals reached: %s and %d more", strings.Join(msg.done[:2], ", "), len(msg.done)-2)
+	}
+	if msg.err != nil {
+		if len(msg.done) == 0 {
+			return fmt.Sprintf("Seeding rules failed: %v", msg.err)
+		}
+		s += fmt.Sprintf(" (%v)", msg.err)
+	}
+	return s
+}
+
+// seedingGoal formats a torrent's progress toward its seeding goal for
+// the Completed tab ("-" without a rule)
+func (m Model) seedingGoal(t qbit.TorrentInfo) string {
+	r, ok := seeding.Find(m.cfg.Seeding, t)
+	if !ok || !seeding.HasGoal(r) {
+		return "-"
+	}
+	p := seeding.Progress(r, t, time.Now())
+	if p >= 1 {
+		return "done"
+	}
+	return fmt.Sprintf("%.0f%%", p*100)
+}
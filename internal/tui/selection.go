@@ -0,0 +1,347 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+)
+
+// bulkActionMsg reports the combined result of an action on several torrents
+type bulkActionMsg struct {
+	action string
+	name   string // Torrent name when only one was targeted
+	done   int
+	failed int
+	err    error          // Last error, if any
+	moved  []movedTorrent // Torrents moved to Plex, for the pipeline
+}
+
+// movedTorrent is a torrent a bulk move put into a library
+type movedTorrent struct {
+	hash, name, dest string
+}
+
+// status formats the result for the status bar, e.g. "Paused 14, 1 failed"
+func (msg bulkActionMsg) status() string {
+	if msg.done+msg.failed == 1 {
+		if msg.err != nil {
+			return fmt.Sprintf("%s failed: %v", msg.action, msg.err)
+		}
+		if msg.failed == 0 {
+			return fmt.Sprintf("%s: %s", msg.action, TruncateString(msg.name, 30))
+		}
+	}
+	s := fmt.Sprintf("%s %d", msg.action, msg.done)
+	if msg.failed > 0 {
+		s += fmt.Sprintf(", %d failed", msg.failed)
+	}
+	if msg.err != nil {
+		s += fmt.Sprintf(" (%v)", msg.err)
+	}
+	return s
+}
+
+// visibleTorrents returns the (filtered, sorted) list shown in the active tab
+func (m Model) visibleTorrents() []qbit.TorrentInfo {
+	switch m.activeTab {
+	case tabDownloads:
+		return m.downloading
+	case tabCompleted:
+		return m.completed
+	}
+	return nil
+}
+
+// visualRange returns the index range covered by visual selection
+func (m Model) visualRange() (lo, hi int, ok bool) {
+	if m.visualAnchor == "" {
+		return 0, 0, false
+	}
+	anchor, found := findTorrentByHash(m.visibleTorrents(), m.visualAnchor)
+	if !found {
+		return 0, 0, false
+	}
+	return min(anchor, m.dlCursor), max(anchor, m.dlCursor), true
+}
+
+// isMarked reports whether the torrent at idx in the visible list is selected
+func (m Model) isMarked(idx int, t qbit.TorrentInfo) bool {
+	if m.marked[t.Hash] {
+		return true
+	}
+	lo, hi, ok := m.visualRange()
+	return ok && idx >= lo && idx <= hi
+}
+
+// selectionCount returns how many visible torrents are selected
+func (m Model) selectionCount() int {
+	n := 0
+	for i, t := range m.visibleTorrents() {
+		if m.isMarked(i, t) {
+			n++
+		}
+	}
+	return n
+}
+
+// targetTorrents returns the torrents an action applies to: the selection
+// in display order, or the torrent under the cursor if nothing is selected.
+// The detail pane always targets just the torrent it shows.
+func (m Model) targetTorrents() []qbit.TorrentInfo {
+	var targets []qbit.TorrentInfo
+	if m.detailOpen() {
+		if t, ok := m.selectedTorrent(); ok {
+			targets = append(targets, t)
+		}
+		return targets
+	}
+	for i, t := range m.visibleTorrents() {
+		if m.isMarked(i, t) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		if t, ok := m.selectedTorrent(); ok {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// toggleMark marks or unmarks the torrent under the cursor and moves down
+func (m *Model) toggleMark() {
+	t, ok := m.selectedTorrent()
+	if !ok {
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	if m.marked[t.Hash] {
+		delete(m.marked, t.Hash)
+	} else {
+		m.marked[t.Hash] = true
+	}
+	if m.dlCursor < len(m.visibleTorrents())-1 {
+		m.dlCursor++
+	}
+}
+
+// toggleVisual starts range selection at the cursor, or ends it and keeps
+// the range marked
+func (m *Model) toggleVisual() {
+	if m.visualAnchor != "" {
+		m.commitVisual()
+		return
+	}
+	if t, ok := m.selectedTorrent(); ok {
+		m.visualAnchor = t.Hash
+	}
+}
+
+// commitVisual turns the visual range into regular marks
+func (m *Model) commitVisual() {
+	lo, hi, ok := m.visualRange()
+	m.visualAnchor = ""
+	if !ok {
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	for _, t := range m.visibleTorrents()[lo : hi+1] {
+		m.marked[t.Hash] = true
+	}
+}
+
+// toggleSelectAll marks every visible torrent, or clears the selection if
+// they are all marked already
+func (m *Model) toggleSelectAll() {
+	m.commitVisual()
+	visible := m.visibleTorrents()
+	if len(visible) > 0 && m.selectionCount() == len(visible) {
+		m.clearSelection()
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	for _, t := range visible {
+		m.marked[t.Hash] = true
+	}
+}
+
+// clearSelection drops all marks and leaves visual mode
+func (m *Model) clearSelection() {
+	m.marked = nil
+	m.visualAnchor = ""
+}
+
+// targetHashes returns the hashes and a display name for the action targets
+func targetHashes(targets []qbit.TorrentInfo) ([]string, string) {
+	hashes := make([]string, len(targets))
+	for i, t := range targets {
+		hashes[i] = t.Hash
+	}
+	name := fmt.Sprintf("%d torrents", len(targets))
+	if len(targets) == 1 {
+		name = targets[0].Name
+	}
+	return hashes, name
+}
+
+// bulkAction applies fn to all targets in one request
+func (m Model) bulkAction(action string, targets []qbit.TorrentInfo, fn func(ctx context.Context, hashes []string) error) tea.Cmd {
+	if len(targets) == 0 {
+		return nil
+	}
+	hashes, name := targetHashes(targets)
+	return m.bulkHashAction(action, hashes, name, fn)
+}
+
+// bulkHashAction applies fn to hashes in one request. Torrents that have
+// disappeared from qBittorrent since the last refresh are counted as failed.
+func (m Model) bulkHashAction(action string, hashes []string, name string, fn func(ctx context.Context, hashes []string) error) tea.Cmd {
+	client := m.qbitClient
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := bulkActionMsg{action: action, name: name}
+		present := hashes
+		if infos, err := client.GetTorrentsByHash(ctx, hashes); err == nil {
+			present = present[:0:0]
+			for _, t := range infos {
+				present = append(present, t.Hash)
+			}
+			msg.failed = len(hashes) - len(present)
+		}
+		if len(present) == 0 {
+			return msg
+		}
+
+		if err := fn(ctx, present); err != nil {
+			msg.failed += len(present)
+			msg.err = err
+		} else {
+			msg.done = len(present)
+		}
+		return msg
+	}
+}
+
+// togglePauseTorrents resumes the targets if all are paused, otherwise pauses them
+func (m Model) togglePauseTorrents() tea.Cmd {
+	targets := m.targetTorrents()
+	allPaused := len(targets) > 0
+	for _, t := range targets {
+		if !strings.Contains(t.State, "paused") {
+			allPaused = false
+		}
+	}
+
+	client := m.qbitClient
+	if allPaused {
+		return m.bulkAction("Resumed", targets, func(ctx context.Context, hashes []string) error {
+			return client.Resume(ctx, hashes...)
+		})
+	}
+	return m.bulkAction("Paused", targets, func(ctx context.Context, hashes []string) error {
+		return client.Pause(ctx, hashes...)
+	})
+}
+
+// requestDelete removes the targets, asking first when there are several
+// or their files go too
+func (m Model) requestDelete(deleteFiles bool) (tea.Model, tea.Cmd) {
+	targets := m.targetTorrents()
+	if len(targets) == 1 && !deleteFiles {
+		cmd := m.deleteTorrents(targets, false)
+		m.clearSelection()
+		return m, cmd
+	}
+
+	what := fmt.Sprintf("%d torrents", len(targets))
+	if len(targets) == 1 {
+		what = TruncateString(targets[0].Name, 40)
+	}
+	if deleteFiles {
+		what += " and their files"
+	}
+	m.confirmingDelete = true
+	m.deleteFiles = deleteFiles
+	m.deleteTargets = targets
+	m.statusMsg = fmt.Sprintf("Delete %s? (y/n)", what)
+	return m, handled()
+}
+
+// handleDeleteConfirmKey deletes on y, and cancels on any other key
+func (m Model) handleDeleteConfirmKey(key string) (tea.Model, tea.Cmd) {
+	targets := m.deleteTargets
+	m.confirmingDelete = false
+	m.deleteTargets = nil
+	if key != "y" && key != "Y" {
+		m.statusMsg = "Delete cancelled"
+		return m, handled()
+	}
+	cmd := m.deleteTorrents(targets, m.deleteFiles)
+	m.clearSelection()
+	return m, cmd
+}
+
+// deleteTorrents removes torrents, optionally with their files
+func (m Model) deleteTorrents(targets []qbit.TorrentInfo, deleteFiles bool) tea.Cmd {
+	client := m.qbitClient
+	action := "Removed"
+	if deleteFiles {
+		action = "Deleted"
+	}
+	return m.bulkAction(action, targets, func(ctx context.Context, hashes []string) error {
+		return client.Delete(ctx, hashes, deleteFiles)
+	})
+}
+
+// recheckTorrents forces a hash check of the targets
+func (m Model) recheckTorrents() tea.Cmd {
+	client := m.qbitClient
+	return m.bulkAction("Rechecking", m.targetTorrents(), func(ctx context.Context, hashes []string) error {
+		return client.Recheck(ctx, hashes...)
+	})
+}
+
+// reannounceTorrents forces the targets to reannounce to their trackers
+func (m Model) reannounceTorrents() tea.Cmd {
+	client := m.qbitClient
+	return m.bulkAction("Reannounced", m.targetTorrents(), func(ctx context.Context, hashes []string) error {
+		return client.Reannounce(ctx, hashes...)
+	})
+}
+
+// moveTorrentsToPlex moves every selected completed torrent to the Plex
+// libraries using auto-detection, one after another, without the modal
+func (m Model) moveTorrentsToPlex() tea.Cmd {
+	targets := m.targetTorrents()
+	mover := m.seedingMover()
+	_, name := targetHashes(targets)
+
+	return func() tea.Msg {
+		msg := bulkActionMsg{action: "Moved to Plex", name: name}
+		for _, t := range targets {
+			sourcePath, detection := detectForMove(t)
+			result, err := mover.MoveToLibraryWithProgress(context.Background(), sourcePath, detection, false, nil)
+			if err != nil {
+				msg.failed++
+				msg.err = err
+				continue
+			}
+			msg.done++
+			msg.moved = append(msg.moved, movedTorrent{hash: t.Hash, name: t.Name, dest: result.DestinationPath})
+		}
+		return msg
+	}
+}
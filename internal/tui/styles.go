@@ -2,10 +2,9 @@ package tui
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
 	"github.com/litescript/ls-torrent-tui/internal/theme"
 	"github.com/mattn/go-runewidth"
 )
@@ -111,7 +110,11 @@ func formatETA(amountLeft int64, dlSpeed int64) string {
 		return "∞"
 	}
 
-	seconds := amountLeft / dlSpeed
+	return formatDuration(amountLeft / dlSpeed)
+}
+
+// formatDuration formats a number of seconds compactly (max 7 chars)
+func formatDuration(seconds int64) string {
 	if seconds < 60 {
 		return fmt.Sprintf("%ds", seconds)
 	}
@@ -131,15 +134,8 @@ func formatETA(amountLeft int64, dlSpeed int64) string {
 	return fmt.Sprintf("%dd%dh", days, hours)
 }
 
-// infohashRegex matches the btih (BitTorrent InfoHash) in a magnet link
-var infohashRegex = regexp.MustCompile(`btih:([a-fA-F0-9]{40}|[a-zA-Z2-7]{32})`)
-
-// ExtractInfohash extracts the infohash from a magnet link.
-// Returns empty string if not found.
+// ExtractInfohash extracts the infohash from a magnet link as lowercase
+// hex (base32 hashes are converted). Returns empty string if not found.
 func ExtractInfohash(magnet string) string {
-	matches := infohashRegex.FindStringSubmatch(magnet)
-	if len(matches) >= 2 {
-		return strings.ToLower(matches[1])
-	}
-	return ""
+	return scraper.InfohashFromMagnet(magnet)
 }
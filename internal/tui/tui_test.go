@@ -1,7 +1,1028 @@
 package tui
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/litescript/ls-torrent-tui/internal/config"
+	"github.com/litescript/ls-torrent-tui/internal/pipeline"
+	"github.com/litescript/ls-torrent-tui/internal/plex"
+	"github.com/litescript/ls-torrent-tui/internal/qbit"
+	"github.com/litescript/ls-torrent-tui/internal/scraper"
+	"github.com/litescript/ls-torrent-tui/internal/searches"
+)
 
 func TestPackageCompiles(t *testing.T) {
 	// placeholder
 }
+
+func TestClassifyAddInput(t *testing.T) {
+	home, _ := os.UserHomeDir()
+
+	tests := []struct {
+		input string
+		kind  addInputKind
+		value string
+	}{
+		{"", addInputInvalid, ""},
+		{"  magnet:?xt=urn:btih:abc  ", addInputMagnet, "magnet:?xt=urn:btih:abc"},
+		{"https://example.local/a.torrent", addInputURL, "https://example.local/a.torrent"},
+		{"HTTP://example.local/a.torrent", addInputURL, "HTTP://example.local/a.torrent"},
+		{"/tmp/a.torrent", addInputFile, "/tmp/a.torrent"},
+		{"~/a.torrent", addInputFile, filepath.Join(home, "a.torrent")},
+	}
+
+	for _, tt := range tests {
+		kind, value := classifyAddInput(tt.input)
+		if kind != tt.kind || value != tt.value {
+			t.Errorf("classifyAddInput(%q) = (%d, %q), want (%d, %q)", tt.input, kind, value, tt.kind, tt.value)
+		}
+	}
+}
+
+func TestMagnetDisplayName(t *testing.T) {
+	got := magnetDisplayName("magnet:?xt=urn:btih:abc&dn=Some+Linux%20ISO&tr=udp")
+	if got != "Some Linux ISO" {
+		t.Errorf("got %q", got)
+	}
+	if got := magnetDisplayName("magnet:?xt=urn:btih:abc"); got != "" {
+		t.Errorf("expected empty name, got %q", got)
+	}
+}
+
+func TestAddOptionsFromDefaults(t *testing.T) {
+	d := config.AddDefaults{
+		Category:         "movies",
+		Sequential:       true,
+		DownloadLimitKiB: 512,
+		UploadLimitKiB:   64,
+	}
+
+	opts := addOptionsFromDefaults(d, "/downloads")
+	if opts.SavePath != "/downloads" {
+		t.Errorf("SavePath = %q, want fallback", opts.SavePath)
+	}
+	if opts.Category != "movies" || !opts.SequentialDownload {
+		t.Errorf("defaults not carried over: %+v", opts)
+	}
+	if opts.DlLimit != 512*1024 || opts.UpLimit != 64*1024 {
+		t.Errorf("limits not converted to bytes/s: dl=%d ul=%d", opts.DlLimit, opts.UpLimit)
+	}
+}
+
+func TestAddOptionsKeepTheirResult(t *testing.T) {
+	m := Model{qbitClient: qbit.NewClient("127.0.0.1", 1, "", "")} // Nothing listens there
+	m.results = []scraper.Torrent{{Name: "Wanted", Magnet: "magnet:?xt=urn:btih:aaa"}}
+	updated, _ := m.openAddOptionsModal()
+	m = updated.(Model)
+
+	// A search finishing while the modal is open replaces the results
+	m.results = []scraper.Torrent{{Name: "Other", Magnet: "magnet:?xt=urn:btih:bbb"}}
+	updated, cmd := m.handleAddOptionsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.showAddOptions || cmd == nil {
+		t.Fatal("enter didn't add the torrent")
+	}
+	if msg, ok := cmd().(torrentAddedMsg); !ok || msg.name != "Wanted" {
+		t.Errorf("added %+v, want the result the modal was opened for", msg)
+	}
+}
+
+func TestBuildFilterEntries(t *testing.T) {
+	torrents := []qbit.TorrentInfo{
+		{Name: "a", Category: "movies", Tags: "hd, keep"},
+		{Name: "b", Category: "movies"},
+		{Name: "c", Category: "linux-isos", Tags: "keep"},
+		{Name: "d"},
+	}
+	categories := map[string]qbit.Category{"tv": {Name: "tv"}, "movies": {Name: "movies"}}
+
+	entries := buildFilterEntries(torrents, categories, []string{"old"})
+
+	want := []struct {
+		label string
+		count int
+	}{
+		{"All", 4},
+		{"Uncategorized", 1},
+		{"linux-isos", 1},
+		{"movies", 2},
+		{"tv", 0},
+		{"#hd", 1},
+		{"#keep", 2},
+		{"#old", 0},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].filter.label() != w.label || entries[i].count != w.count {
+			t.Errorf("entry %d = %s (%d), want %s (%d)", i, entries[i].filter.label(), entries[i].count, w.label, w.count)
+		}
+	}
+
+	keep := filterTorrents(torrents, torrentFilter{kind: filterTag, value: "keep"})
+	if len(keep) != 2 || keep[0].Name != "a" || keep[1].Name != "c" {
+		t.Errorf("tag filter returned %+v", keep)
+	}
+}
+
+func TestApplyTorrentFilterDropsStaleFilter(t *testing.T) {
+	m := Model{
+		dlAll:  []qbit.TorrentInfo{{Name: "a", Category: "tv"}},
+		filter: torrentFilter{kind: filterCategory, value: "gone"},
+	}
+	m.applyTorrentFilter()
+	if m.filter.kind != filterAll || len(m.downloading) != 1 {
+		t.Errorf("stale filter kept: filter=%+v downloading=%d", m.filter, len(m.downloading))
+	}
+}
+
+func TestBulkActionStatus(t *testing.T) {
+	tests := []struct {
+		msg  bulkActionMsg
+		want string
+	}{
+		{bulkActionMsg{action: "Paused", done: 14, failed: 1}, "Paused 14, 1 failed"},
+		{bulkActionMsg{action: "Paused", done: 3}, "Paused 3"},
+		{bulkActionMsg{action: "Removed", name: "ubuntu.iso", done: 1}, "Removed: ubuntu.iso"},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.status(); got != tt.want {
+			t.Errorf("status() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSelectionTargets(t *testing.T) {
+	m := Model{
+		activeTab:   tabDownloads,
+		downloading: []qbit.TorrentInfo{{Hash: "a"}, {Hash: "b"}, {Hash: "c"}, {Hash: "d"}},
+	}
+
+	// Nothing marked: the cursor torrent is the target
+	m.dlCursor = 2
+	if got := m.targetTorrents(); len(got) != 1 || got[0].Hash != "c" {
+		t.Fatalf("cursor target = %+v", got)
+	}
+
+	// Visual range from b down to d, plus a space-marked a
+	m.dlCursor = 1
+	m.toggleVisual()
+	m.dlCursor = 3
+	m.marked = map[string]bool{"a": true}
+	if got := m.selectionCount(); got != 4 {
+		t.Fatalf("selectionCount = %d, want 4", got)
+	}
+	m.toggleVisual()
+	if m.visualAnchor != "" || len(m.marked) != 4 {
+		t.Fatalf("visual range not committed: anchor=%q marked=%v", m.visualAnchor, m.marked)
+	}
+
+	// Select-all toggles back to nothing when everything is marked
+	m.toggleSelectAll()
+	if m.selectionCount() != 0 {
+		t.Errorf("toggleSelectAll did not clear a full selection")
+	}
+}
+
+func TestDeleteConfirm(t *testing.T) {
+	m := Model{
+		activeTab:   tabDownloads,
+		downloading: []qbit.TorrentInfo{{Hash: "a", Name: "A"}, {Hash: "b", Name: "B"}, {Hash: "c", Name: "C"}},
+	}
+
+	// One torrent without its files goes at once
+	updated, cmd := m.requestDelete(false)
+	if m = updated.(Model); m.confirmingDelete || cmd == nil {
+		t.Fatal("single remove asked for confirmation")
+	}
+
+	// Files, or several torrents, ask first
+	updated, _ = m.requestDelete(true)
+	if m = updated.(Model); !m.confirmingDelete || !strings.Contains(m.statusMsg, "A and their files?") {
+		t.Fatalf("delete with files didn't ask: %q", m.statusMsg)
+	}
+	updated, _ = m.handleDeleteConfirmKey("n")
+	if m = updated.(Model); m.confirmingDelete || m.statusMsg != "Delete cancelled" {
+		t.Fatalf("n didn't cancel: %q", m.statusMsg)
+	}
+
+	m.toggleSelectAll()
+	updated, _ = m.requestDelete(false)
+	if m = updated.(Model); !m.confirmingDelete || len(m.deleteTargets) != 3 {
+		t.Fatalf("bulk remove: confirming=%v targets=%d", m.confirmingDelete, len(m.deleteTargets))
+	}
+	updated, cmd = m.handleDeleteConfirmKey("y")
+	if m = updated.(Model); m.confirmingDelete || cmd == nil || m.selectionCount() != 0 {
+		t.Error("y didn't delete the selection")
+	}
+}
+
+func TestBuildFileTree(t *testing.T) {
+	files := []qbit.TorrentFile{
+		{Index: 2, Name: "Show/Season 1/e02.mkv", Size: 100, Progress: 0},
+		{Index: 0, Name: "Show/Season 1/e01.mkv", Size: 300, Progress: 1},
+		{Index: 1, Name: "Show/info.nfo", Size: 0, Progress: 1, Priority: 0},
+	}
+
+	rows := buildFileTree(files)
+	want := []struct {
+		depth int
+		name  string
+		isDir bool
+		size  int64
+	}{
+		{0, "Show", true, 400},
+		{1, "Season 1", true, 400},
+		{2, "e01.mkv", false, 300},
+		{2, "e02.mkv", false, 100},
+		{1, "info.nfo", false, 0},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.depth != w.depth || r.name != w.name || r.isDir != w.isDir || r.size != w.size {
+			t.Errorf("row %d = %+v, want %+v", i, r, w)
+		}
+	}
+	if rows[1].progress != 0.75 {
+		t.Errorf("directory progress = %v, want size-weighted 0.75", rows[1].progress)
+	}
+	if rows[2].index != 0 || rows[0].index != -1 {
+		t.Errorf("file indexes not kept: %+v", rows)
+	}
+}
+
+func TestPieceBarCells(t *testing.T) {
+	pieces := []int{
+		qbit.PieceDownloaded, qbit.PieceDownloaded,
+		qbit.PieceDownloaded, qbit.PieceMissing,
+		qbit.PieceMissing, qbit.PieceDownloading,
+		qbit.PieceMissing, qbit.PieceMissing,
+	}
+	got := pieceBarCells(pieces, 4)
+	want := []int{qbit.PieceDownloaded, qbit.PieceDownloading, qbit.PieceDownloading, qbit.PieceMissing}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("cells = %v, want %v", got, want)
+		}
+	}
+
+	// More cells than pieces: each piece spreads over several cells
+	if got := pieceBarCells([]int{qbit.PieceDownloaded, qbit.PieceMissing}, 4); got[1] != qbit.PieceDownloaded || got[2] != qbit.PieceMissing {
+		t.Errorf("stretched cells = %v", got)
+	}
+}
+
+func TestStepFilePriority(t *testing.T) {
+	tests := []struct {
+		prio, delta, want int
+	}{
+		{qbit.FilePrioritySkip, 1, qbit.FilePriorityNormal},
+		{qbit.FilePriorityNormal, 1, qbit.FilePriorityHigh},
+		{qbit.FilePriorityMax, 1, qbit.FilePriorityMax},
+		{qbit.FilePriorityNormal, -1, qbit.FilePrioritySkip},
+		{qbit.FilePrioritySkip, -1, qbit.FilePrioritySkip},
+		{4, 1, qbit.FilePriorityHigh}, // Unknown levels count as the one below
+	}
+	for _, tt := range tests {
+		if got := stepFilePriority(tt.prio, tt.delta); got != tt.want {
+			t.Errorf("stepFilePriority(%d, %d) = %d, want %d", tt.prio, tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestChangeRowPriority(t *testing.T) {
+	files := []qbit.TorrentFile{
+		{Index: 0, Name: "Show/Season 1/e01.mkv", Priority: qbit.FilePriorityNormal},
+		{Index: 1, Name: "Show/Season 1/e02.mkv", Priority: qbit.FilePrioritySkip},
+		{Index: 2, Name: "Show/info.nfo", Priority: qbit.FilePriorityNormal},
+	}
+
+	// Row 1 is "Season 1": toggling skips both episodes, not the .nfo
+	indexes, prio := changeRowPriority(files, 1, true, 0)
+	if prio != qbit.FilePrioritySkip || len(indexes) != 2 {
+		t.Fatalf("toggle directory = %v, %d", indexes, prio)
+	}
+	if files[0].Priority != qbit.FilePrioritySkip || files[2].Priority != qbit.FilePriorityNormal {
+		t.Errorf("priorities after toggle = %+v", files)
+	}
+
+	// Row 4 is info.nfo
+	indexes, prio = changeRowPriority(files, 4, false, 1)
+	if prio != qbit.FilePriorityHigh || len(indexes) != 1 || indexes[0] != 2 {
+		t.Errorf("step file = %v, %d", indexes, prio)
+	}
+
+	if indexes, _ := changeRowPriority(files, 10, true, 0); indexes != nil {
+		t.Errorf("out of range cursor changed %v", indexes)
+	}
+}
+
+func TestParseLimitKiB(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"", 0, true},
+		{" 0 ", 0, true},
+		{"512", 512 * 1024, true},
+		{"-5", 0, false},
+		{"1.5", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseLimitKiB(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseLimitKiB(%q) = %d, %v", tt.in, got, err)
+		}
+	}
+	if kibString(-1) != "" || kibString(2048) != "2" {
+		t.Errorf("kibString round trip broken")
+	}
+}
+
+func TestSeedingMsgStatus(t *testing.T) {
+	tests := []struct {
+		msg  seedingMsg
+		want string
+	}{
+		{seedingMsg{}, ""},
+		{seedingMsg{done: []string{"Stopped A"}}, "Seeding goal reached: Stopped A"},
+		{seedingMsg{done: []string{"Stopped A", "Removed B", "Stopped C"}}, "Seeding goals reached: Stopped A, Removed B and 1 more"},
+		{seedingMsg{err: errors.New("boom")}, "Seeding rules failed: boom"},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.status(); got != tt.want {
+			t.Errorf("status() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSortByQueuePosition(t *testing.T) {
+	torrents := []qbit.TorrentInfo{
+		{Name: "seeding", QueuePosition: 0},
+		{Name: "third", QueuePosition: 3},
+		{Name: "first", QueuePosition: 1},
+		{Name: "forced", QueuePosition: 2, ForceStart: true},
+	}
+	sortTorrents(torrents, dlSortQueue, true)
+
+	want := []string{"first", "forced", "third", "seeding"}
+	for i, name := range want {
+		if torrents[i].Name != name {
+			t.Fatalf("order = %v, want %v", torrents, want)
+		}
+	}
+	if queueLabel(torrents[1]) != "F" || queueLabel(torrents[2]) != "3" || queueLabel(torrents[3]) != "-" {
+		t.Errorf("unexpected queue labels")
+	}
+}
+
+func TestFeedRows(t *testing.T) {
+	m := Model{cfg: config.Config{Feeds: []config.FeedConfig{
+		{Name: "a", Rules: []config.FeedRule{{Name: "r1"}, {Name: "r2"}}},
+		{Name: "b"},
+	}}}
+	want := []feedRow{{0, -1}, {0, 0}, {0, 1}, {1, -1}}
+	rows := m.feedRows()
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %v, want %v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestRuleFromInputs(t *testing.T) {
+	m := Model{cfg: config.Config{Feeds: []config.FeedConfig{{Name: "feed", Rules: []config.FeedRule{
+		{Name: "show", Include: "^Show", Episodes: "S01E01+", MinSizeMB: 100, Paused: true, Enabled: true},
+	}}}}}
+	m.feedCursor = 1
+	model, _ := m.openRuleEditor(false)
+	m = model.(Model)
+
+	r, err := m.ruleFromInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != m.cfg.Feeds[0].Rules[0] {
+		t.Errorf("round trip = %+v, want %+v", r, m.cfg.Feeds[0].Rules[0])
+	}
+
+	m.ruleInputs[ruleFieldEpisodes].SetValue("soon")
+	if _, err := m.ruleFromInputs(); err == nil {
+		t.Error("invalid episode filter accepted")
+	}
+}
+
+func TestSearchResultsStreamIn(t *testing.T) {
+	m := Model{searchID: 2, searchFresh: true, searchSortCol: 2, results: []scraper.Torrent{{Name: "old", Seeders: 1}}}
+	m.searchSources = []string{"a", "b"}
+
+	// Results of an older search are dropped
+	if cmd := m.handleSearchSource(searchSourceMsg{id: 1}); cmd != nil || len(m.results) != 1 {
+		t.Fatalf("stale message applied: %v", m.results)
+	}
+
+	m.handleSearchSource(searchSourceMsg{id: 2, result: scraper.SourceResult{
+		SourceStatus: scraper.SourceStatus{Source: "a", Count: 2},
+		Results:      []scraper.Torrent{{Name: "x", Seeders: 5}, {Name: "ad link"}},
+	}})
+	if len(m.results) != 1 || m.results[0].Name != "x" || m.mode != viewResults {
+		t.Fatalf("after first source: %v", m.results)
+	}
+
+	m.cursor = 0
+	m.handleSearchSource(searchSourceMsg{id: 2, result: scraper.SourceResult{
+		SourceStatus: scraper.SourceStatus{Source: "b", Count: 1},
+		Results:      []scraper.Torrent{{Name: "y", Seeders: 9}},
+	}})
+	if len(m.results) != 2 || m.results[m.cursor].Name != "x" {
+		t.Errorf("cursor moved off the selected result: %v, cursor %d", m.results, m.cursor)
+	}
+
+	m.handleSearchDone(searchDoneMsg{id: 2})
+	if m.searching || m.statusMsg != "Found 2 results" {
+		t.Errorf("done: searching=%v status=%q", m.searching, m.statusMsg)
+	}
+}
+
+func TestSearchResultsMergeAcrossSources(t *testing.T) {
+	const magnet = "magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567"
+	m := Model{searchID: 1, searchFresh: true, searchSortCol: 2}
+	m.searchSources = []string{"a", "b"}
+
+	m.handleSearchSource(searchSourceMsg{id: 1, result: scraper.SourceResult{
+		SourceStatus: scraper.SourceStatus{Source: "a", Count: 1},
+		Results:      []scraper.Torrent{{Name: "x", Magnet: magnet, Source: "a", Seeders: 5}},
+	}})
+	m.handleSearchSource(searchSourceMsg{id: 1, result: scraper.SourceResult{
+		SourceStatus: scraper.SourceStatus{Source: "b", Count: 1},
+		Results:      []scraper.Torrent{{Name: "x.mkv", Magnet: magnet, Source: "b", Seeders: 8}},
+	}})
+
+	if len(m.results) != 1 {
+		t.Fatalf("expected one merged result, got %v", m.results)
+	}
+	r := m.results[0]
+	if r.Seeders != 8 || len(r.Sources()) != 2 {
+		t.Errorf("merged result: seeders %d, sources %v", r.Seeders, r.Sources())
+	}
+	if key := resultKey(r); key != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("resultKey = %q", key)
+	}
+}
+
+func TestNewSourceScraperProfile(t *testing.T) {
+	src := config.SourceConfig{Name: "site", URL: "https://site.local"}
+	if _, err := newSourceScraper(src); err != nil {
+		t.Fatalf("source without profile: %v", err)
+	}
+
+	// An invalid profile is reported, and the heuristics still work
+	src.Profile = &config.ScrapeProfile{SearchURL: "https://site.local/search", Row: "tr"}
+	s, err := newSourceScraper(src)
+	if err == nil || s == nil {
+		t.Errorf("invalid profile: scraper %v, err %v", s, err)
+	}
+
+	src.Profile.SearchURL = "https://site.local/search?q={query}"
+	if _, err := newSourceScraper(src); err != nil {
+		t.Errorf("valid profile: %v", err)
+	}
+}
+
+func TestResultFilters(t *testing.T) {
+	now := time.Now()
+	m := Model{searchSortCol: 1, searchSortAsc: true}
+	m.searchAll = []scraper.Torrent{
+		{Name: "big", SizeBytes: 8 << 30, PublishedAt: now.AddDate(0, 0, -2)},
+		{Name: "small", SizeBytes: 300 << 20, PublishedAt: now.AddDate(-2, 0, 0)},
+		{Name: "mid", SizeBytes: 2 << 30},
+	}
+	m.applyResultFilters()
+	if len(m.results) != 3 || m.results[0].Name != "small" || m.results[2].Name != "big" {
+		t.Fatalf("sorted by real size: %v", m.results)
+	}
+
+	m.cursor = 2       // big
+	m.cycleAgeFilter() // 1 day: nothing
+	if len(m.results) != 0 || m.renderFilterBar() == "" {
+		t.Errorf("age ≤ 1d: %v", m.results)
+	}
+	m.cycleAgeFilter() // 7 days
+	if len(m.results) != 1 || m.results[m.cursor].Name != "big" {
+		t.Errorf("age ≤ 7d: %v, cursor %d", m.results, m.cursor)
+	}
+
+	m.removeResultFilters(scraper.KeyAge)
+	m.cycleSizeFilter() // < 1 GB
+	if len(m.results) != 1 || m.results[0].Name != "small" {
+		t.Errorf("size < 1 GB: %v", m.results)
+	}
+	m.cycleSizeFilter() // 1-5 GB
+	if len(m.results) != 1 || m.results[0].Name != "mid" {
+		t.Errorf("size 1-5 GB: %v", m.results)
+	}
+
+	m.removeResultFilters(scraper.KeySize)
+	m.searchSortCol = searchSortAge
+	m.applyResultFilters()
+	if got := m.results[0].Name + "," + m.results[1].Name + "," + m.results[2].Name; got != "big,small,mid" {
+		t.Errorf("ascending age (newest first, unknown last) = %s", got)
+	}
+}
+
+func TestQueryFiltersMergeAndToggle(t *testing.T) {
+	m := Model{}
+	m.searchAll = []scraper.Torrent{
+		{Name: "Show 1080p", SizeBytes: 3 << 30, Seeders: 40},
+		{Name: "Show 720p", SizeBytes: 1 << 30, Seeders: 4},
+		{Name: "Show 1080p CAM", SizeBytes: 2 << 30, Seeders: 90},
+	}
+	query := func(s string) {
+		t.Helper()
+		q, err := scraper.ParseQuery(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.setResultFilters(q.Filters)
+		m.applyResultFilters()
+	}
+
+	query("show seeds:>=10 -cam")
+	if len(m.results) != 1 || m.results[0].Name != "Show 1080p" {
+		t.Fatalf("seeds:>=10 -cam: %v", m.results)
+	}
+
+	// A later query replaces seeds and adds to the excluded words
+	query("show seeds:>=1 -720p")
+	if len(m.searchFilters) != 3 || len(m.results) != 1 {
+		t.Errorf("merged filters %v: %v", m.searchFilters, m.results)
+	}
+
+	m.filterCursor = 0 // -cam
+	m.toggleResultFilter()
+	if len(m.results) != 2 || !m.searchFilters[0].off {
+		t.Errorf("-cam off: %v", m.results)
+	}
+	m.filterCursor = 1 // seeds:>=1
+	m.deleteResultFilter()
+	if len(m.searchFilters) != 2 || m.searchFilters[1].cond.String() != "-720p" {
+		t.Errorf("after removing seeds: %v", m.searchFilters)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Minute: "30m",
+		5 * time.Hour:    "5h",
+		3 * day:          "3d",
+		90 * day:         "3mo",
+		800 * day:        "2y",
+	}
+	for d, want := range tests {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestResultRelease(t *testing.T) {
+	got := resultRelease(scraper.Torrent{Name: "Movie.Title.2019.PROPER.2160p.WEB-DL.DV.DDP5.1-GRP"})
+	want := "Movie Title · 2019 · 2160p WEB-DL · DV · DDP5.1 · PROPER · group GRP"
+	if got != want {
+		t.Errorf("resultRelease = %q, want %q", got, want)
+	}
+	if got := resultRelease(scraper.Torrent{Name: "Show.S01E02.720p"}); got != "Show · S01E02 · 720p" {
+		t.Errorf("episode: %q", got)
+	}
+}
+
+func TestQualityProfileRanking(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // Choosing a profile saves the sort settings
+	m := Model{qualityIdx: -1, searchSortCol: 2}
+	m.cfg.Quality = []config.QualityProfile{{
+		Name:        "1080p",
+		Resolutions: []string{"1080p"},
+		Reject:      []string{"CAM"},
+	}}
+	m.searchAll = []scraper.Torrent{
+		{Name: "Movie.2019.HDCAM", Seeders: 900},
+		{Name: "Movie.2019.720p.WEB-DL", Seeders: 300},
+		{Name: "Movie.2019.1080p.WEB-DL", Seeders: 20},
+	}
+	m.applyResultFilters()
+	if m.results[0].Name != "Movie.2019.HDCAM" || m.resultScoreReason(m.results[0]) != "" {
+		t.Fatalf("without a profile: %v", m.results)
+	}
+
+	m.cycleQualityProfile()
+	if m.searchSortCol != searchSortScore || m.searchSortAsc || m.results[0].Name != "Movie.2019.1080p.WEB-DL" {
+		t.Fatalf("ranked by profile: sort %d, %v", m.searchSortCol, m.results)
+	}
+	if last := m.results[2]; !strings.Contains(m.resultScoreReason(last), "rejected: CAM") {
+		t.Errorf("reason for %s = %q", last.Name, m.resultScoreReason(last))
+	}
+
+	m.cursor = 1
+	if cmd := m.grabBest(); cmd == nil || m.cursor != 0 {
+		t.Errorf("grabBest: cmd %v, cursor %d", cmd, m.cursor)
+	}
+
+	m.cycleQualityProfile()
+	if m.hasQualityProfile() {
+		t.Error("cycling past the last profile should turn scoring off")
+	}
+}
+
+func TestSearchHistoryRecall(t *testing.T) {
+	store, _ := searches.Load(filepath.Join(t.TempDir(), "searches.json"))
+	for _, q := range []string{"ubuntu", "the expanse", "debian"} {
+		store.Add(q)
+	}
+	m := Model{searchStore: store, searchInput: textinput.New()}
+	m.searchInput.Focus()
+	m.searchInput.SetValue("draft")
+
+	key := func(k string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "ctrl+r":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlR}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		next, _ := m.handleKeyPress(msg)
+		m = next.(Model)
+	}
+
+	key("up")
+	key("up")
+	if got := m.searchInput.Value(); got != "the expanse" {
+		t.Errorf("up twice = %q", got)
+	}
+	key("down")
+	key("down")
+	if got := m.searchInput.Value(); got != "draft" {
+		t.Errorf("down past the newest = %q, want the draft back", got)
+	}
+
+	key("ctrl+r")
+	key("e")
+	if got := m.historyMatch(); got != "debian" {
+		t.Errorf("reverse search e = %q", got)
+	}
+	key("ctrl+r")
+	key("enter")
+	if m.histSearching || m.searchInput.Value() != "the expanse" {
+		t.Errorf("after ctrl+r, enter: searching %v, input %q", m.histSearching, m.searchInput.Value())
+	}
+}
+
+func TestSavedSearchNewResults(t *testing.T) {
+	store, _ := searches.Load(filepath.Join(t.TempDir(), "searches.json"))
+	m := Model{searchStore: store, savedRunning: map[string]bool{"show": true}}
+
+	m.handleSavedSearch(savedSearchMsg{name: "show", keys: []string{"a", "b"}})
+	if m.newSavedResults() != 0 || m.savedRunning["show"] {
+		t.Fatalf("first run: %d new, running %v", m.newSavedResults(), m.savedRunning["show"])
+	}
+	m.handleSavedSearch(savedSearchMsg{name: "show", keys: []string{"a", "b", "c"}})
+	if m.newSavedResults() != 1 || !strings.Contains(m.statusMsg, "1 new result") {
+		t.Errorf("second run: %d new, status %q", m.newSavedResults(), m.statusMsg)
+	}
+	if !strings.Contains(m.renderTabBar(), "+1") {
+		t.Error("Search tab has no badge for the new result")
+	}
+}
+
+func TestPipelineReview(t *testing.T) {
+	dir := t.TempDir()
+	for _, lib := range []string{"Movies", "TV"} {
+		if err := os.Mkdir(filepath.Join(dir, lib), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := qbit.TorrentInfo{Hash: "old", Name: "Old.Movie.2001.1080p"}
+	unsure := qbit.TorrentInfo{Hash: "new", Name: "home video", ContentPath: filepath.Join(dir, "home video.mkv")}
+
+	state, _ := pipeline.Load(filepath.Join(dir, "pipeline.json"))
+	m := Model{pipeline: state}
+	m.cfg.Plex = config.PlexConfig{
+		MovieLibrary: filepath.Join(dir, "Movies"),
+		TVLibrary:    filepath.Join(dir, "TV"),
+		AutoMove:     true,
+	}
+
+	// Torrents completed before the pipeline started are left alone
+	m.compAll = []qbit.TorrentInfo{old}
+	if cmd := m.runPipeline(); cmd != nil || !state.IsStarted() {
+		t.Fatal("first run didn't only take a baseline")
+	}
+
+	m.compAll = []qbit.TorrentInfo{old, unsure}
+	cmd := m.runPipeline()
+	if cmd == nil || !m.autoMoving {
+		t.Fatal("newly completed torrent not processed")
+	}
+	if m.runPipeline() != nil {
+		t.Error("a second move started while one is running")
+	}
+	msg, ok := cmd().(autoMoveMsg)
+	if !ok || msg.status != pipeline.StatusReview {
+		t.Fatalf("unrecognized media: %+v, want review", msg)
+	}
+	if next := m.handleAutoMove(msg); next != nil {
+		t.Error("pipeline went on with nothing left to do")
+	}
+	if m.reviewCount() != 1 || !strings.Contains(m.statusMsg, "needs review") {
+		t.Errorf("review count %d, status %q", m.reviewCount(), m.statusMsg)
+	}
+
+	// A manual move takes it out of review
+	m.recordManualMove(unsure.Hash, unsure.Name, filepath.Join(dir, "Movies", "Home Video.mkv"))
+	reloaded, _ := pipeline.Load(filepath.Join(dir, "pipeline.json"))
+	if e, _ := reloaded.Get(unsure.Hash); e.Status != pipeline.StatusMoved {
+		t.Errorf("after manual move: %q", e.Status)
+	}
+	if e, _ := reloaded.Get(old.Hash); e.Status != pipeline.StatusBaseline {
+		t.Errorf("baseline lost on reload: %q", e.Status)
+	}
+}
+
+func TestPipelineKeepsSeedingData(t *testing.T) {
+	dir := t.TempDir()
+	for _, lib := range []string{"Movies", "TV"} {
+		if err := os.Mkdir(filepath.Join(dir, lib), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := filepath.Join(dir, "Alien.1979.1080p.BluRay.x264-GRP.mkv")
+	if err := os.WriteFile(src, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, _ := pipeline.Load(filepath.Join(dir, "pipeline.json"))
+	m := Model{pipeline: state}
+	m.cfg.Plex = config.PlexConfig{
+		MovieLibrary: filepath.Join(dir, "Movies"),
+		TVLibrary:    filepath.Join(dir, "TV"),
+		TransferMode: "move",
+		AutoMove:     true,
+	}
+	m.runPipeline() // Baseline
+
+	m.compAll = []qbit.TorrentInfo{{Hash: "alien", Name: "Alien.1979.1080p.BluRay.x264-GRP", ContentPath: src}}
+	cmd := m.runPipeline()
+	if cmd == nil {
+		t.Fatal("completed torrent not processed")
+	}
+	msg := cmd().(autoMoveMsg)
+	if msg.status != pipeline.StatusMoved {
+		t.Fatalf("%+v, want moved", msg)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("torrent lost its data: %v", err)
+	}
+	if _, err := os.Stat(msg.detail); err != nil {
+		t.Errorf("not in the library: %v", err)
+	}
+}
+
+func TestBulkMoveLeavesReview(t *testing.T) {
+	dir := t.TempDir()
+	for _, lib := range []string{"Movies", "TV"} {
+		if err := os.Mkdir(filepath.Join(dir, lib), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := filepath.Join(dir, "Alien.1979.1080p.BluRay.x264-GRP.mkv")
+	if err := os.WriteFile(src, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	alien := qbit.TorrentInfo{Hash: "alien", Name: "Alien.1979.1080p.BluRay.x264-GRP", ContentPath: src}
+
+	state, _ := pipeline.Load(filepath.Join(dir, "pipeline.json"))
+	state.Set(alien.Hash, alien.Name, pipeline.StatusReview, "low confidence", time.Now())
+	m := Model{pipeline: state, activeTab: tabCompleted, completed: []qbit.TorrentInfo{alien}}
+	m.cfg.Plex = config.PlexConfig{MovieLibrary: filepath.Join(dir, "Movies"), TVLibrary: filepath.Join(dir, "TV")}
+	m.marked = map[string]bool{alien.Hash: true}
+
+	msg := m.moveTorrentsToPlex()().(bulkActionMsg)
+	if msg.done != 1 {
+		t.Fatalf("bulk move: %+v", msg)
+	}
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if e, _ := m.pipeline.Get(alien.Hash); e.Status != pipeline.StatusMoved {
+		t.Errorf("after bulk move: %q, want moved", e.Status)
+	}
+}
+
+func TestPipelineSaveError(t *testing.T) {
+	dir := t.TempDir()
+	for _, lib := range []string{"Movies", "TV"} {
+		if err := os.Mkdir(filepath.Join(dir, lib), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The state file can't be written under a regular file
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := pipeline.Load(filepath.Join(blocker, "pipeline.json"))
+	m := Model{pipeline: state}
+	m.cfg.Plex = config.PlexConfig{MovieLibrary: filepath.Join(dir, "Movies"), TVLibrary: filepath.Join(dir, "TV"), AutoMove: true}
+
+	m.runPipeline() // Baseline
+	if !strings.HasPrefix(m.statusMsg, "Couldn't save pipeline state") {
+		t.Errorf("save error not shown: %q", m.statusMsg)
+	}
+	m.compAll = []qbit.TorrentInfo{{Hash: "new", Name: "Alien.1979.1080p"}}
+	if cmd := m.runPipeline(); cmd != nil || m.autoMoving {
+		t.Error("move started though it couldn't be recorded")
+	}
+	if !m.pipeline.InReview("new") {
+		t.Error("unrecorded move not left for review")
+	}
+}
+
+func TestMoveModalRelocate(t *testing.T) {
+	dir := t.TempDir()
+	downloads := filepath.Join(dir, "downloads")
+	pack := filepath.Join(downloads, "Show.S01.1080p")
+	if err := os.MkdirAll(pack, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Show.S01E01.1080p.mkv", "Show.S01E02.1080p.mkv"} {
+		if err := os.WriteFile(filepath.Join(pack, name), []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := Model{showMoveModal: true, moveHash: "abc", moveSourcePath: pack, moveCleanup: true}
+	m.cfg.Plex = config.PlexConfig{MovieLibrary: filepath.Join(dir, "Movies"), TVLibrary: filepath.Join(dir, "TV")}
+	m.moveDetection = plex.DetectionResult{Type: plex.MediaTypeTV, Title: "Show", Season: 1}
+	m.moveMediaType = plex.MediaTypeTV
+	m.moveTitleInput = textinput.New()
+	m.moveTitleInput.SetValue("Show")
+
+	updated, cmd := m.handleMoveModalKey("r")
+	m = updated.(Model)
+	if !m.moveRelocate || m.moveCleanup {
+		t.Fatalf("relocate %v cleanup %v, want relocate without cleanup", m.moveRelocate, m.moveCleanup)
+	}
+	if cmd == nil || !strings.HasPrefix(m.moveDestPreview, "Listing") {
+		t.Errorf("files not listed for the preview: %q", m.moveDestPreview)
+	}
+
+	// The preview follows qBittorrent's files, not what is on disk
+	files := []plex.TorrentFile{{Path: "Show.S01.1080p/Show.S01E01.1080p.mkv"}, {Path: "Show.S01.1080p/Show.S01E02.1080p.mkv"}}
+	updated, _ = m.Update(relocateFilesMsg{hash: "other", files: []plex.TorrentFile{{Path: "Show.S02E01.mkv"}}})
+	m = updated.(Model)
+	updated, _ = m.Update(relocateFilesMsg{hash: "abc", files: files})
+	m = updated.(Model)
+	if want := filepath.Join(dir, "TV", "Show", "Season 01"); m.moveDestPreview != want {
+		t.Errorf("preview %q, want %q", m.moveDestPreview, want)
+	}
+
+	// Cleanup only applies to copies
+	updated, _ = m.handleMoveModalKey("c")
+	m = updated.(Model)
+	if m.moveRelocate || !m.moveCleanup {
+		t.Errorf("cleanup didn't switch back to copying")
+	}
+}
+
+func TestCleanupAfterLinkAsks(t *testing.T) {
+	src := t.TempDir()
+	m := Model{showMoveModal: true, moveCleanup: true}
+	result := &plex.MoveResult{Transfer: plex.TransferHardlink, SourceDir: src, DestinationPath: "/media/Movies/Movie.mkv"}
+	updated, _ := m.Update(moveCompleteMsg{result: result})
+	m = updated.(Model)
+	if !m.moveShowCleanup || !m.moveCleanupLinked {
+		t.Fatal("cleanup of a linked source didn't ask first")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source deleted without asking: %v", err)
+	}
+	updated, _ = m.handleMoveModalKey("n")
+	m = updated.(Model)
+	if _, err := os.Stat(src); err != nil || m.moveShowCleanup {
+		t.Errorf("declined cleanup: %v, still asking %v", err, m.moveShowCleanup)
+	}
+}
+
+func TestRelocateUndoesRenames(t *testing.T) {
+	// A torrent whose second episode can't be renamed
+	files := []string{"Show.S01E01.mkv", "Show.S01E02.mkv"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Ok."))
+	})
+	mux.HandleFunc("/api/v2/torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		list := make([]qbit.TorrentFile, len(files))
+		for i, name := range files {
+			list[i] = qbit.TorrentFile{Index: i, Name: name}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	})
+	mux.HandleFunc("/api/v2/torrents/renameFile", func(w http.ResponseWriter, r *http.Request) {
+		oldPath, newPath := r.FormValue("oldPath"), r.FormValue("newPath")
+		if strings.Contains(newPath, "E02") {
+			http.Error(w, "name in use", http.StatusConflict)
+			return
+		}
+		for i := range files {
+			if files[i] == oldPath {
+				files[i] = newPath
+			}
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	m := Model{qbitClient: qbit.NewClient(host, portNum, "admin", "admin"), moveHash: "abc"}
+	m.cfg.Plex = config.PlexConfig{MovieLibrary: "/media/Movies", TVLibrary: "/media/TV"}
+	m.moveDetection = plex.DetectionResult{Type: plex.MediaTypeTV, Title: "Show", Season: 1}
+	m.moveMediaType = plex.MediaTypeTV
+	m.moveTitleInput = textinput.New()
+	m.moveTitleInput.SetValue("Show")
+
+	_, cmd := m.startRelocate()
+	msg := cmd().(relocateMsg)
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "1 applied renames undone") {
+		t.Errorf("err = %v, want the applied rename undone", msg.err)
+	}
+	if files[0] != "Show.S01E01.mkv" {
+		t.Errorf("first episode left at %q", files[0])
+	}
+}
+
+func TestMovePreviewTemplate(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "Alien.1979.2160p.BluRay.x265-GRP.mkv")
+	if err := os.WriteFile(src, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Model{showMoveModal: true, moveSourcePath: src}
+	m.cfg.Plex = config.PlexConfig{
+		MovieLibrary:  filepath.Join(dir, "Movies"),
+		TVLibrary:     filepath.Join(dir, "TV"),
+		MovieTemplate: "{title} ({year})/{title} ({year}) [{resolution}]{ext}",
+	}
+	m.moveDetection, _ = plex.Detect(src)
+	m.moveMediaType = plex.MediaTypeMovie
+	m.moveTitleInput = textinput.New()
+	m.moveTitleInput.SetValue(m.moveDetection.Title)
+	m.updateMoveDestPreview()
+	if want := filepath.Join(dir, "Movies", "Alien (1979)", "Alien (1979) [2160p].mkv"); m.moveDestPreview != want {
+		t.Errorf("preview %q, want %q", m.moveDestPreview, want)
+	}
+
+	// The preview follows the title while it's typed
+	m.moveEditing = true
+	m.moveTitleInput.Focus()
+	updated, _ := m.handleMoveModalKey("s")
+	m = updated.(Model)
+	if want := filepath.Join(dir, "Movies", "Aliens (1979)", "Aliens (1979) [2160p].mkv"); m.moveDestPreview != want {
+		t.Errorf("preview while typing %q, want %q", m.moveDestPreview, want)
+	}
+
+	for _, lib := range []string{"Movies", "TV"} {
+		if err := os.Mkdir(filepath.Join(dir, lib), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.cfg.Plex.MovieTemplate = "{title} {imdb}{ext}"
+	if msg := m.checkPlexLibraries(); !strings.Contains(msg, "movie_template") {
+		t.Errorf("invalid template not reported: %q", msg)
+	}
+}